@@ -44,7 +44,7 @@ type AdminUnsetLightResponse struct {
 func (cfg *apiConfig) adminLightCreateHandler(w http.ResponseWriter, r *http.Request) {
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -52,7 +52,7 @@ func (cfg *apiConfig) adminLightCreateHandler(w http.ResponseWriter, r *http.Req
 	var createRequest AdminLightCreateRequest
 	err = json.NewDecoder(r.Body).Decode(&createRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -60,12 +60,12 @@ func (cfg *apiConfig) adminLightCreateHandler(w http.ResponseWriter, r *http.Req
 
 	// checking body properties
 	if createRequest.Name == "" {
-		cfg.sl.Debug("Request midding name property")
+		cfg.sl.DebugContext(r.Context(), "Request midding name property")
 		respondWithError(errors.New("no name property provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	if createRequest.Description == "" {
-		cfg.sl.Debug("Request midding description property")
+		cfg.sl.DebugContext(r.Context(), "Request midding description property")
 		respondWithError(errors.New("no description property provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -77,12 +77,12 @@ func (cfg *apiConfig) adminLightCreateHandler(w http.ResponseWriter, r *http.Req
 	}
 	lightRecord, err := cfg.db.CreateLightNeed(r.Context(), createParams)
 	if err != nil {
-		cfg.sl.Debug("Could not create record in light_needs", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not create record in light_needs", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully created light need", "admin id", requestUserID, "light need id", lightRecord.ID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully created light need", "admin id", requestUserID, "light need id", lightRecord.ID)
 	respondWithJSON(http.StatusCreated, lightRecord, w, cfg.sl)
 }
 
@@ -90,19 +90,19 @@ func (cfg *apiConfig) adminLightCreateHandler(w http.ResponseWriter, r *http.Req
 func (cfg *apiConfig) adminLightViewHandler(w http.ResponseWriter, r *http.Request) {
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
 	lightRecords, err := cfg.db.GetAllLightNeedsOrderedByCreated(r.Context())
 	if err != nil {
-		cfg.sl.Debug("Could not get light_needs record", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get light_needs record", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully completed request", "admin id", requestUserID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully completed request", "admin id", requestUserID)
 	respondWithJSON(http.StatusOK, lightRecords, w, cfg.sl)
 }
 
@@ -111,7 +111,7 @@ func (cfg *apiConfig) adminLightUpdateHandler(w http.ResponseWriter, r *http.Req
 	lightIDStr := r.PathValue("lightID")
 	lightID, err := uuid.Parse(lightIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse lightID from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse lightID from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -119,7 +119,7 @@ func (cfg *apiConfig) adminLightUpdateHandler(w http.ResponseWriter, r *http.Req
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -127,7 +127,7 @@ func (cfg *apiConfig) adminLightUpdateHandler(w http.ResponseWriter, r *http.Req
 	var updateRequest AdminLightUpdateRequest
 	err = json.NewDecoder(r.Body).Decode(&updateRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -135,12 +135,12 @@ func (cfg *apiConfig) adminLightUpdateHandler(w http.ResponseWriter, r *http.Req
 
 	// checking body properties
 	if updateRequest.Name == "" {
-		cfg.sl.Debug("Request body missing name property")
+		cfg.sl.DebugContext(r.Context(), "Request body missing name property")
 		respondWithError(errors.New("no name provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	if updateRequest.Description == "" {
-		cfg.sl.Debug("Request body missing description property")
+		cfg.sl.DebugContext(r.Context(), "Request body missing description property")
 		respondWithError(errors.New("no description provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -153,12 +153,12 @@ func (cfg *apiConfig) adminLightUpdateHandler(w http.ResponseWriter, r *http.Req
 	}
 	err = cfg.db.UpdateLightNeedsByID(r.Context(), updateParams)
 	if err != nil {
-		cfg.sl.Debug("Could not update light needs record", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not update light needs record", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully completed request", "admin id", requestUserID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully completed request", "admin id", requestUserID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -167,7 +167,7 @@ func (cfg *apiConfig) adminLightDeleteHandler(w http.ResponseWriter, r *http.Req
 	lightIDStr := r.PathValue("lightID")
 	lightID, err := uuid.Parse(lightIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse light id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse light id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -175,7 +175,7 @@ func (cfg *apiConfig) adminLightDeleteHandler(w http.ResponseWriter, r *http.Req
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -187,12 +187,12 @@ func (cfg *apiConfig) adminLightDeleteHandler(w http.ResponseWriter, r *http.Req
 	}
 	err = cfg.db.MarkLightNeedAsDeletedByID(r.Context(), deleteParams)
 	if err != nil {
-		cfg.sl.Debug("Could not delete light needs records", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not delete light needs records", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully completed request", "admin id", requestUserID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully completed request", "admin id", requestUserID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -202,7 +202,7 @@ func (cfg *apiConfig) adminSetPlantAsLightNeedHandler(w http.ResponseWriter, r *
 	lightIDStr := r.PathValue("lightID")
 	lightID, err := uuid.Parse(lightIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse light id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse light id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -210,13 +210,13 @@ func (cfg *apiConfig) adminSetPlantAsLightNeedHandler(w http.ResponseWriter, r *
 	// plant species
 	plantSpeciesIDStr := r.URL.Query().Get("plant-species-id")
 	if plantSpeciesIDStr == "" {
-		cfg.sl.Debug("No plant species id was specified in url query")
+		cfg.sl.DebugContext(r.Context(), "No plant species id was specified in url query")
 		respondWithError(errors.New("no plant species id was provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	plantSpeciesID, err := uuid.Parse(plantSpeciesIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant species id from url query", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant species id from url query", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -224,7 +224,7 @@ func (cfg *apiConfig) adminSetPlantAsLightNeedHandler(w http.ResponseWriter, r *
 	// user id
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -241,7 +241,7 @@ func (cfg *apiConfig) adminSetPlantAsLightNeedHandler(w http.ResponseWriter, r *
 	}
 	lightRecord, err := cfg.db.SetPlantSpeciesAsLightNeed(r.Context(), setParams)
 	if err != nil {
-		cfg.sl.Debug("Could not set light need for plant species id", "error", err, "light need id", lightID, "plant species id", plantSpeciesID)
+		cfg.sl.DebugContext(r.Context(), "Could not set light need for plant species id", "error", err, "light need id", lightID, "plant species id", plantSpeciesID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -252,7 +252,7 @@ func (cfg *apiConfig) adminSetPlantAsLightNeedHandler(w http.ResponseWriter, r *
 		PlantSpeciesName: lightRecord.SpeciesName,
 	}
 
-	cfg.sl.Debug("Admin successfully set plant species to light need", "admin id", requestUserID, "plant species id", plantSpeciesID, "light need", lightID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully set plant species to light need", "admin id", requestUserID, "plant species id", plantSpeciesID, "light need", lightID)
 	respondWithJSON(http.StatusOK, lightResponse, w, cfg.sl)
 }
 
@@ -262,7 +262,7 @@ func (cfg *apiConfig) adminUnsetPlantAsLightNeedHandler(w http.ResponseWriter, r
 	lightIDStr := r.PathValue("lightID")
 	lightID, err := uuid.Parse(lightIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse light id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse light id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -270,13 +270,13 @@ func (cfg *apiConfig) adminUnsetPlantAsLightNeedHandler(w http.ResponseWriter, r
 	// plant species
 	plantSpeciesIDStr := r.URL.Query().Get("plant-species-id")
 	if plantSpeciesIDStr == "" {
-		cfg.sl.Debug("No plant species id was specified in url query")
+		cfg.sl.DebugContext(r.Context(), "No plant species id was specified in url query")
 		respondWithError(errors.New("no plant species id was provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	plantSpeciesID, err := uuid.Parse(plantSpeciesIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant species id from url query", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant species id from url query", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -284,7 +284,7 @@ func (cfg *apiConfig) adminUnsetPlantAsLightNeedHandler(w http.ResponseWriter, r
 	// user id
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -296,7 +296,7 @@ func (cfg *apiConfig) adminUnsetPlantAsLightNeedHandler(w http.ResponseWriter, r
 	}
 	lightRecord, err := cfg.db.UnsetPlantSpeciesAsLightNeed(r.Context(), unsetParams)
 	if err != nil {
-		cfg.sl.Debug("Could not unset light need for plant species id", "error", err, "light need id", lightID, "plant species id", plantSpeciesID)
+		cfg.sl.DebugContext(r.Context(), "Could not unset light need for plant species id", "error", err, "light need id", lightID, "plant species id", plantSpeciesID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -306,6 +306,6 @@ func (cfg *apiConfig) adminUnsetPlantAsLightNeedHandler(w http.ResponseWriter, r
 		PlantSpeciesName: lightRecord.SpeciesName,
 	}
 
-	cfg.sl.Debug("Admin successfully unset plant species to light need", "admin id", requestUserID, "plant species id", plantSpeciesID, "light need", lightID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully unset plant species to light need", "admin id", requestUserID, "plant species id", plantSpeciesID, "light need", lightID)
 	respondWithJSON(http.StatusOK, lightResponse, w, cfg.sl)
 }
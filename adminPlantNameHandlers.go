@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nicholasss/plantae/internal/database"
@@ -32,7 +34,7 @@ func (cfg *apiConfig) adminPlantNamesCreateHandler(w http.ResponseWriter, r *htt
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -40,7 +42,7 @@ func (cfg *apiConfig) adminPlantNamesCreateHandler(w http.ResponseWriter, r *htt
 	var createRequest AdminPlantNamesCreateRequest
 	err = json.NewDecoder(r.Body).Decode(&createRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -48,17 +50,17 @@ func (cfg *apiConfig) adminPlantNamesCreateHandler(w http.ResponseWriter, r *htt
 
 	// check all request properties
 	if createRequest.PlantID == uuid.Nil {
-		cfg.sl.Debug("Request body missing plant id")
+		cfg.sl.DebugContext(r.Context(), "Request body missing plant id")
 		respondWithError(errors.New("no plant id provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	if createRequest.LangCode == "" {
-		cfg.sl.Debug("Request body missing lang code")
+		cfg.sl.DebugContext(r.Context(), "Request body missing lang code")
 		respondWithError(errors.New("no lang code provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	if createRequest.CommonName == "" {
-		cfg.sl.Debug("Request body missing common name")
+		cfg.sl.DebugContext(r.Context(), "Request body missing common name")
 		respondWithError(errors.New("no common name provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -75,7 +77,7 @@ func (cfg *apiConfig) adminPlantNamesCreateHandler(w http.ResponseWriter, r *htt
 
 	plantNameRecord, err := cfg.db.CreatePlantName(r.Context(), createRequestParams)
 	if err != nil {
-		cfg.sl.Debug("Could not create plant name record for plant id", "error", err, "plant id", createRequest.PlantID)
+		cfg.sl.DebugContext(r.Context(), "Could not create plant name record for plant id", "error", err, "plant id", createRequest.PlantID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -87,81 +89,78 @@ func (cfg *apiConfig) adminPlantNamesCreateHandler(w http.ResponseWriter, r *htt
 		CommonName: createRequest.CommonName,
 	}
 
-	cfg.sl.Debug("Admin created plant name record", "admin id", requestUserID, "common name", createRequest.CommonName, "plant id", createRequest.PlantID)
+	cfg.sl.DebugContext(r.Context(), "Admin created plant name record", "admin id", requestUserID, "common name", createRequest.CommonName, "plant id", createRequest.PlantID)
 	respondWithJSON(http.StatusCreated, createResponse, w, cfg.sl)
 }
 
+// queries a page of plant names in the requested order
+func (cfg *apiConfig) getPlantNamesPage(ctx context.Context, langCode sql.NullString, page pageRequest) ([]database.GetAllPlantNamesOrderedByCreatedRow, error) {
+	listParams := database.GetAllPlantNamesOrderedByCreatedParams{
+		LangCode:   langCode,
+		CursorTime: page.cursorTime,
+		CursorID:   page.cursorID,
+		RowLimit:   page.rowLimit(),
+	}
+	if page.sort == pageSortCreated {
+		return cfg.db.GetAllPlantNamesOrderedByCreated(ctx, listParams)
+	}
+
+	updatedRecords, err := cfg.db.GetAllPlantNamesOrderedByUpdated(ctx, database.GetAllPlantNamesOrderedByUpdatedParams(listParams))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]database.GetAllPlantNamesOrderedByCreatedRow, 0, len(updatedRecords))
+	for _, record := range updatedRecords {
+		records = append(records, database.GetAllPlantNamesOrderedByCreatedRow(record))
+	}
+	return records, nil
+}
+
+// GET /api/v1/admin/plant-names
+// optionally filtered to a language with the 'lang' url query parameter
+// paginated with 'limit', 'cursor', and 'sort' url query parameters
 func (cfg *apiConfig) adminPlantNamesViewHandler(w http.ResponseWriter, r *http.Request) {
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	requestedLangCode := r.URL.Query().Get("lang")
-	if requestedLangCode == "" {
-		cfg.sl.Debug("Language filter not requested in URL query path")
-	} else {
-		cfg.sl.Debug("Language filter requested in URL query path", "lang code", requestedLangCode)
+	page, err := parsePageRequest(r.URL.Query(), pageSortCreated)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Invalid pagination was provided in url query", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
 	}
 
-	// perform query without language filter
+	// checking language filter code first
+	nullLangCode := sql.NullString{}
+	requestedLangCode := r.URL.Query().Get("lang")
 	if requestedLangCode == "" {
-		plantNameRecords, err := cfg.db.GetAllPlantNamesOrderedByCreated(r.Context())
-		if err != nil {
-			cfg.sl.Debug("Could not get plant name records without lang code", "error", err)
-			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
-			return
-		}
-
-		if len(plantNameRecords) <= 0 {
-			cfg.sl.Debug("Admin successfully listed empty plant name list", "admin id", requestUserID)
-			respondWithJSON(http.StatusOK, plantNameRecords, w, cfg.sl)
+		cfg.sl.DebugContext(r.Context(), "Language filter not requested in URL query path")
+	} else {
+		requestedLangName, ok := LangCodes[requestedLangCode]
+		if !ok {
+			cfg.sl.DebugContext(r.Context(), "Requested language code was not found", "lang code", requestedLangCode)
+			respondWithError(errors.New("language code requested does not exist"), http.StatusBadRequest, w, cfg.sl)
 			return
 		}
 
-		nameResponses := make([]AdminPlantNamesResponse, 0)
-		for _, record := range plantNameRecords {
-			response := AdminPlantNamesResponse{
-				ID:         record.ID,
-				PlantID:    record.PlantID,
-				LangCode:   record.LangCode.String,
-				CommonName: record.CommonName.String,
-			}
-
-			nameResponses = append(nameResponses, response)
-		}
-
-		cfg.sl.Debug("Admin successfully queried all common names", "admin id", requestUserID)
-		respondWithJSON(http.StatusOK, nameResponses, w, cfg.sl)
-		return
-	}
-
-	// perform query with language filter, checking code first
-	requestedLangName, ok := LangCodes[requestedLangCode]
-	if !ok {
-		cfg.sl.Debug("Requested language code was not found", "lang code", requestedLangCode)
-		respondWithError(errors.New("language code requested does not exist"), http.StatusBadRequest, w, cfg.sl)
-		return
+		cfg.sl.DebugContext(r.Context(), "Filtering common names to show requested lang code", "lang code", requestedLangCode, "lang name", requestedLangName)
+		nullLangCode = sql.NullString{String: requestedLangCode, Valid: true}
 	}
 
-	cfg.sl.Debug("Filtering common names to show requested lang code", "lang code", requestedLangCode, "lang name", requestedLangName)
-
-	nullLangCode := sql.NullString{String: requestedLangCode, Valid: true}
-	plantNameRecords, err := cfg.db.GetAllPlantNamesForLanguageOrderedByCreated(r.Context(), nullLangCode)
+	plantNameRecords, err := cfg.getPlantNamesPage(r.Context(), nullLangCode, page)
 	if err != nil {
-		cfg.sl.Debug("Could not get common names for language code", "error", err, "lang code", requestedLangCode)
+		cfg.sl.DebugContext(r.Context(), "Could not get plant name records", "error", err, "lang code", requestedLangCode)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
-
-	if len(plantNameRecords) <= 0 {
-		cfg.sl.Debug("Admin successfully listed empty plant name list", "admin id", requestUserID)
-		respondWithJSON(http.StatusOK, plantNameRecords, w, cfg.sl)
-		return
-	}
+	plantNameRecords, nextCursor := nextPage(plantNameRecords, page, func(record database.GetAllPlantNamesOrderedByCreatedRow) (time.Time, time.Time, uuid.UUID) {
+		return record.CreatedAt, record.UpdatedAt, record.ID
+	})
 
 	nameResponses := make([]AdminPlantNamesResponse, 0)
 	for _, record := range plantNameRecords {
@@ -175,15 +174,20 @@ func (cfg *apiConfig) adminPlantNamesViewHandler(w http.ResponseWriter, r *http.
 		nameResponses = append(nameResponses, response)
 	}
 
-	cfg.sl.Debug("Admin successfully queried common names for language", "admin id", requestUserID, "lang code", requestedLangCode)
-	respondWithJSON(http.StatusOK, nameResponses, w, cfg.sl)
+	pageResponse := PageResponse[AdminPlantNamesResponse]{
+		Items:      nameResponses,
+		NextCursor: nextCursor,
+	}
+
+	cfg.sl.DebugContext(r.Context(), "Admin successfully queried common names", "admin id", requestUserID, "lang code", requestedLangCode)
+	respondWithJSON(http.StatusOK, pageResponse, w, cfg.sl)
 }
 
 func (cfg *apiConfig) adminPlantNamesDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	plantNameIDStr := r.PathValue("plantNameID")
 	plantNameID, err := uuid.Parse(plantNameIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant name id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant name id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -191,7 +195,7 @@ func (cfg *apiConfig) adminPlantNamesDeleteHandler(w http.ResponseWriter, r *htt
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -206,11 +210,11 @@ func (cfg *apiConfig) adminPlantNamesDeleteHandler(w http.ResponseWriter, r *htt
 	}
 	err = cfg.db.MarkPlantNameAsDeletedByID(r.Context(), requestParams)
 	if err != nil {
-		cfg.sl.Debug("Could not mark plant name as deleted", "error", err, "plant name id", plantNameID)
+		cfg.sl.DebugContext(r.Context(), "Could not mark plant name as deleted", "error", err, "plant name id", plantNameID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin marked plant name record as deleted", "admin id", requestUserID, "plant name id", plantNameID)
+	cfg.sl.DebugContext(r.Context(), "Admin marked plant name record as deleted", "admin id", requestUserID, "plant name id", plantNameID)
 	w.WriteHeader(http.StatusNoContent)
 }
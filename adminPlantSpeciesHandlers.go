@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nicholasss/plantae/internal/database"
@@ -51,27 +53,54 @@ type AdminPlantSpeciesViewResponse struct {
 
 // === handler functions ===
 
+// queries a page of plant species in the requested order
+func (cfg *apiConfig) getPlantSpeciesPage(ctx context.Context, page pageRequest) ([]database.GetAllPlantSpeciesOrderedByCreatedRow, error) {
+	listParams := database.GetAllPlantSpeciesOrderedByCreatedParams{
+		CursorTime: page.cursorTime,
+		CursorID:   page.cursorID,
+		RowLimit:   page.rowLimit(),
+	}
+	if page.sort == pageSortCreated {
+		return cfg.db.GetAllPlantSpeciesOrderedByCreated(ctx, listParams)
+	}
+
+	updatedRecords, err := cfg.db.GetAllPlantSpeciesOrderedByUpdated(ctx, database.GetAllPlantSpeciesOrderedByUpdatedParams(listParams))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]database.GetAllPlantSpeciesOrderedByCreatedRow, 0, len(updatedRecords))
+	for _, record := range updatedRecords {
+		records = append(records, database.GetAllPlantSpeciesOrderedByCreatedRow(record))
+	}
+	return records, nil
+}
+
 // GET json
+// paginated with 'limit', 'cursor', and 'sort' url query parameters
 func (cfg *apiConfig) adminPlantSpeciesViewHandler(w http.ResponseWriter, r *http.Request) {
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	plantSpeciesRecords, err := cfg.db.GetAllPlantSpeciesOrderedByCreated(r.Context())
+	page, err := parsePageRequest(r.URL.Query(), pageSortCreated)
 	if err != nil {
-		cfg.sl.Debug("Could not get plant species records", "error", err)
-		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		cfg.sl.DebugContext(r.Context(), "Invalid pagination was provided in url query", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	if len(plantSpeciesRecords) <= 0 {
-		cfg.sl.Debug("Admin successfully listed empty plant species list", "admin id", requestUserID)
-		respondWithJSON(http.StatusOK, plantSpeciesRecords, w, cfg.sl)
+	plantSpeciesRecords, err := cfg.getPlantSpeciesPage(r.Context(), page)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get plant species records", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
+	plantSpeciesRecords, nextCursor := nextPage(plantSpeciesRecords, page, func(record database.GetAllPlantSpeciesOrderedByCreatedRow) (time.Time, time.Time, uuid.UUID) {
+		return record.CreatedAt, record.UpdatedAt, record.ID
+	})
 
 	// TODO: not the most efficient way to convert, is there another way?
 	plantSpeciesResponse := make([]AdminPlantSpeciesViewResponse, 0)
@@ -115,8 +144,13 @@ func (cfg *apiConfig) adminPlantSpeciesViewHandler(w http.ResponseWriter, r *htt
 		plantSpeciesResponse = append(plantSpeciesResponse, newResponse)
 	}
 
-	cfg.sl.Debug("Admin successfully listed plant species list", "admin id", requestUserID)
-	respondWithJSON(http.StatusOK, plantSpeciesResponse, w, cfg.sl)
+	pageResponse := PageResponse[AdminPlantSpeciesViewResponse]{
+		Items:      plantSpeciesResponse,
+		NextCursor: nextCursor,
+	}
+
+	cfg.sl.DebugContext(r.Context(), "Admin successfully listed plant species list", "admin id", requestUserID)
+	respondWithJSON(http.StatusOK, pageResponse, w, cfg.sl)
 }
 
 // PUT /api/v1/admin/plants/{plantSpeciesID}
@@ -124,7 +158,7 @@ func (cfg *apiConfig) adminReplacePlantSpeciesInfoHandler(w http.ResponseWriter,
 	plantSpeciesIDStr := r.PathValue("plantSpeciesID")
 	plantSpeciesID, err := uuid.Parse(plantSpeciesIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse species id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse species id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -132,7 +166,7 @@ func (cfg *apiConfig) adminReplacePlantSpeciesInfoHandler(w http.ResponseWriter,
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -140,7 +174,7 @@ func (cfg *apiConfig) adminReplacePlantSpeciesInfoHandler(w http.ResponseWriter,
 	var updateRequest AdminPlantSpeciesUpdateRequest
 	err = json.NewDecoder(r.Body).Decode(&updateRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -188,12 +222,12 @@ func (cfg *apiConfig) adminReplacePlantSpeciesInfoHandler(w http.ResponseWriter,
 
 	err = cfg.db.UpdatePlantSpeciesPropertiesByID(r.Context(), updateRequestParams)
 	if err != nil {
-		cfg.sl.Debug("Could not update plant species record", "error", err, "plant species id", plantSpeciesID)
+		cfg.sl.DebugContext(r.Context(), "Could not update plant species record", "error", err, "plant species id", plantSpeciesID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully updated plant species", "admin id", requestUserID, "plant species id", plantSpeciesID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully updated plant species", "admin id", requestUserID, "plant species id", plantSpeciesID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -202,7 +236,7 @@ func (cfg *apiConfig) adminDeletePlantSpeciesHandler(w http.ResponseWriter, r *h
 	plantSpeciesIDStr := r.PathValue("plantSpeciesID")
 	plantSpeciesID, err := uuid.Parse(plantSpeciesIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse species id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse species id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -210,7 +244,7 @@ func (cfg *apiConfig) adminDeletePlantSpeciesHandler(w http.ResponseWriter, r *h
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -225,12 +259,12 @@ func (cfg *apiConfig) adminDeletePlantSpeciesHandler(w http.ResponseWriter, r *h
 	}
 	err = cfg.db.MarkPlantSpeciesAsDeletedByID(r.Context(), deleteRequestParams)
 	if err != nil {
-		cfg.sl.Debug("Could not mark plant species as deleted", "error", err, "plant species id", plantSpeciesID)
+		cfg.sl.DebugContext(r.Context(), "Could not mark plant species as deleted", "error", err, "plant species id", plantSpeciesID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully marked plant species as deleted", "admin id", requestUserID, "plant species id", plantSpeciesID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully marked plant species as deleted", "admin id", requestUserID, "plant species id", plantSpeciesID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -240,7 +274,7 @@ func (cfg *apiConfig) adminPlantSpeciesCreateHandler(w http.ResponseWriter, r *h
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -248,7 +282,7 @@ func (cfg *apiConfig) adminPlantSpeciesCreateHandler(w http.ResponseWriter, r *h
 	var createRequest AdminPlantSpeciesCreateRequest
 	err = json.NewDecoder(r.Body).Decode(&createRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -256,7 +290,7 @@ func (cfg *apiConfig) adminPlantSpeciesCreateHandler(w http.ResponseWriter, r *h
 
 	// check all of the request properties
 	if createRequest.SpeciesName == "" {
-		cfg.sl.Debug("Request body missing species name")
+		cfg.sl.DebugContext(r.Context(), "Request body missing species name")
 		respondWithError(errors.New("no species name provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -303,7 +337,7 @@ func (cfg *apiConfig) adminPlantSpeciesCreateHandler(w http.ResponseWriter, r *h
 	}
 	speciesRecord, err := cfg.db.CreatePlantSpecies(r.Context(), createRequestParams)
 	if err != nil {
-		cfg.sl.Debug("Could not create plant species in database", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not create plant species in database", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -343,6 +377,6 @@ func (cfg *apiConfig) adminPlantSpeciesCreateHandler(w http.ResponseWriter, r *h
 		PetEdible:        petEP,
 	}
 
-	cfg.sl.Debug("Admin successfully created plant species", "admin id", requestUserID, "species id", speciesRecord.ID, "species name", speciesRecord.SpeciesName)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully created plant species", "admin id", requestUserID, "species id", speciesRecord.ID, "species name", speciesRecord.SpeciesName)
 	respondWithJSON(http.StatusCreated, plantSpeciesResponse, w, cfg.sl)
 }
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+const (
+	speciesImportMaxBytes = 8 << 20
+	speciesImportMaxRows  = 1000
+)
+
+// speciesImportCSVColumns are the columns a csv import may have, only speciesName is required.
+// commonNames holds 'langCode:name' pairs separated by semicolons, e.g. 'en:jade plant;es:árbol de jade'
+var speciesImportCSVColumns = []string{
+	"speciesName",
+	"humanPoisonToxic",
+	"petPoisonToxic",
+	"humanEdible",
+	"petEdible",
+	"commonNames",
+	"plantType",
+	"lightNeed",
+	"waterNeed",
+}
+
+// === request response types ===
+
+// AdminPlantSpeciesImportRow is one species of a bulk import.
+// The plant type, light need, and water need are linked by their names.
+type AdminPlantSpeciesImportRow struct {
+	SpeciesName      string                        `json:"speciesName"`
+	HumanPoisonToxic *bool                         `json:"humanPoisonToxic"`
+	PetPoisonToxic   *bool                         `json:"petPoisonToxic"`
+	HumanEdible      *bool                         `json:"humanEdible"`
+	PetEdible        *bool                         `json:"petEdible"`
+	CommonNames      []AdminPlantSpeciesImportName `json:"commonNames"`
+	PlantType        *string                       `json:"plantType"`
+	LightNeed        *string                       `json:"lightNeed"`
+	WaterNeed        *string                       `json:"waterNeed"`
+}
+
+type AdminPlantSpeciesImportName struct {
+	LangCode   string `json:"langCode"`
+	CommonName string `json:"commonName"`
+}
+
+// AdminPlantSpeciesImportRowResult reports on one row, rows are numbered from 1 in the order they were sent.
+type AdminPlantSpeciesImportRowResult struct {
+	Row         int        `json:"row"`
+	SpeciesName string     `json:"speciesName"`
+	ID          *uuid.UUID `json:"id,omitempty"`
+	Errors      []string   `json:"errors,omitempty"`
+}
+
+type AdminPlantSpeciesImportResponse struct {
+	DryRun   bool                               `json:"dryRun"`
+	Valid    int                                `json:"valid"`
+	Imported int                                `json:"imported"`
+	Failed   int                                `json:"failed"`
+	Rows     []AdminPlantSpeciesImportRowResult `json:"rows"`
+}
+
+// speciesImportRow is a row being validated and imported
+type speciesImportRow struct {
+	AdminPlantSpeciesImportRow
+	errors       []string
+	id           uuid.UUID
+	plantTypeID  uuid.NullUUID
+	lightNeedsID uuid.NullUUID
+	waterNeedsID uuid.NullUUID
+}
+
+func (row *speciesImportRow) addError(format string, args ...any) {
+	row.errors = append(row.errors, fmt.Sprintf(format, args...))
+}
+
+// === parsing and validation functions ===
+
+func parseSpeciesImportJSON(body io.Reader) ([]speciesImportRow, error) {
+	var requestRows []AdminPlantSpeciesImportRow
+	err := json.NewDecoder(body).Decode(&requestRows)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]speciesImportRow, 0, len(requestRows))
+	for _, requestRow := range requestRows {
+		rows = append(rows, speciesImportRow{AdminPlantSpeciesImportRow: requestRow})
+	}
+	return rows, nil
+}
+
+// parses a csv with a header row, cells that cannot be parsed are reported on their row
+func parseSpeciesImportCSV(body io.Reader) ([]speciesImportRow, error) {
+	records, err := csv.NewReader(body).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv has no header row")
+	}
+
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		column = strings.TrimSpace(column)
+		if !slices.Contains(speciesImportCSVColumns, column) {
+			return nil, fmt.Errorf("csv has unknown column %q", column)
+		}
+		columns[column] = i
+	}
+	if _, ok := columns["speciesName"]; !ok {
+		return nil, errors.New("csv has no speciesName column")
+	}
+
+	rows := make([]speciesImportRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		var row speciesImportRow
+		cell := func(column string) string {
+			i, ok := columns[column]
+			if !ok {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		optionalBool := func(column string) *bool {
+			value := cell(column)
+			if value == "" {
+				return nil
+			}
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				row.addError("%s must be 'true', 'false', or empty", column)
+				return nil
+			}
+			return &parsed
+		}
+		optionalString := func(column string) *string {
+			value := cell(column)
+			if value == "" {
+				return nil
+			}
+			return &value
+		}
+
+		row.SpeciesName = cell("speciesName")
+		row.HumanPoisonToxic = optionalBool("humanPoisonToxic")
+		row.PetPoisonToxic = optionalBool("petPoisonToxic")
+		row.HumanEdible = optionalBool("humanEdible")
+		row.PetEdible = optionalBool("petEdible")
+		row.PlantType = optionalString("plantType")
+		row.LightNeed = optionalString("lightNeed")
+		row.WaterNeed = optionalString("waterNeed")
+
+		for _, pair := range strings.Split(cell("commonNames"), ";") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			langCode, commonName, ok := strings.Cut(pair, ":")
+			if !ok {
+				row.addError("common name %q must be written as 'langCode:name'", pair)
+				continue
+			}
+			row.CommonNames = append(row.CommonNames, AdminPlantSpeciesImportName{
+				LangCode:   strings.TrimSpace(langCode),
+				CommonName: strings.TrimSpace(commonName),
+			})
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// checks every row against the catalog and the other rows, and resolves the linked names to ids
+// problems are recorded on the rows, the returned error is only for database failures
+func (cfg *apiConfig) validateSpeciesImportRows(ctx context.Context, rows []speciesImportRow) error {
+	speciesRecords, err := cfg.db.ExportPlantSpecies(ctx)
+	if err != nil {
+		return err
+	}
+	existingSpecies := make(map[string]bool)
+	for _, record := range speciesRecords {
+		existingSpecies[strings.ToLower(record.SpeciesName)] = true
+	}
+
+	plantTypeRecords, err := cfg.db.ExportPlantTypes(ctx)
+	if err != nil {
+		return err
+	}
+	plantTypes := make(catalogNameIndex)
+	for _, record := range plantTypeRecords {
+		plantTypes.add(record.Name, record.ID)
+	}
+
+	lightNeedRecords, err := cfg.db.ExportLightNeeds(ctx)
+	if err != nil {
+		return err
+	}
+	lightNeeds := make(catalogNameIndex)
+	for _, record := range lightNeedRecords {
+		lightNeeds.add(record.Name, record.ID)
+	}
+
+	waterNeedRecords, err := cfg.db.ExportWaterNeeds(ctx)
+	if err != nil {
+		return err
+	}
+	waterNeeds := make(catalogNameIndex)
+	for _, record := range waterNeedRecords {
+		waterNeeds.add(record.PlantType, record.ID)
+	}
+
+	// the first row of a species name within the import, to report duplicates
+	firstRows := make(map[string]int)
+	for i := range rows {
+		row := &rows[i]
+
+		row.SpeciesName = strings.TrimSpace(row.SpeciesName)
+		speciesKey := strings.ToLower(row.SpeciesName)
+		switch {
+		case row.SpeciesName == "":
+			row.addError("speciesName is required")
+		case existingSpecies[speciesKey]:
+			row.addError("species %q already exists", row.SpeciesName)
+		default:
+			if firstRow, ok := firstRows[speciesKey]; ok {
+				row.addError("species %q is a duplicate of row %d", row.SpeciesName, firstRow)
+			} else {
+				firstRows[speciesKey] = i + 1
+			}
+		}
+
+		for _, name := range row.CommonNames {
+			if _, ok := LangCodes[name.LangCode]; !ok {
+				row.addError("common name %q has unknown lang code %q", name.CommonName, name.LangCode)
+			}
+			if strings.TrimSpace(name.CommonName) == "" {
+				row.addError("common name for lang code %q is empty", name.LangCode)
+			}
+		}
+
+		row.plantTypeID = plantTypes.resolve(row, "plant type", row.PlantType)
+		row.lightNeedsID = lightNeeds.resolve(row, "light need", row.LightNeed)
+		row.waterNeedsID = waterNeeds.resolve(row, "water need", row.WaterNeed)
+	}
+
+	return nil
+}
+
+// catalogNameIndex finds the ids of catalog records by name, ignoring case
+type catalogNameIndex map[string][]uuid.UUID
+
+func (index catalogNameIndex) add(name string, id uuid.UUID) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	index[key] = append(index[key], id)
+}
+
+// returns the id of the named record, recording an error on the row if it is unknown or ambiguous
+func (index catalogNameIndex) resolve(row *speciesImportRow, kind string, name *string) uuid.NullUUID {
+	if name == nil || strings.TrimSpace(*name) == "" {
+		return uuid.NullUUID{}
+	}
+
+	ids := index[strings.ToLower(strings.TrimSpace(*name))]
+	switch len(ids) {
+	case 0:
+		row.addError("%s %q does not exist", kind, *name)
+		return uuid.NullUUID{}
+	case 1:
+		return uuid.NullUUID{UUID: ids[0], Valid: true}
+	default:
+		row.addError("%s %q matches more than one record", kind, *name)
+		return uuid.NullUUID{}
+	}
+}
+
+// creates the species of every row with their common names and links
+func importSpeciesRows(ctx context.Context, q *database.Queries, rows []speciesImportRow, requestUserID uuid.UUID) error {
+	for i := range rows {
+		row := &rows[i]
+
+		speciesRecord, err := q.CreatePlantSpecies(ctx, database.CreatePlantSpeciesParams{
+			CreatedBy:        requestUserID,
+			UpdatedBy:        requestUserID,
+			SpeciesName:      row.SpeciesName,
+			HumanPoisonToxic: ptrNullBool(row.HumanPoisonToxic),
+			PetPoisonToxic:   ptrNullBool(row.PetPoisonToxic),
+			HumanEdible:      ptrNullBool(row.HumanEdible),
+			PetEdible:        ptrNullBool(row.PetEdible),
+		})
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+		row.id = speciesRecord.ID
+
+		for _, name := range row.CommonNames {
+			_, err = q.CreatePlantName(ctx, database.CreatePlantNameParams{
+				CreatedBy:  requestUserID,
+				PlantID:    row.id,
+				LangCode:   sql.NullString{String: name.LangCode, Valid: true},
+				CommonName: sql.NullString{String: strings.TrimSpace(name.CommonName), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+
+		if row.plantTypeID.Valid {
+			_, err = q.SetPlantSpeciesAsType(ctx, database.SetPlantSpeciesAsTypeParams{
+				ID:          row.id,
+				PlantTypeID: row.plantTypeID,
+				UpdatedBy:   requestUserID,
+			})
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		if row.lightNeedsID.Valid {
+			_, err = q.SetPlantSpeciesAsLightNeed(ctx, database.SetPlantSpeciesAsLightNeedParams{
+				ID:           row.id,
+				LightNeedsID: row.lightNeedsID,
+				UpdatedBy:    requestUserID,
+			})
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		if row.waterNeedsID.Valid {
+			_, err = q.SetPlantSpeciesAsWaterNeed(ctx, database.SetPlantSpeciesAsWaterNeedParams{
+				ID:           row.id,
+				WaterNeedsID: row.waterNeedsID,
+				UpdatedBy:    requestUserID,
+			})
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// === handler functions ===
+
+// POST /api/v1/admin/plant-species/import
+// accepts a json array of rows, or a csv with a header row when sent as 'text/csv'
+// nothing is imported unless every row is valid, and 'dryRun=true' only validates the rows
+func (cfg *apiConfig) adminPlantSpeciesImportHandler(w http.ResponseWriter, r *http.Request) {
+	// check header for admin access token
+	requestUserID, err := cfg.getUserIDFromToken(r)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dryRun"); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Invalid dry run was requested", "dry run", dryRunStr)
+			respondWithError(errors.New("dryRun must be either 'true' or 'false'"), http.StatusBadRequest, w, cfg.sl)
+			return
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse content type of request", "error", err)
+		respondWithError(err, http.StatusUnsupportedMediaType, w, cfg.sl)
+		return
+	}
+
+	var rows []speciesImportRow
+	r.Body = http.MaxBytesReader(w, r.Body, speciesImportMaxBytes)
+	switch mediaType {
+	case "application/json":
+		rows, err = parseSpeciesImportJSON(r.Body)
+	case "text/csv":
+		rows, err = parseSpeciesImportCSV(r.Body)
+	default:
+		cfg.sl.DebugContext(r.Context(), "Unsupported content type for species import", "content type", mediaType)
+		respondWithError(errors.New("content type must be 'application/json' or 'text/csv'"), http.StatusUnsupportedMediaType, w, cfg.sl)
+		return
+	}
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	if len(rows) == 0 {
+		cfg.sl.DebugContext(r.Context(), "Species import has no rows")
+		respondWithError(errors.New("no rows to import"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	if len(rows) > speciesImportMaxRows {
+		cfg.sl.DebugContext(r.Context(), "Species import has too many rows", "rows", len(rows))
+		respondWithError(errors.New("at most 1000 rows can be imported at once"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	err = cfg.validateSpeciesImportRows(r.Context(), rows)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not validate species import rows", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	importResponse := AdminPlantSpeciesImportResponse{
+		DryRun: dryRun,
+		Rows:   make([]AdminPlantSpeciesImportRowResult, 0, len(rows)),
+	}
+	for _, row := range rows {
+		if len(row.errors) > 0 {
+			importResponse.Failed++
+		} else {
+			importResponse.Valid++
+		}
+	}
+
+	// nothing is written if any row is invalid
+	if importResponse.Failed > 0 {
+		for i, row := range rows {
+			importResponse.Rows = append(importResponse.Rows, AdminPlantSpeciesImportRowResult{
+				Row:         i + 1,
+				SpeciesName: row.SpeciesName,
+				Errors:      row.errors,
+			})
+		}
+		cfg.sl.DebugContext(r.Context(), "Species import has invalid rows", "admin id", requestUserID, "failed", importResponse.Failed)
+		respondWithJSON(http.StatusBadRequest, importResponse, w, cfg.sl)
+		return
+	}
+
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), nil)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to begin species import transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+
+	err = importSpeciesRows(r.Context(), cfg.db.WithTx(tx), rows, requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to import species rows", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	if !dryRun {
+		err = tx.Commit()
+		if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Unable to commit species import transaction", "error", err)
+			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+			return
+		}
+	}
+
+	for i, row := range rows {
+		result := AdminPlantSpeciesImportRowResult{
+			Row:         i + 1,
+			SpeciesName: row.SpeciesName,
+		}
+		// ids from a dry run were rolled back
+		if !dryRun {
+			result.ID = &row.id
+		}
+		importResponse.Rows = append(importResponse.Rows, result)
+	}
+
+	// nothing is imported on a dry run, valid tells how many rows would be
+	if dryRun {
+		cfg.sl.DebugContext(r.Context(), "Admin successfully validated species import", "admin id", requestUserID, "rows", len(rows))
+		respondWithJSON(http.StatusOK, importResponse, w, cfg.sl)
+		return
+	}
+
+	importResponse.Imported = len(rows)
+	cfg.sl.InfoContext(r.Context(), "Admin successfully imported species", "admin id", requestUserID, "rows", len(rows))
+	respondWithJSON(http.StatusCreated, importResponse, w, cfg.sl)
+}
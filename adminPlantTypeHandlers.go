@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nicholasss/plantae/internal/database"
@@ -85,7 +87,7 @@ func (cfg *apiConfig) adminPlantTypesCreateHandler(w http.ResponseWriter, r *htt
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -93,7 +95,7 @@ func (cfg *apiConfig) adminPlantTypesCreateHandler(w http.ResponseWriter, r *htt
 	var createRequest AdminPlantTypeCreateRequest
 	err = json.NewDecoder(r.Body).Decode(&createRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -101,12 +103,12 @@ func (cfg *apiConfig) adminPlantTypesCreateHandler(w http.ResponseWriter, r *htt
 
 	// checking body properties
 	if createRequest.Name == "" {
-		cfg.sl.Debug("Request body missing name")
+		cfg.sl.DebugContext(r.Context(), "Request body missing name")
 		respondWithError(errors.New("no name provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	if createRequest.Description == "" {
-		cfg.sl.Debug("Request body missing description")
+		cfg.sl.DebugContext(r.Context(), "Request body missing description")
 		respondWithError(errors.New("no description provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -177,7 +179,7 @@ func (cfg *apiConfig) adminPlantTypesCreateHandler(w http.ResponseWriter, r *htt
 	}
 	typeRecord, err := cfg.db.CreatePlantType(r.Context(), createParams)
 	if err != nil {
-		cfg.sl.Debug("Could not create plant type in database", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not create plant type in database", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -225,33 +227,60 @@ func (cfg *apiConfig) adminPlantTypesCreateHandler(w http.ResponseWriter, r *htt
 		SoilDrainageMix:       SoilDrainageMix,
 	}
 
-	cfg.sl.Debug("Admin successfully create plant type", "admin id", requestUserID, "plant type id", typeRecord.ID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully create plant type", "admin id", requestUserID, "plant type id", typeRecord.ID)
 	respondWithJSON(http.StatusCreated, plantTypeResponse, w, cfg.sl)
 }
 
+// queries a page of plant types in the requested order
+func (cfg *apiConfig) getPlantTypesPage(ctx context.Context, page pageRequest) ([]database.GetAllPlantTypesOrderedByCreatedRow, error) {
+	listParams := database.GetAllPlantTypesOrderedByCreatedParams{
+		CursorTime: page.cursorTime,
+		CursorID:   page.cursorID,
+		RowLimit:   page.rowLimit(),
+	}
+	if page.sort == pageSortCreated {
+		return cfg.db.GetAllPlantTypesOrderedByCreated(ctx, listParams)
+	}
+
+	updatedRecords, err := cfg.db.GetAllPlantTypesOrderedByUpdated(ctx, database.GetAllPlantTypesOrderedByUpdatedParams(listParams))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]database.GetAllPlantTypesOrderedByCreatedRow, 0, len(updatedRecords))
+	for _, record := range updatedRecords {
+		records = append(records, database.GetAllPlantTypesOrderedByCreatedRow(record))
+	}
+	return records, nil
+}
+
 // GET /admin/plant-type
 // view list of plant types
+// paginated with 'limit', 'cursor', and 'sort' url query parameters
 func (cfg *apiConfig) adminPlantTypesViewHandler(w http.ResponseWriter, r *http.Request) {
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	plantTypeRecords, err := cfg.db.GetAllPlantTypesOrderedByCreated(r.Context())
+	page, err := parsePageRequest(r.URL.Query(), pageSortCreated)
 	if err != nil {
-		cfg.sl.Debug("Could not get plant type records", "error", err)
-		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		cfg.sl.DebugContext(r.Context(), "Invalid pagination was provided in url query", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	if len(plantTypeRecords) <= 0 {
-		cfg.sl.Debug("Admin successfully listed empty plant species list", "admin id", requestUserID)
-		respondWithJSON(http.StatusOK, plantTypeRecords, w, cfg.sl)
+	plantTypeRecords, err := cfg.getPlantTypesPage(r.Context(), page)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get plant type records", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
+	plantTypeRecords, nextCursor := nextPage(plantTypeRecords, page, func(record database.GetAllPlantTypesOrderedByCreatedRow) (time.Time, time.Time, uuid.UUID) {
+		return record.CreatedAt, record.UpdatedAt, record.ID
+	})
 
 	// TODO: not the most efficient way to convert, is there another way?
 	plantTypeResponse := make([]AdminPlantTypeViewResponse, 0)
@@ -302,8 +331,13 @@ func (cfg *apiConfig) adminPlantTypesViewHandler(w http.ResponseWriter, r *http.
 		plantTypeResponse = append(plantTypeResponse, newRecord)
 	}
 
-	cfg.sl.Debug("Admin successfully listed plant type list", "admin id", requestUserID)
-	respondWithJSON(http.StatusOK, plantTypeResponse, w, cfg.sl)
+	pageResponse := PageResponse[AdminPlantTypeViewResponse]{
+		Items:      plantTypeResponse,
+		NextCursor: nextCursor,
+	}
+
+	cfg.sl.DebugContext(r.Context(), "Admin successfully listed plant type list", "admin id", requestUserID)
+	respondWithJSON(http.StatusOK, pageResponse, w, cfg.sl)
 }
 
 // plant type info update
@@ -311,7 +345,7 @@ func (cfg *apiConfig) adminPlantTypesUpdateHandler(w http.ResponseWriter, r *htt
 	plantTypeIDStr := r.PathValue("plantTypeID")
 	plantTypeID, err := uuid.Parse(plantTypeIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant type id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant type id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -319,7 +353,7 @@ func (cfg *apiConfig) adminPlantTypesUpdateHandler(w http.ResponseWriter, r *htt
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -327,7 +361,7 @@ func (cfg *apiConfig) adminPlantTypesUpdateHandler(w http.ResponseWriter, r *htt
 	var updateRequest AdminPlantTypeUpdateRequest
 	err = json.NewDecoder(r.Body).Decode(&updateRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -398,12 +432,12 @@ func (cfg *apiConfig) adminPlantTypesUpdateHandler(w http.ResponseWriter, r *htt
 
 	err = cfg.db.UpdatePlantTypesPropertiesByID(r.Context(), updateParams)
 	if err != nil {
-		cfg.sl.Debug("Could not update plant type record", "error", err, "plant type id", plantTypeID)
+		cfg.sl.DebugContext(r.Context(), "Could not update plant type record", "error", err, "plant type id", plantTypeID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully updated plant type", "admin id", requestUserID, "plant type id", plantTypeID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully updated plant type", "admin id", requestUserID, "plant type id", plantTypeID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -412,7 +446,7 @@ func (cfg *apiConfig) adminPlantTypeDeleteHandler(w http.ResponseWriter, r *http
 	plantTypeIDStr := r.PathValue("plantTypeID")
 	plantTypeID, err := uuid.Parse(plantTypeIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant type id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant type id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -420,7 +454,7 @@ func (cfg *apiConfig) adminPlantTypeDeleteHandler(w http.ResponseWriter, r *http
 	// check header for admin access token
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -433,12 +467,12 @@ func (cfg *apiConfig) adminPlantTypeDeleteHandler(w http.ResponseWriter, r *http
 	}
 	err = cfg.db.MarkPlantTypeAsDeletedByID(r.Context(), deleteParams)
 	if err != nil {
-		cfg.sl.Debug("Could not mark plant type as deleted", "error", err, "plant type id", plantTypeID)
+		cfg.sl.DebugContext(r.Context(), "Could not mark plant type as deleted", "error", err, "plant type id", plantTypeID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully marked plant type as deleted", "admin id", requestUserID, "plant type id", plantTypeID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully marked plant type as deleted", "admin id", requestUserID, "plant type id", plantTypeID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -448,7 +482,7 @@ func (cfg *apiConfig) adminSetPlantAsTypeHandler(w http.ResponseWriter, r *http.
 	plantTypeIDStr := r.PathValue("plantTypeID")
 	plantTypeID, err := uuid.Parse(plantTypeIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant type id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant type id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -456,13 +490,13 @@ func (cfg *apiConfig) adminSetPlantAsTypeHandler(w http.ResponseWriter, r *http.
 	// plant species
 	plantSpeciesIDStr := r.URL.Query().Get("plant-species-id")
 	if plantSpeciesIDStr == "" {
-		cfg.sl.Debug("No plant species id was provided in url query")
+		cfg.sl.DebugContext(r.Context(), "No plant species id was provided in url query")
 		respondWithError(errors.New("no plant species id was provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	plantSpeciesID, err := uuid.Parse(plantSpeciesIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant species id from url query", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant species id from url query", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -470,7 +504,7 @@ func (cfg *apiConfig) adminSetPlantAsTypeHandler(w http.ResponseWriter, r *http.
 	// user id
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -484,7 +518,7 @@ func (cfg *apiConfig) adminSetPlantAsTypeHandler(w http.ResponseWriter, r *http.
 	}
 	speciesRecord, err := cfg.db.SetPlantSpeciesAsType(r.Context(), setPlantTypeParams)
 	if err != nil {
-		cfg.sl.Debug("Could not set plant type for plant species", "error", err, "plant type id", plantTypeID, "plant species id", plantSpeciesID)
+		cfg.sl.DebugContext(r.Context(), "Could not set plant type for plant species", "error", err, "plant type id", plantTypeID, "plant species id", plantSpeciesID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -495,7 +529,7 @@ func (cfg *apiConfig) adminSetPlantAsTypeHandler(w http.ResponseWriter, r *http.
 		PlantSpeciesName: speciesRecord.SpeciesName,
 	}
 
-	cfg.sl.Debug("Admin successfully set plant species to plant type", "admin id", requestUserID, "plant species id", plantSpeciesID, "plant type id", plantTypeID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully set plant species to plant type", "admin id", requestUserID, "plant species id", plantSpeciesID, "plant type id", plantTypeID)
 	respondWithJSON(http.StatusOK, setResponse, w, cfg.sl)
 }
 
@@ -505,7 +539,7 @@ func (cfg *apiConfig) adminUnsetPlantAsTypeHandler(w http.ResponseWriter, r *htt
 	plantTypeIDStr := r.PathValue("plantTypeID")
 	plantTypeID, err := uuid.Parse(plantTypeIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant type id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant type id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -513,13 +547,13 @@ func (cfg *apiConfig) adminUnsetPlantAsTypeHandler(w http.ResponseWriter, r *htt
 	// plant species
 	plantSpeciesIDStr := r.URL.Query().Get("plant-species-id")
 	if plantSpeciesIDStr == "" {
-		cfg.sl.Debug("No plant species id was provided in url query")
+		cfg.sl.DebugContext(r.Context(), "No plant species id was provided in url query")
 		respondWithError(errors.New("no plant species id was provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	plantSpeciesID, err := uuid.Parse(plantSpeciesIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant species id from url query", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant species id from url query", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -527,7 +561,7 @@ func (cfg *apiConfig) adminUnsetPlantAsTypeHandler(w http.ResponseWriter, r *htt
 	// user id
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -539,7 +573,7 @@ func (cfg *apiConfig) adminUnsetPlantAsTypeHandler(w http.ResponseWriter, r *htt
 	}
 	speciesRecord, err := cfg.db.UnsetPlantSpeciesAsType(r.Context(), unsetPlantTypeParams)
 	if err != nil {
-		cfg.sl.Debug("Could not unset type for plant species", "error", err, "plant type id", plantTypeID, "plant species id", plantSpeciesID)
+		cfg.sl.DebugContext(r.Context(), "Could not unset type for plant species", "error", err, "plant type id", plantTypeID, "plant species id", plantSpeciesID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -549,6 +583,6 @@ func (cfg *apiConfig) adminUnsetPlantAsTypeHandler(w http.ResponseWriter, r *htt
 		PlantSpeciesName: speciesRecord.SpeciesName,
 	}
 
-	cfg.sl.Debug("Admin successfully unset plant species from plant type", "admin id", requestUserID, "plant species id", plantSpeciesID, "plant type id", plantTypeID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully unset plant species from plant type", "admin id", requestUserID, "plant species id", plantSpeciesID, "plant type id", plantTypeID)
 	respondWithJSON(http.StatusOK, unsetResponse, w, cfg.sl)
 }
@@ -55,7 +55,7 @@ type AdminUnsetWaterResponse struct {
 func (cfg *apiConfig) adminWaterCreateHandler(w http.ResponseWriter, r *http.Request) {
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -63,7 +63,7 @@ func (cfg *apiConfig) adminWaterCreateHandler(w http.ResponseWriter, r *http.Req
 	var createRequest AdminWaterCreateRequest
 	err = json.NewDecoder(r.Body).Decode(&createRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -71,12 +71,12 @@ func (cfg *apiConfig) adminWaterCreateHandler(w http.ResponseWriter, r *http.Req
 
 	// checking body
 	if createRequest.PlantType == "" {
-		cfg.sl.Debug("Request body missing plant type")
+		cfg.sl.DebugContext(r.Context(), "Request body missing plant type")
 		respondWithError(errors.New("no plant type provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	if createRequest.Description == "" {
-		cfg.sl.Debug("Request body missing description")
+		cfg.sl.DebugContext(r.Context(), "Request body missing description")
 		respondWithError(errors.New("no description provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -88,7 +88,7 @@ func (cfg *apiConfig) adminWaterCreateHandler(w http.ResponseWriter, r *http.Req
 
 	if dayRequest {
 		if createRequest.DrySoilDays == nil {
-			cfg.sl.Debug("Request body missing dry soil days")
+			cfg.sl.DebugContext(r.Context(), "Request body missing dry soil days")
 			respondWithError(errors.New("no dry soil days provided"), http.StatusBadRequest, w, cfg.sl)
 			return
 		}
@@ -102,7 +102,7 @@ func (cfg *apiConfig) adminWaterCreateHandler(w http.ResponseWriter, r *http.Req
 		}
 		waterRecord, err := cfg.db.CreateWaterDryDays(r.Context(), createParams)
 		if err != nil {
-			cfg.sl.Debug("Could not create water record", "error", err)
+			cfg.sl.DebugContext(r.Context(), "Could not create water record", "error", err)
 			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 			return
 		}
@@ -114,7 +114,7 @@ func (cfg *apiConfig) adminWaterCreateHandler(w http.ResponseWriter, r *http.Req
 
 	} else if mmRequest {
 		if createRequest.DrySoilMM == nil {
-			cfg.sl.Debug("Request body missing dry soil mm")
+			cfg.sl.DebugContext(r.Context(), "Request body missing dry soil mm")
 			respondWithError(errors.New("no dry soil mm provided"), http.StatusBadRequest, w, cfg.sl)
 			return
 		}
@@ -128,7 +128,7 @@ func (cfg *apiConfig) adminWaterCreateHandler(w http.ResponseWriter, r *http.Req
 		}
 		waterRecord, err := cfg.db.CreateWaterDryMM(r.Context(), createParams)
 		if err != nil {
-			cfg.sl.Debug("Could not create water record", "error", err)
+			cfg.sl.DebugContext(r.Context(), "Could not create water record", "error", err)
 			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 			return
 		}
@@ -139,26 +139,26 @@ func (cfg *apiConfig) adminWaterCreateHandler(w http.ResponseWriter, r *http.Req
 		waterResponse.DrySoilMM = &waterRecord.DrySoilMm.Int32
 
 	} else {
-		cfg.sl.Debug("Invalid plant type provided", "plant type", createRequest.PlantType)
+		cfg.sl.DebugContext(r.Context(), "Invalid plant type provided", "plant type", createRequest.PlantType)
 		respondWithError(errors.New("invalid plant type"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Debug("Admin successfully created water need", "admin id", requestUserID, "water need id", waterResponse.ID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully created water need", "admin id", requestUserID, "water need id", waterResponse.ID)
 	respondWithJSON(http.StatusCreated, waterResponse, w, cfg.sl)
 }
 
 func (cfg *apiConfig) adminWaterViewHandler(w http.ResponseWriter, r *http.Request) {
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
 	waterRecords, err := cfg.db.GetAllWaterNeedsOrderedByCreated(r.Context())
 	if err != nil {
-		cfg.sl.Debug("Could not get water needs records", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get water needs records", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -188,11 +188,11 @@ func (cfg *apiConfig) adminWaterViewHandler(w http.ResponseWriter, r *http.Reque
 			}
 			waterResponses = append(waterResponses, newRecord)
 		} else {
-			cfg.sl.Warn("Unknown plant type returned from database", "plant type id", record.ID, "plant type", record.PlantType)
+			cfg.sl.WarnContext(r.Context(), "Unknown plant type returned from database", "plant type id", record.ID, "plant type", record.PlantType)
 		}
 	}
 
-	cfg.sl.Debug("Admin successfully listed water needs list", "admin id", requestUserID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully listed water needs list", "admin id", requestUserID)
 	respondWithJSON(http.StatusOK, waterResponses, w, cfg.sl)
 }
 
@@ -200,7 +200,7 @@ func (cfg *apiConfig) adminWaterViewHandler(w http.ResponseWriter, r *http.Reque
 func (cfg *apiConfig) adminWaterDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -209,7 +209,7 @@ func (cfg *apiConfig) adminWaterDeleteHandler(w http.ResponseWriter, r *http.Req
 	waterIDStr := r.PathValue("waterID")
 	waterID, err := uuid.Parse(waterIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse water id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse water id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -222,13 +222,13 @@ func (cfg *apiConfig) adminWaterDeleteHandler(w http.ResponseWriter, r *http.Req
 	}
 	err = cfg.db.MarkWaterNeedAsDeletedByID(r.Context(), deleteParams)
 	if err != nil {
-		cfg.sl.Debug("Could not mark water as deleted", "error", err, "water id", waterID)
+		cfg.sl.DebugContext(r.Context(), "Could not mark water as deleted", "error", err, "water id", waterID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
 	// respond with 204
-	cfg.sl.Debug("Admin successfully marked plant water as deleted", "admin id", requestUserID, "water id", waterID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully marked plant water as deleted", "admin id", requestUserID, "water id", waterID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -238,7 +238,7 @@ func (cfg *apiConfig) adminSetPlantAsWaterNeedHandler(w http.ResponseWriter, r *
 	waterIDStr := r.PathValue("waterID")
 	waterID, err := uuid.Parse(waterIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse water id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse water id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -246,13 +246,13 @@ func (cfg *apiConfig) adminSetPlantAsWaterNeedHandler(w http.ResponseWriter, r *
 	// plant species
 	plantSpeciesIDStr := r.URL.Query().Get("plant-species-id")
 	if plantSpeciesIDStr == "" {
-		cfg.sl.Debug("No plant species id was provided in url query")
+		cfg.sl.DebugContext(r.Context(), "No plant species id was provided in url query")
 		respondWithError(errors.New("no plant species id was provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	plantSpeciesID, err := uuid.Parse(plantSpeciesIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant species id from url query", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant species id from url query", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -260,7 +260,7 @@ func (cfg *apiConfig) adminSetPlantAsWaterNeedHandler(w http.ResponseWriter, r *
 	// user id
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -274,7 +274,7 @@ func (cfg *apiConfig) adminSetPlantAsWaterNeedHandler(w http.ResponseWriter, r *
 	}
 	waterRecord, err := cfg.db.SetPlantSpeciesAsWaterNeed(r.Context(), setParams)
 	if err != nil {
-		cfg.sl.Debug("Could not set water for plant species", "error", err, "water id", waterID, "plant species id", plantSpeciesID)
+		cfg.sl.DebugContext(r.Context(), "Could not set water for plant species", "error", err, "water id", waterID, "plant species id", plantSpeciesID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -285,7 +285,7 @@ func (cfg *apiConfig) adminSetPlantAsWaterNeedHandler(w http.ResponseWriter, r *
 		PlantSpeciesName: waterRecord.SpeciesName,
 	}
 
-	cfg.sl.Debug("Admin successfully set plant species to water need", "admin id", requestUserID, "plant species id", plantSpeciesID, "water need", waterID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully set plant species to water need", "admin id", requestUserID, "plant species id", plantSpeciesID, "water need", waterID)
 	respondWithJSON(http.StatusOK, waterResponse, w, cfg.sl)
 }
 
@@ -295,7 +295,7 @@ func (cfg *apiConfig) adminUnsetPlantAsWaterNeedHandler(w http.ResponseWriter, r
 	waterIDStr := r.PathValue("waterID")
 	waterID, err := uuid.Parse(waterIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse water id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse water id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -303,13 +303,13 @@ func (cfg *apiConfig) adminUnsetPlantAsWaterNeedHandler(w http.ResponseWriter, r
 	// plant species
 	plantSpeciesIDStr := r.URL.Query().Get("plant-species-id")
 	if plantSpeciesIDStr == "" {
-		cfg.sl.Debug("No plant species id was provided in url query")
+		cfg.sl.DebugContext(r.Context(), "No plant species id was provided in url query")
 		respondWithError(errors.New("no plant species id was provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	plantSpeciesID, err := uuid.Parse(plantSpeciesIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant species id from url query", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant species id from url query", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -317,7 +317,7 @@ func (cfg *apiConfig) adminUnsetPlantAsWaterNeedHandler(w http.ResponseWriter, r
 	// user id
 	requestUserID, err := cfg.getUserIDFromToken(r)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get user id from token", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -329,7 +329,7 @@ func (cfg *apiConfig) adminUnsetPlantAsWaterNeedHandler(w http.ResponseWriter, r
 	}
 	waterRecord, err := cfg.db.UnsetPlantSpeciesAsWaterNeed(r.Context(), unsetParams)
 	if err != nil {
-		cfg.sl.Debug("Could not unset water need for plant species", "error", err, "water id", waterID, "plant species id", plantSpeciesID)
+		cfg.sl.DebugContext(r.Context(), "Could not unset water need for plant species", "error", err, "water id", waterID, "plant species id", plantSpeciesID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -339,6 +339,6 @@ func (cfg *apiConfig) adminUnsetPlantAsWaterNeedHandler(w http.ResponseWriter, r
 		PlantSpeciesName: waterRecord.SpeciesName,
 	}
 
-	cfg.sl.Debug("Admin successfully unset plant species to water need", "admin id", requestUserID, "plant species id", plantSpeciesID, "water need", waterID)
+	cfg.sl.DebugContext(r.Context(), "Admin successfully unset plant species to water need", "admin id", requestUserID, "plant species id", plantSpeciesID, "water need", waterID)
 	respondWithJSON(http.StatusOK, waterResponse, w, cfg.sl)
 }
@@ -0,0 +1,519 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// catalogArchiveVersion is bumped whenever the archive format changes
+const catalogArchiveVersion = 1
+
+// How an import handles records whose id is already in the database
+const (
+	catalogConflictFail      = "fail"
+	catalogConflictSkip      = "skip"
+	catalogConflictOverwrite = "overwrite"
+)
+
+var errCatalogConflict = errors.New("record already exists")
+
+// === archive types ===
+
+// CatalogArchive is a backup of the universal plant data, the species, their common names,
+// and the plant types, light needs, and water needs they link to.
+type CatalogArchive struct {
+	Version      int                          `json:"version"`
+	ExportedAt   time.Time                    `json:"exportedAt"`
+	PlantTypes   []CatalogArchivePlantType    `json:"plantTypes"`
+	LightNeeds   []CatalogArchiveLightNeed    `json:"lightNeeds"`
+	WaterNeeds   []CatalogArchiveWaterNeed    `json:"waterNeeds"`
+	PlantSpecies []CatalogArchivePlantSpecies `json:"plantSpecies"`
+	PlantNames   []CatalogArchivePlantName    `json:"plantNames"`
+}
+
+// CatalogArchiveRecord holds the id and audit columns every archived record keeps
+type CatalogArchiveRecord struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	UpdatedBy uuid.UUID `json:"updatedBy"`
+}
+
+type CatalogArchivePlantType struct {
+	CatalogArchiveRecord
+	Name                  string  `json:"name"`
+	Description           string  `json:"description"`
+	MaxTemperatureCelsius *int32  `json:"maxTemperatureCelsius"`
+	MinTemperatureCelsius *int32  `json:"minTemperatureCelsius"`
+	MaxHumidityPercent    *int32  `json:"maxHumidityPercent"`
+	MinHumidityPercent    *int32  `json:"minHumidityPercent"`
+	SoilOrganicMix        *string `json:"soilOrganicMix"`
+	SoilGritMix           *string `json:"soilGritMix"`
+	SoilDrainageMix       *string `json:"soilDrainageMix"`
+}
+
+type CatalogArchiveLightNeed struct {
+	CatalogArchiveRecord
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CatalogArchiveWaterNeed struct {
+	CatalogArchiveRecord
+	PlantType   string `json:"plantType"`
+	Description string `json:"description"`
+	DrySoilMM   *int32 `json:"drySoilMM"`
+	DrySoilDays *int32 `json:"drySoilDays"`
+}
+
+type CatalogArchivePlantSpecies struct {
+	CatalogArchiveRecord
+	SpeciesName      string     `json:"speciesName"`
+	HumanPoisonToxic *bool      `json:"humanPoisonToxic"`
+	PetPoisonToxic   *bool      `json:"petPoisonToxic"`
+	HumanEdible      *bool      `json:"humanEdible"`
+	PetEdible        *bool      `json:"petEdible"`
+	PlantTypeID      *uuid.UUID `json:"plantTypeID"`
+	LightNeedsID     *uuid.UUID `json:"lightNeedsID"`
+	WaterNeedsID     *uuid.UUID `json:"waterNeedsID"`
+}
+
+type CatalogArchivePlantName struct {
+	CatalogArchiveRecord
+	PlantID    uuid.UUID `json:"plantID"`
+	LangCode   *string   `json:"langCode"`
+	CommonName *string   `json:"commonName"`
+}
+
+// CatalogImportTableReport counts what happened to the archived records of one table
+type CatalogImportTableReport struct {
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
+
+type CatalogImportReport struct {
+	DryRun       bool                     `json:"dryRun"`
+	Conflict     string                   `json:"conflict"`
+	PlantTypes   CatalogImportTableReport `json:"plantTypes"`
+	LightNeeds   CatalogImportTableReport `json:"lightNeeds"`
+	WaterNeeds   CatalogImportTableReport `json:"waterNeeds"`
+	PlantSpecies CatalogImportTableReport `json:"plantSpecies"`
+	PlantNames   CatalogImportTableReport `json:"plantNames"`
+}
+
+// === archive functions ===
+
+// streams every record of the catalog that is not deleted to w, as the json of a CatalogArchive.
+// each table is read and written before the next one is read, and flushed to the client after it is written.
+// returns how many records were written
+func writeCatalogArchive(ctx context.Context, q *database.Queries, w io.Writer, flush func() error, exportedAt time.Time) (int, error) {
+	bw := bufio.NewWriter(w)
+	exportedAtJSON, err := json.Marshal(exportedAt)
+	if err != nil {
+		return 0, err
+	}
+	_, err = fmt.Fprintf(bw, `{"version":%d,"exportedAt":%s`, catalogArchiveVersion, exportedAtJSON)
+	if err != nil {
+		return 0, err
+	}
+
+	records := 0
+	// writes a table, then flushes it through to the client
+	table := func(count int, err error) error {
+		if err != nil {
+			return err
+		}
+		records += count
+		err = bw.Flush()
+		if err != nil {
+			return err
+		}
+		return flush()
+	}
+
+	plantTypes, err := q.ExportPlantTypes(ctx)
+	if err != nil {
+		return records, fmt.Errorf("exporting plant types: %w", err)
+	}
+	err = table(writeArchiveTable(bw, "plantTypes", plantTypes, func(record database.ExportPlantTypesRow) CatalogArchivePlantType {
+		return CatalogArchivePlantType{
+			CatalogArchiveRecord:  archiveRecord(record.ID, record.CreatedAt, record.UpdatedAt, record.CreatedBy, record.UpdatedBy),
+			Name:                  record.Name,
+			Description:           record.Description,
+			MaxTemperatureCelsius: nullInt32Ptr(record.MaxTemperatureCelsius),
+			MinTemperatureCelsius: nullInt32Ptr(record.MinTemperatureCelsius),
+			MaxHumidityPercent:    nullInt32Ptr(record.MaxHumidityPercent),
+			MinHumidityPercent:    nullInt32Ptr(record.MinHumidityPercent),
+			SoilOrganicMix:        nullStringPtr(record.SoilOrganicMix),
+			SoilGritMix:           nullStringPtr(record.SoilGritMix),
+			SoilDrainageMix:       nullStringPtr(record.SoilDrainageMix),
+		}
+	}))
+	if err != nil {
+		return records, fmt.Errorf("writing plant types: %w", err)
+	}
+
+	lightNeeds, err := q.ExportLightNeeds(ctx)
+	if err != nil {
+		return records, fmt.Errorf("exporting light needs: %w", err)
+	}
+	err = table(writeArchiveTable(bw, "lightNeeds", lightNeeds, func(record database.ExportLightNeedsRow) CatalogArchiveLightNeed {
+		return CatalogArchiveLightNeed{
+			CatalogArchiveRecord: archiveRecord(record.ID, record.CreatedAt, record.UpdatedAt, record.CreatedBy, record.UpdatedBy),
+			Name:                 record.Name,
+			Description:          record.Description,
+		}
+	}))
+	if err != nil {
+		return records, fmt.Errorf("writing light needs: %w", err)
+	}
+
+	waterNeeds, err := q.ExportWaterNeeds(ctx)
+	if err != nil {
+		return records, fmt.Errorf("exporting water needs: %w", err)
+	}
+	err = table(writeArchiveTable(bw, "waterNeeds", waterNeeds, func(record database.ExportWaterNeedsRow) CatalogArchiveWaterNeed {
+		return CatalogArchiveWaterNeed{
+			CatalogArchiveRecord: archiveRecord(record.ID, record.CreatedAt, record.UpdatedAt, record.CreatedBy, record.UpdatedBy),
+			PlantType:            record.PlantType,
+			Description:          record.Description,
+			DrySoilMM:            nullInt32Ptr(record.DrySoilMm),
+			DrySoilDays:          nullInt32Ptr(record.DrySoilDays),
+		}
+	}))
+	if err != nil {
+		return records, fmt.Errorf("writing water needs: %w", err)
+	}
+
+	plantSpecies, err := q.ExportPlantSpecies(ctx)
+	if err != nil {
+		return records, fmt.Errorf("exporting plant species: %w", err)
+	}
+	err = table(writeArchiveTable(bw, "plantSpecies", plantSpecies, func(record database.ExportPlantSpeciesRow) CatalogArchivePlantSpecies {
+		return CatalogArchivePlantSpecies{
+			CatalogArchiveRecord: archiveRecord(record.ID, record.CreatedAt, record.UpdatedAt, record.CreatedBy, record.UpdatedBy),
+			SpeciesName:          record.SpeciesName,
+			HumanPoisonToxic:     nullBoolPtr(record.HumanPoisonToxic),
+			PetPoisonToxic:       nullBoolPtr(record.PetPoisonToxic),
+			HumanEdible:          nullBoolPtr(record.HumanEdible),
+			PetEdible:            nullBoolPtr(record.PetEdible),
+			PlantTypeID:          nullUUIDPtr(record.PlantTypeID),
+			LightNeedsID:         nullUUIDPtr(record.LightNeedsID),
+			WaterNeedsID:         nullUUIDPtr(record.WaterNeedsID),
+		}
+	}))
+	if err != nil {
+		return records, fmt.Errorf("writing plant species: %w", err)
+	}
+
+	plantNames, err := q.ExportPlantNames(ctx)
+	if err != nil {
+		return records, fmt.Errorf("exporting plant names: %w", err)
+	}
+	err = table(writeArchiveTable(bw, "plantNames", plantNames, func(record database.ExportPlantNamesRow) CatalogArchivePlantName {
+		return CatalogArchivePlantName{
+			CatalogArchiveRecord: archiveRecord(record.ID, record.CreatedAt, record.UpdatedAt, record.CreatedBy, record.UpdatedBy),
+			PlantID:              record.PlantID,
+			LangCode:             nullStringPtr(record.LangCode),
+			CommonName:           nullStringPtr(record.CommonName),
+		}
+	}))
+	if err != nil {
+		return records, fmt.Errorf("writing plant names: %w", err)
+	}
+
+	_, err = bw.WriteString("}\n")
+	if err != nil {
+		return records, err
+	}
+	return records, table(0, nil)
+}
+
+// writes the records of a table as a field of the archive object, converting each one as it is encoded
+func writeArchiveTable[R, A any](w *bufio.Writer, field string, records []R, convert func(R) A) (int, error) {
+	_, err := fmt.Fprintf(w, `,%q:[`, field)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, record := range records {
+		if i > 0 {
+			err = w.WriteByte(',')
+			if err != nil {
+				return i, err
+			}
+		}
+		data, err := json.Marshal(convert(record))
+		if err != nil {
+			return i, err
+		}
+		_, err = w.Write(data)
+		if err != nil {
+			return i, err
+		}
+	}
+
+	err = w.WriteByte(']')
+	return len(records), err
+}
+
+// checks the archive can be imported before touching the database
+func (archive CatalogArchive) validate() error {
+	if archive.Version != catalogArchiveVersion {
+		return fmt.Errorf("archive version %d is not supported, expected version %d", archive.Version, catalogArchiveVersion)
+	}
+
+	for _, record := range archive.PlantTypes {
+		if record.ID == uuid.Nil || record.Name == "" || record.Description == "" {
+			return fmt.Errorf("plant type %q is missing an id, name, or description", record.ID)
+		}
+	}
+	for _, record := range archive.LightNeeds {
+		if record.ID == uuid.Nil || record.Name == "" || record.Description == "" {
+			return fmt.Errorf("light need %q is missing an id, name, or description", record.ID)
+		}
+	}
+	for _, record := range archive.WaterNeeds {
+		if record.ID == uuid.Nil || record.PlantType == "" || record.Description == "" {
+			return fmt.Errorf("water need %q is missing an id, plant type, or description", record.ID)
+		}
+	}
+	for _, record := range archive.PlantSpecies {
+		if record.ID == uuid.Nil || record.SpeciesName == "" {
+			return fmt.Errorf("plant species %q is missing an id or species name", record.ID)
+		}
+	}
+	for _, record := range archive.PlantNames {
+		if record.ID == uuid.Nil || record.PlantID == uuid.Nil {
+			return fmt.Errorf("plant name %q is missing an id or plant id", record.ID)
+		}
+	}
+
+	return nil
+}
+
+// writes the archive into the database, the caller owns the transaction q runs in
+// linked tables are imported before the species and names that refer to them
+func importCatalogArchive(ctx context.Context, q *database.Queries, archive CatalogArchive, conflict string) (CatalogImportReport, error) {
+	report := CatalogImportReport{Conflict: conflict}
+	overwrite := conflict == catalogConflictOverwrite
+
+	for _, record := range archive.PlantTypes {
+		inserted, err := q.ImportPlantType(ctx, database.ImportPlantTypeParams{
+			ID:                    record.ID,
+			CreatedAt:             record.CreatedAt,
+			UpdatedAt:             record.UpdatedAt,
+			CreatedBy:             record.CreatedBy,
+			UpdatedBy:             record.UpdatedBy,
+			Name:                  record.Name,
+			Description:           record.Description,
+			MaxTemperatureCelsius: ptrNullInt32(record.MaxTemperatureCelsius),
+			MinTemperatureCelsius: ptrNullInt32(record.MinTemperatureCelsius),
+			MaxHumidityPercent:    ptrNullInt32(record.MaxHumidityPercent),
+			MinHumidityPercent:    ptrNullInt32(record.MinHumidityPercent),
+			SoilOrganicMix:        ptrNullString(record.SoilOrganicMix),
+			SoilGritMix:           ptrNullString(record.SoilGritMix),
+			SoilDrainageMix:       ptrNullString(record.SoilDrainageMix),
+			Overwrite:             overwrite,
+		})
+		err = report.PlantTypes.count(inserted, err, conflict)
+		if err != nil {
+			return CatalogImportReport{}, fmt.Errorf("importing plant type %q: %w", record.ID, err)
+		}
+	}
+
+	for _, record := range archive.LightNeeds {
+		inserted, err := q.ImportLightNeed(ctx, database.ImportLightNeedParams{
+			ID:          record.ID,
+			CreatedAt:   record.CreatedAt,
+			UpdatedAt:   record.UpdatedAt,
+			CreatedBy:   record.CreatedBy,
+			UpdatedBy:   record.UpdatedBy,
+			Name:        record.Name,
+			Description: record.Description,
+			Overwrite:   overwrite,
+		})
+		err = report.LightNeeds.count(inserted, err, conflict)
+		if err != nil {
+			return CatalogImportReport{}, fmt.Errorf("importing light need %q: %w", record.ID, err)
+		}
+	}
+
+	for _, record := range archive.WaterNeeds {
+		inserted, err := q.ImportWaterNeed(ctx, database.ImportWaterNeedParams{
+			ID:          record.ID,
+			CreatedAt:   record.CreatedAt,
+			UpdatedAt:   record.UpdatedAt,
+			CreatedBy:   record.CreatedBy,
+			UpdatedBy:   record.UpdatedBy,
+			PlantType:   record.PlantType,
+			Description: record.Description,
+			DrySoilMm:   ptrNullInt32(record.DrySoilMM),
+			DrySoilDays: ptrNullInt32(record.DrySoilDays),
+			Overwrite:   overwrite,
+		})
+		err = report.WaterNeeds.count(inserted, err, conflict)
+		if err != nil {
+			return CatalogImportReport{}, fmt.Errorf("importing water need %q: %w", record.ID, err)
+		}
+	}
+
+	for _, record := range archive.PlantSpecies {
+		inserted, err := q.ImportPlantSpecies(ctx, database.ImportPlantSpeciesParams{
+			ID:               record.ID,
+			CreatedAt:        record.CreatedAt,
+			UpdatedAt:        record.UpdatedAt,
+			CreatedBy:        record.CreatedBy,
+			UpdatedBy:        record.UpdatedBy,
+			SpeciesName:      record.SpeciesName,
+			HumanPoisonToxic: ptrNullBool(record.HumanPoisonToxic),
+			PetPoisonToxic:   ptrNullBool(record.PetPoisonToxic),
+			HumanEdible:      ptrNullBool(record.HumanEdible),
+			PetEdible:        ptrNullBool(record.PetEdible),
+			PlantTypeID:      ptrNullUUID(record.PlantTypeID),
+			LightNeedsID:     ptrNullUUID(record.LightNeedsID),
+			WaterNeedsID:     ptrNullUUID(record.WaterNeedsID),
+			Overwrite:        overwrite,
+		})
+		err = report.PlantSpecies.count(inserted, err, conflict)
+		if err != nil {
+			return CatalogImportReport{}, fmt.Errorf("importing plant species %q: %w", record.ID, err)
+		}
+	}
+
+	for _, record := range archive.PlantNames {
+		inserted, err := q.ImportPlantName(ctx, database.ImportPlantNameParams{
+			ID:         record.ID,
+			CreatedAt:  record.CreatedAt,
+			UpdatedAt:  record.UpdatedAt,
+			CreatedBy:  record.CreatedBy,
+			UpdatedBy:  record.UpdatedBy,
+			PlantID:    record.PlantID,
+			LangCode:   ptrNullString(record.LangCode),
+			CommonName: ptrNullString(record.CommonName),
+			Overwrite:  overwrite,
+		})
+		err = report.PlantNames.count(inserted, err, conflict)
+		if err != nil {
+			return CatalogImportReport{}, fmt.Errorf("importing plant name %q: %w", record.ID, err)
+		}
+	}
+
+	return report, nil
+}
+
+// counts the result of importing one record
+// no row is returned when the id already existed and was not overwritten
+func (tableReport *CatalogImportTableReport) count(inserted bool, err error, conflict string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		if conflict == catalogConflictFail {
+			return errCatalogConflict
+		}
+		tableReport.Skipped++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if inserted {
+		tableReport.Inserted++
+	} else {
+		tableReport.Updated++
+	}
+	return nil
+}
+
+// returns true when an import failed because of the data in the archive rather than the server
+// such as an id conflict, a duplicate unique name, or a link to a record that does not exist
+func catalogImportRejected(err error) bool {
+	if errors.Is(err, errCatalogConflict) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Name() == "unique_violation" || pqErr.Code.Name() == "foreign_key_violation"
+	}
+	return false
+}
+
+// === conversion helpers ===
+
+func archiveRecord(id uuid.UUID, createdAt, updatedAt time.Time, createdBy, updatedBy uuid.UUID) CatalogArchiveRecord {
+	return CatalogArchiveRecord{
+		ID:        id,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		CreatedBy: createdBy,
+		UpdatedBy: updatedBy,
+	}
+}
+
+func nullInt32Ptr(value sql.NullInt32) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int32
+}
+
+func nullStringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func nullBoolPtr(value sql.NullBool) *bool {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Bool
+}
+
+func nullUUIDPtr(value uuid.NullUUID) *uuid.UUID {
+	if !value.Valid {
+		return nil
+	}
+	return &value.UUID
+}
+
+func ptrNullInt32(value *int32) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *value, Valid: true}
+}
+
+func ptrNullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *value, Valid: true}
+}
+
+func ptrNullBool(value *bool) sql.NullBool {
+	if value == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *value, Valid: true}
+}
+
+func ptrNullUUID(value *uuid.UUID) uuid.NullUUID {
+	if value == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *value, Valid: true}
+}
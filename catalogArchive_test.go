@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestWriteArchiveTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []string
+		want    string
+	}{
+		{"empty table", nil, `,"lightNeeds":[]`},
+		{"records", []string{"low light", "bright light"}, `,"lightNeeds":[{"name":"low light"},{"name":"bright light"}]`},
+	}
+
+	for _, test := range tests {
+		var b strings.Builder
+		w := bufio.NewWriter(&b)
+		count, err := writeArchiveTable(w, "lightNeeds", test.records, func(name string) map[string]string {
+			return map[string]string{"name": name}
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		w.Flush()
+
+		if b.String() != test.want {
+			t.Errorf("%s: wrote %s, want %s", test.name, b.String(), test.want)
+		}
+		if count != len(test.records) {
+			t.Errorf("%s: count = %d, want %d", test.name, count, len(test.records))
+		}
+	}
+}
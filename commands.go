@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/auth"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// schemaFS bundles the goose migrations into the binary
+//
+//go:embed sql/schema/*.sql
+var schemaFS embed.FS
+
+const schemaDir = "sql/schema"
+
+const commandUsage = `usage: plantae <command> [arguments]
+
+commands:
+  serve                                         start the server, the default without a command
+  migrate up | down | status                    apply all, roll back one, or list the schema migrations
+  seed [-overwrite] [-dry-run]                  load the bundled example plant catalog
+  user create -email <email> [-lang <code>] [-admin]
+                                                create a user, the password is read from stdin
+  user promote -email <email>                   promote a user to admin
+  user demote -email <email>                    demote an admin to a user
+  token revoke -email <email> | -token <token>  revoke all of a user's refresh tokens, or a single one
+`
+
+// commands that operate on a loaded config, selected by the first argument
+var commands = map[string]func(cfg *apiConfig, args []string) error{
+	"serve": func(cfg *apiConfig, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected arguments: %v", args)
+		}
+		return runServer(cfg)
+	},
+	"migrate": runMigrate,
+	"seed":    runSeed,
+	"user":    runUser,
+	"token":   runToken,
+}
+
+// === Command Dispatch ===
+
+// runs the command named by the first argument, or the server when there is none
+func runCommand(args []string) error {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Print(commandUsage)
+		return nil
+	}
+
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprint(os.Stderr, commandUsage)
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	// migrate changes the schema, so it is the one command that runs without it being current
+	cfg, closeConfig, err := loadAPIConfig(name != "migrate")
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	defer closeConfig()
+
+	return command(cfg, args)
+}
+
+// splits a command with actions, such as 'user create', into the action and its arguments
+func commandAction(command string, args []string, actions ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%s needs one of: %s", command, strings.Join(actions, ", "))
+	}
+	for _, action := range actions {
+		if args[0] == action {
+			return action, args[1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("unknown %s action %q, expected one of: %s", command, args[0], strings.Join(actions, ", "))
+}
+
+// parses the flags of a command, extra positional arguments are rejected
+func parseCommandFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+	return nil
+}
+
+// === Migrate Command ===
+
+// applies, rolls back, or lists the migrations bundled into the binary
+// usage: plantae migrate up | down | status
+func runMigrate(cfg *apiConfig, args []string) error {
+	action, args, err := commandAction("migrate", args, "up", "down", "status")
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments: %v", args)
+	}
+
+	migrator := cfg.migrator
+	ctx := context.Background()
+
+	switch action {
+	case "up":
+		ran, err := migrator.Up(ctx)
+		for _, migration := range ran {
+			fmt.Printf("Applied %s\n", migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(ran) == 0 {
+			fmt.Printf("Schema is already at version %d\n", migrator.Latest())
+		}
+		cfg.sl.Info("Migrated schema up", "applied", len(ran), "version", migrator.Latest())
+
+	case "down":
+		migration, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %s\n", migration.Name)
+		cfg.sl.Info("Migrated schema down", "migration", migration.Name)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-20s %s\n", appliedAt, status.Name)
+		}
+	}
+
+	return nil
+}
+
+// === User Command ===
+
+// creates users and changes their admin status
+// usage: plantae user create | promote | demote -email <email>
+func runUser(cfg *apiConfig, args []string) error {
+	action, args, err := commandAction("user", args, "create", "promote", "demote")
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("user "+action, flag.ContinueOnError)
+	email := flags.String("email", "", "email of the user")
+	var langCode *string
+	var admin *bool
+	if action == "create" {
+		langCode = flags.String("lang", "en", "language preference of the user")
+		admin = flags.Bool("admin", false, "create the user as an admin")
+	}
+	err = parseCommandFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	ctx := context.Background()
+	switch action {
+	case "create":
+		return createUserCommand(ctx, cfg, *email, *langCode, *admin)
+	case "promote":
+		return setUserAdminCommand(ctx, cfg, *email, true)
+	default:
+		return setUserAdminCommand(ctx, cfg, *email, false)
+	}
+}
+
+func createUserCommand(ctx context.Context, cfg *apiConfig, email, langCode string, admin bool) error {
+	if _, ok := LangCodes[langCode]; !ok {
+		return fmt.Errorf("language code %q does not exist", langCode)
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	rawPassword, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	fmt.Fprintln(os.Stderr)
+	if err != nil && rawPassword == "" {
+		return fmt.Errorf("could not read password: %w", err)
+	}
+	rawPassword = strings.TrimRight(rawPassword, "\r\n")
+	if rawPassword == "" {
+		return errors.New("password is empty")
+	}
+
+	hashedPassword, err := auth.HashPassword(rawPassword, cfg.sl)
+	rawPassword = "" // GC collection
+	if err != nil {
+		return err
+	}
+
+	newUserUUID, err := uuid.NewUUID()
+	if err != nil {
+		return err
+	}
+
+	// the user and their admin status are created together
+	tx, err := cfg.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := cfg.db.WithTx(tx)
+
+	userRecord, err := q.CreateUser(ctx, database.CreateUserParams{
+		ID:             newUserUUID,
+		LangCodePref:   langCode,
+		Email:          email,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	if admin {
+		err = q.PromoteUserToAdminByID(ctx, database.PromoteUserToAdminByIDParams{
+			ID:        userRecord.ID,
+			UpdatedBy: uuid.Max,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	cfg.sl.InfoContext(ctx, "Created user from the command line", "user id", userRecord.ID, "is admin", admin)
+	fmt.Printf("Created user %s (%s), admin: %t\n", userRecord.Email, userRecord.ID, admin)
+	return nil
+}
+
+func setUserAdminCommand(ctx context.Context, cfg *apiConfig, email string, isAdmin bool) error {
+	userRecord, err := getUserByEmailCommand(ctx, cfg, email)
+	if err != nil {
+		return err
+	}
+
+	if userRecord.IsAdmin == isAdmin {
+		fmt.Printf("User %s already has admin: %t\n", userRecord.Email, isAdmin)
+		return nil
+	}
+
+	if isAdmin {
+		err = cfg.db.PromoteUserToAdminByID(ctx, database.PromoteUserToAdminByIDParams{
+			ID:        userRecord.ID,
+			UpdatedBy: uuid.Max,
+		})
+	} else {
+		err = cfg.db.DemoteUserFromAdminByID(ctx, database.DemoteUserFromAdminByIDParams{
+			ID:        userRecord.ID,
+			UpdatedBy: uuid.Max,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg.sl.InfoContext(ctx, "Changed admin status from the command line", "user id", userRecord.ID, "is admin", isAdmin)
+	fmt.Printf("User %s (%s), admin: %t\n", userRecord.Email, userRecord.ID, isAdmin)
+	return nil
+}
+
+func getUserByEmailCommand(ctx context.Context, cfg *apiConfig, email string) (database.GetUserByEmailWithoutPasswordRow, error) {
+	userRecord, err := cfg.db.GetUserByEmailWithoutPassword(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return userRecord, fmt.Errorf("no user with email %q", email)
+	}
+	return userRecord, err
+}
+
+// === Token Command ===
+
+// revokes refresh tokens, so the user has to log in again once their access token expires
+// usage: plantae token revoke -email <email> | -token <token>
+func runToken(cfg *apiConfig, args []string) error {
+	_, args, err := commandAction("token", args, "revoke")
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("token revoke", flag.ContinueOnError)
+	email := flags.String("email", "", "revoke every refresh token of the user with this email")
+	token := flags.String("token", "", "revoke a single refresh token")
+	err = parseCommandFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if (*email == "") == (*token == "") {
+		return errors.New("either -email or -token is required")
+	}
+
+	ctx := context.Background()
+	if *token != "" {
+		userID, err := cfg.db.RevokeRefreshTokenWithToken(ctx, database.RevokeRefreshTokenWithTokenParams{
+			TokenHash: auth.HashRefreshToken(*token),
+			UpdatedBy: uuid.Max,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("refresh token does not exist")
+		}
+		if err != nil {
+			return err
+		}
+
+		cfg.sl.Info("Revoked refresh token from the command line", "user id", userID)
+		fmt.Printf("Revoked refresh token of user %s\n", userID)
+		return nil
+	}
+
+	userRecord, err := getUserByEmailCommand(ctx, cfg, *email)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := cfg.db.RevokeAllRefreshTokensByUserID(ctx, database.RevokeAllRefreshTokensByUserIDParams{
+		UserID:    userRecord.ID,
+		UpdatedBy: uuid.Max,
+	})
+	if err != nil {
+		return err
+	}
+
+	cfg.sl.Info("Revoked refresh tokens from the command line", "user id", userRecord.ID, "revoked", revoked)
+	fmt.Printf("Revoked %d refresh tokens of user %s\n", revoked, userRecord.Email)
+	return nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/nicholasss/plantae/internal/migrate"
+)
+
+// version of the server, set at build time with
+// go build -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
+// how long the readiness check waits for the database to answer
+const healthDatabaseTimeout = time.Second * 2
+
+// Status values of a single health check
+const (
+	healthStatusOK       = "ok"
+	healthStatusFail     = "fail"
+	healthStatusDegraded = "degraded"
+	healthStatusDisabled = "disabled"
+)
+
+// === Response Types ===
+
+type HealthBuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+type HealthCheck struct {
+	Status          string     `json:"status"`
+	Error           string     `json:"error,omitempty"`
+	LatencyMS       *int64     `json:"latencyMS,omitempty"`
+	Version         *int64     `json:"version,omitempty"`
+	ExpectedVersion *int64     `json:"expectedVersion,omitempty"`
+	LastHeartbeat   *time.Time `json:"lastHeartbeat,omitempty"`
+}
+
+type HealthResponse struct {
+	Status    string                 `json:"status"`
+	Build     HealthBuildInfo        `json:"build"`
+	StartedAt time.Time              `json:"startedAt"`
+	Checks    map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// === Health Handlers ===
+
+// reports that the process is up and serving requests, without checking any dependency
+// GET /api/v1/health/live
+func (cfg *apiConfig) healthLiveHandler(w http.ResponseWriter, r *http.Request) {
+	healthResponse := HealthResponse{
+		Status:    healthStatusOK,
+		Build:     buildInfo(),
+		StartedAt: cfg.startedAt,
+	}
+
+	respondWithJSON(http.StatusOK, healthResponse, w, cfg.sl)
+}
+
+// reports whether the server can handle traffic, by checking the database, the schema version,
+// and the reminder scheduler. Responds with 503 when the database or migrations check fails,
+// a stalled scheduler is only reported as degraded, as the server still handles requests without it.
+// GET /api/v1/health/ready
+func (cfg *apiConfig) healthReadyHandler(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]HealthCheck{
+		"database":   cfg.checkDatabaseHealth(r.Context()),
+		"migrations": cfg.checkMigrationsHealth(r.Context()),
+		"scheduler":  cfg.checkSchedulerHealth(time.Now()),
+	}
+
+	healthResponse := HealthResponse{
+		Status:    healthStatusOK,
+		Build:     buildInfo(),
+		StartedAt: cfg.startedAt,
+		Checks:    checks,
+	}
+	code := http.StatusOK
+	for name, check := range checks {
+		switch check.Status {
+		case healthStatusFail:
+			cfg.sl.WarnContext(r.Context(), "Readiness check failed", "check", name, "error", check.Error)
+			healthResponse.Status = healthStatusFail
+			code = http.StatusServiceUnavailable
+		case healthStatusDegraded:
+			cfg.sl.WarnContext(r.Context(), "Readiness check degraded", "check", name, "error", check.Error)
+			if healthResponse.Status == healthStatusOK {
+				healthResponse.Status = healthStatusDegraded
+			}
+		}
+	}
+
+	respondWithJSON(code, healthResponse, w, cfg.sl)
+}
+
+// === Health Checks ===
+
+func (cfg *apiConfig) checkDatabaseHealth(ctx context.Context) HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthDatabaseTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := cfg.sqlDB.PingContext(ctx)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		return HealthCheck{Status: healthStatusFail, Error: "database did not respond", LatencyMS: &latency}
+	}
+
+	return HealthCheck{Status: healthStatusOK, LatencyMS: &latency}
+}
+
+func (cfg *apiConfig) checkMigrationsHealth(ctx context.Context) HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthDatabaseTimeout)
+	defer cancel()
+
+	expected := cfg.migrator.Latest()
+	applied, err := cfg.migrator.Version(ctx)
+	if errors.Is(err, migrate.ErrNoVersionTable) {
+		return HealthCheck{Status: healthStatusFail, Error: "database has not been migrated", ExpectedVersion: &expected}
+	} else if err != nil {
+		return HealthCheck{Status: healthStatusFail, Error: "could not read schema version", ExpectedVersion: &expected}
+	}
+
+	check := HealthCheck{Status: healthStatusOK, Version: &applied, ExpectedVersion: &expected}
+	if applied < expected {
+		check.Status = healthStatusFail
+		check.Error = "database schema is behind the server"
+	}
+	return check
+}
+
+// the scheduler is stale when it missed two scans in a row,
+// which degrades reminders but does not make the server unready
+func (cfg *apiConfig) checkSchedulerHealth(now time.Time) HealthCheck {
+	if cfg.reminderInterval <= 0 {
+		return HealthCheck{Status: healthStatusDisabled}
+	}
+
+	heartbeat := cfg.reminderHeartbeat.Load()
+	if heartbeat == 0 {
+		// the first scan has not finished yet
+		if now.Sub(cfg.startedAt) > cfg.reminderInterval*2 {
+			return HealthCheck{Status: healthStatusDegraded, Error: "reminder scheduler has not run"}
+		}
+		return HealthCheck{Status: healthStatusOK}
+	}
+
+	lastHeartbeat := time.Unix(0, heartbeat).UTC()
+	if now.Sub(lastHeartbeat) > cfg.reminderInterval*2 {
+		return HealthCheck{Status: healthStatusDegraded, Error: "reminder scheduler is stalled", LastHeartbeat: &lastHeartbeat}
+	}
+	return HealthCheck{Status: healthStatusOK, LastHeartbeat: &lastHeartbeat}
+}
+
+// reads the version and vcs revision stamped into the binary
+func buildInfo() HealthBuildInfo {
+	info := HealthBuildInfo{Version: version}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = buildInfo.GoVersion
+	for _, setting := range buildInfo.Settings {
+		if setting.Key == "vcs.revision" {
+			info.Revision = setting.Value
+		}
+	}
+	return info
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckSchedulerHealth(t *testing.T) {
+	startedAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	interval := time.Minute * 15
+
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		heartbeat time.Time
+		now       time.Time
+		status    string
+	}{
+		{name: "disabled", interval: 0, now: startedAt.Add(time.Hour), status: healthStatusDisabled},
+		{name: "first scan pending", interval: interval, now: startedAt.Add(interval), status: healthStatusOK},
+		{name: "first scan never finished", interval: interval, now: startedAt.Add(interval * 3), status: healthStatusDegraded},
+		{name: "recent scan", interval: interval, heartbeat: startedAt.Add(interval), now: startedAt.Add(interval * 2), status: healthStatusOK},
+		// a stalled scheduler must not fail readiness
+		{name: "stalled", interval: interval, heartbeat: startedAt.Add(interval), now: startedAt.Add(interval * 4), status: healthStatusDegraded},
+	}
+
+	for _, test := range tests {
+		cfg := &apiConfig{startedAt: startedAt, reminderInterval: test.interval}
+		if !test.heartbeat.IsZero() {
+			cfg.reminderHeartbeat.Store(test.heartbeat.UnixNano())
+		}
+
+		check := cfg.checkSchedulerHealth(test.now)
+		if check.Status != test.status {
+			t.Errorf("%s: status %q, want %q", test.name, check.Status, test.status)
+		}
+	}
+}
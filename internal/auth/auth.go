@@ -6,6 +6,7 @@ package auth
 import (
 	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
@@ -94,7 +95,8 @@ func GetBearerToken(headers http.Header, sl *slog.Logger) (string, error) {
 }
 
 // MakeJWT provides a fresh access token to a particular user for a given duration.
-func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration, sl *slog.Logger) (string, error) {
+// The session id is kept in the 'jti' claim, so requests can tell which session they belong to.
+func MakeJWT(userID, sessionID uuid.UUID, tokenSecret string, expiresIn time.Duration, sl *slog.Logger) (string, error) {
 	currentTime := time.Now().UTC()
 	expirationTime := currentTime.UTC().Add(expiresIn)
 
@@ -104,6 +106,7 @@ func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration, sl *
 		IssuedAt:  jwt.NewNumericDate(currentTime),
 		ExpiresAt: jwt.NewNumericDate(expirationTime),
 		Subject:   userID.String(),
+		ID:        sessionID.String(),
 	}
 	token := jwt.NewWithClaims(signingMethod, claims)
 
@@ -121,6 +124,14 @@ func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration, sl *
 // ValidateJWT checks a users access token and ensures that it is valid.
 // It will return a user id (uuid) when successful.
 func ValidateJWT(tokenString, tokenSecret string, sl *slog.Logger) (uuid.UUID, error) {
+	userID, _, err := ValidateSessionJWT(tokenString, tokenSecret, sl)
+	return userID, err
+}
+
+// ValidateSessionJWT checks a users access token and ensures that it is valid.
+// It will return a user id (uuid) and the session id (uuid) when successful,
+// the session id is uuid.Nil for tokens issued without one.
+func ValidateSessionJWT(tokenString, tokenSecret string, sl *slog.Logger) (uuid.UUID, uuid.UUID, error) {
 	claims := jwt.RegisteredClaims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, &claims,
@@ -131,20 +142,28 @@ func ValidateJWT(tokenString, tokenSecret string, sl *slog.Logger) (uuid.UUID, e
 			return []byte(tokenSecret), nil
 		})
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, uuid.Nil, err
 	}
 
 	userID, err := token.Claims.GetSubject()
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, uuid.Nil, err
 	}
 
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	sessionUUID := uuid.Nil
+	if claims.ID != "" {
+		sessionUUID, err = uuid.Parse(claims.ID)
+		if err != nil {
+			return uuid.Nil, uuid.Nil, err
+		}
 	}
 
-	return userUUID, nil
+	return userUUID, sessionUUID, nil
 }
 
 // MakeRefreshToken provides a fresh refresh token.
@@ -160,8 +179,21 @@ func MakeRefreshToken(sl *slog.Logger) (string, error) {
 	return secureString, nil
 }
 
+// HashRefreshToken provides the hex encoded SHA-256 of a refresh token.
+// Only the hash is stored, refresh tokens are random enough to not need a salt.
+func HashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
 // === User Password Functions ===
 
+// PasswordMaxBytes is the longest password bcrypt can hash, longer passwords are refused.
+const PasswordMaxBytes = 72
+
+// ErrPasswordTooLong is returned by HashPassword for passwords longer than PasswordMaxBytes.
+var ErrPasswordTooLong = fmt.Errorf("unable to hash password longer than %d bytes", PasswordMaxBytes)
+
 // HashPassword takes a raw password and returns a hashed version, utilizing bcrypt.
 func HashPassword(rawPassword string, sl *slog.Logger) (string, error) {
 	if rawPassword == "" {
@@ -171,8 +203,8 @@ func HashPassword(rawPassword string, sl *slog.Logger) (string, error) {
 
 	rawPasswordData := []byte(rawPassword)
 	rawPassword = "" // GC collection
-	if len(rawPasswordData) > 72 {
-		return "", errors.New("unable to hash password longer than 72 bytes")
+	if len(rawPasswordData) > PasswordMaxBytes {
+		return "", ErrPasswordTooLong
 	}
 
 	hashedPasswordData, err := bcrypt.GenerateFromPassword(rawPasswordData, bcrypt.DefaultCost)
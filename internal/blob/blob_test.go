@@ -0,0 +1,127 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	valid := []string{"photos/user/photo.jpg", "a", "a/b.c/d", "..photo.jpg", "photo..jpg"}
+	for _, key := range valid {
+		if err := validateKey(key); err != nil {
+			t.Errorf("validateKey(%q) = %v, want nil", key, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"/etc/passwd",
+		"../secret",
+		"photos/../../secret",
+		"photos/..",
+		"photos/./photo.jpg",
+		".",
+		"photos//photo.jpg",
+		"photos/",
+		"photos\\..\\secret",
+	}
+	for _, key := range invalid {
+		if err := validateKey(key); err == nil {
+			t.Errorf("validateKey(%q) = nil, want error", key)
+		}
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "blobs")
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	err = store.Put(ctx, "photos/user/1.jpg", []byte("photo"), "image/jpeg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "photos", "user", "1.jpg"))
+	if err != nil || string(data) != "photo" {
+		t.Fatalf("stored file = %q, %v, want %q", data, err, "photo")
+	}
+
+	// no temporary files are left behind
+	entries, err := os.ReadDir(filepath.Join(root, "photos", "user"))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("files after Put = %v, %v, want only the blob", entries, err)
+	}
+
+	body, err := store.Get(ctx, "photos/user/1.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = io.ReadAll(body)
+	body.Close()
+	if string(data) != "photo" {
+		t.Errorf("Get = %q, want %q", data, "photo")
+	}
+
+	err = store.Delete(ctx, "photos/user/1.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Get(ctx, "photos/user/1.jpg")
+	if err != ErrNotFound {
+		t.Errorf("Get after Delete = %v, want %v", err, ErrNotFound)
+	}
+
+	// deleting a missing blob is not an error
+	err = store.Delete(ctx, "photos/user/1.jpg")
+	if err != nil {
+		t.Errorf("Delete of a missing blob = %v, want nil", err)
+	}
+}
+
+func TestLocalStoreRejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "blobs")
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	secret := filepath.Join(dir, "secret")
+	err = os.WriteFile(secret, []byte("secret"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"../secret", "photos/../../secret", secret, "..\\secret"}
+	for _, key := range keys {
+		if err := store.Put(ctx, key, []byte("overwritten"), ""); err == nil {
+			t.Errorf("Put(%q) returned no error", key)
+		}
+		if _, err := store.Get(ctx, key); err == nil || err == ErrNotFound {
+			t.Errorf("Get(%q) = %v, want an invalid key error", key, err)
+		}
+		if err := store.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) returned no error", key)
+		}
+	}
+
+	data, err := os.ReadFile(secret)
+	if err != nil || string(data) != "secret" {
+		t.Errorf("file outside the store = %q, %v, want it untouched", data, err)
+	}
+}
+
+func TestNewLocalStore(t *testing.T) {
+	_, err := NewLocalStore("")
+	if err == nil {
+		t.Error("NewLocalStore without a directory returned no error")
+	}
+}
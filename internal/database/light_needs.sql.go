@@ -83,6 +83,29 @@ func (q *Queries) GetAllLightNeedsOrderedByCreated(ctx context.Context) ([]GetAl
 	return items, nil
 }
 
+const getLightNeedByID = `-- name: GetLightNeedByID :one
+select
+  id,
+  name,
+  description
+from light_needs
+  where id = $1
+  and deleted_at is null
+`
+
+type GetLightNeedByIDRow struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+}
+
+func (q *Queries) GetLightNeedByID(ctx context.Context, id uuid.UUID) (GetLightNeedByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getLightNeedByID, id)
+	var i GetLightNeedByIDRow
+	err := row.Scan(&i.ID, &i.Name, &i.Description)
+	return i, err
+}
+
 const markLightNeedAsDeletedByID = `-- name: MarkLightNeedAsDeletedByID :exec
 update light_needs
   set
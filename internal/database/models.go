@@ -11,6 +11,13 @@ import (
 	"github.com/google/uuid"
 )
 
+type EmailVerificationToken struct {
+	TokenHash string    `json:"tokenHash"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	UserID    uuid.UUID `json:"userID"`
+}
+
 type LightNeed struct {
 	ID          uuid.UUID     `json:"id"`
 	CreatedAt   time.Time     `json:"createdAt"`
@@ -23,6 +30,73 @@ type LightNeed struct {
 	Description string        `json:"description"`
 }
 
+type Location struct {
+	ID                    uuid.UUID      `json:"id"`
+	CreatedAt             time.Time      `json:"createdAt"`
+	UpdatedAt             time.Time      `json:"updatedAt"`
+	DeletedAt             sql.NullTime   `json:"deletedAt"`
+	CreatedBy             uuid.UUID      `json:"createdBy"`
+	UpdatedBy             uuid.UUID      `json:"updatedBy"`
+	DeletedBy             uuid.NullUUID  `json:"deletedBy"`
+	UserID                uuid.UUID      `json:"userID"`
+	Name                  string         `json:"name"`
+	LightExposure         sql.NullString `json:"lightExposure"`
+	MinTemperatureCelsius sql.NullInt32  `json:"minTemperatureCelsius"`
+	MaxTemperatureCelsius sql.NullInt32  `json:"maxTemperatureCelsius"`
+	MinHumidityPercent    sql.NullInt32  `json:"minHumidityPercent"`
+	MaxHumidityPercent    sql.NullInt32  `json:"maxHumidityPercent"`
+}
+
+type NotificationChannel struct {
+	ID          uuid.UUID     `json:"id"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+	DeletedAt   sql.NullTime  `json:"deletedAt"`
+	CreatedBy   uuid.UUID     `json:"createdBy"`
+	UpdatedBy   uuid.UUID     `json:"updatedBy"`
+	DeletedBy   uuid.NullUUID `json:"deletedBy"`
+	UserID      uuid.UUID     `json:"userID"`
+	ChannelType string        `json:"channelType"`
+	Target      string        `json:"target"`
+	Enabled     bool          `json:"enabled"`
+}
+
+type NotificationSetting struct {
+	UserID          uuid.UUID     `json:"userID"`
+	CreatedAt       time.Time     `json:"createdAt"`
+	UpdatedAt       time.Time     `json:"updatedAt"`
+	DeletedAt       sql.NullTime  `json:"deletedAt"`
+	CreatedBy       uuid.UUID     `json:"createdBy"`
+	UpdatedBy       uuid.UUID     `json:"updatedBy"`
+	DeletedBy       uuid.NullUUID `json:"deletedBy"`
+	QuietHoursStart sql.NullInt32 `json:"quietHoursStart"`
+	QuietHoursEnd   sql.NullInt32 `json:"quietHoursEnd"`
+	Timezone        string        `json:"timezone"`
+	LastRemindedAt  sql.NullTime  `json:"lastRemindedAt"`
+}
+
+type PasswordResetToken struct {
+	TokenHash string    `json:"tokenHash"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	UserID    uuid.UUID `json:"userID"`
+}
+
+type PlantCareEvent struct {
+	ID           uuid.UUID      `json:"id"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	DeletedAt    sql.NullTime   `json:"deletedAt"`
+	CreatedBy    uuid.UUID      `json:"createdBy"`
+	UpdatedBy    uuid.UUID      `json:"updatedBy"`
+	DeletedBy    uuid.NullUUID  `json:"deletedBy"`
+	UsersPlantID uuid.UUID      `json:"usersPlantID"`
+	UserID       uuid.UUID      `json:"userID"`
+	EventType    string         `json:"eventType"`
+	OccurredAt   time.Time      `json:"occurredAt"`
+	Notes        sql.NullString `json:"notes"`
+}
+
 type PlantName struct {
 	ID         uuid.UUID      `json:"id"`
 	CreatedAt  time.Time      `json:"createdAt"`
@@ -36,6 +110,24 @@ type PlantName struct {
 	CommonName sql.NullString `json:"commonName"`
 }
 
+type PlantPhoto struct {
+	ID           uuid.UUID     `json:"id"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+	DeletedAt    sql.NullTime  `json:"deletedAt"`
+	CreatedBy    uuid.UUID     `json:"createdBy"`
+	UpdatedBy    uuid.UUID     `json:"updatedBy"`
+	DeletedBy    uuid.NullUUID `json:"deletedBy"`
+	UsersPlantID uuid.UUID     `json:"usersPlantID"`
+	UserID       uuid.UUID     `json:"userID"`
+	ContentType  string        `json:"contentType"`
+	SizeBytes    int64         `json:"sizeBytes"`
+	Width        int32         `json:"width"`
+	Height       int32         `json:"height"`
+	BlobKey      string        `json:"blobKey"`
+	ThumbnailKey string        `json:"thumbnailKey"`
+}
+
 type PlantSpecy struct {
 	ID               uuid.UUID     `json:"id"`
 	CreatedAt        time.Time     `json:"createdAt"`
@@ -74,46 +166,56 @@ type PlantType struct {
 }
 
 type RefreshToken struct {
-	RefreshToken string        `json:"refreshToken"`
-	CreatedAt    time.Time     `json:"createdAt"`
-	UpdatedAt    time.Time     `json:"updatedAt"`
-	DeletedAt    sql.NullTime  `json:"deletedAt"`
-	CreatedBy    uuid.UUID     `json:"createdBy"`
-	UpdatedBy    uuid.UUID     `json:"updatedBy"`
-	DeletedBy    uuid.NullUUID `json:"deletedBy"`
-	RevokedAt    sql.NullTime  `json:"revokedAt"`
-	RevokedBy    uuid.NullUUID `json:"revokedBy"`
-	ExpiresAt    time.Time     `json:"expiresAt"`
-	UserID       uuid.UUID     `json:"userID"`
+	TokenHash  string         `json:"tokenHash"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+	DeletedAt  sql.NullTime   `json:"deletedAt"`
+	CreatedBy  uuid.UUID      `json:"createdBy"`
+	UpdatedBy  uuid.UUID      `json:"updatedBy"`
+	DeletedBy  uuid.NullUUID  `json:"deletedBy"`
+	RevokedAt  sql.NullTime   `json:"revokedAt"`
+	RevokedBy  uuid.NullUUID  `json:"revokedBy"`
+	ExpiresAt  time.Time      `json:"expiresAt"`
+	UserID     uuid.UUID      `json:"userID"`
+	FamilyID   uuid.UUID      `json:"familyID"`
+	ReplacedBy sql.NullString `json:"replacedBy"`
+	UserAgent  sql.NullString `json:"userAgent"`
+	IpAddress  sql.NullString `json:"ipAddress"`
+	LastUsedAt sql.NullTime   `json:"lastUsedAt"`
 }
 
 type User struct {
-	ID             uuid.UUID     `json:"id"`
-	CreatedAt      time.Time     `json:"createdAt"`
-	UpdatedAt      time.Time     `json:"updatedAt"`
-	DeletedAt      sql.NullTime  `json:"deletedAt"`
-	CreatedBy      uuid.UUID     `json:"createdBy"`
-	UpdatedBy      uuid.UUID     `json:"updatedBy"`
-	DeletedBy      uuid.NullUUID `json:"deletedBy"`
-	LangCodePref   string        `json:"langCodePref"`
-	JoinDate       time.Time     `json:"joinDate"`
-	IsAdmin        bool          `json:"isAdmin"`
-	Email          string        `json:"email"`
-	HashedPassword string        `json:"hashedPassword"`
+	ID              uuid.UUID     `json:"id"`
+	CreatedAt       time.Time     `json:"createdAt"`
+	UpdatedAt       time.Time     `json:"updatedAt"`
+	DeletedAt       sql.NullTime  `json:"deletedAt"`
+	CreatedBy       uuid.UUID     `json:"createdBy"`
+	UpdatedBy       uuid.UUID     `json:"updatedBy"`
+	DeletedBy       uuid.NullUUID `json:"deletedBy"`
+	LangCodePref    string        `json:"langCodePref"`
+	JoinDate        time.Time     `json:"joinDate"`
+	IsAdmin         bool          `json:"isAdmin"`
+	Email           string        `json:"email"`
+	HashedPassword  string        `json:"hashedPassword"`
+	EmailVerifiedAt sql.NullTime  `json:"emailVerifiedAt"`
 }
 
 type UsersPlant struct {
-	ID           uuid.UUID      `json:"id"`
-	CreatedAt    time.Time      `json:"createdAt"`
-	UpdatedAt    time.Time      `json:"updatedAt"`
-	DeletedAt    sql.NullTime   `json:"deletedAt"`
-	CreatedBy    uuid.UUID      `json:"createdBy"`
-	UpdatedBy    uuid.UUID      `json:"updatedBy"`
-	DeletedBy    uuid.NullUUID  `json:"deletedBy"`
-	PlantID      uuid.UUID      `json:"plantID"`
-	UserID       uuid.UUID      `json:"userID"`
-	AdoptionDate sql.NullTime   `json:"adoptionDate"`
-	Name         sql.NullString `json:"name"`
+	ID                     uuid.UUID      `json:"id"`
+	CreatedAt              time.Time      `json:"createdAt"`
+	UpdatedAt              time.Time      `json:"updatedAt"`
+	DeletedAt              sql.NullTime   `json:"deletedAt"`
+	CreatedBy              uuid.UUID      `json:"createdBy"`
+	UpdatedBy              uuid.UUID      `json:"updatedBy"`
+	DeletedBy              uuid.NullUUID  `json:"deletedBy"`
+	PlantID                uuid.UUID      `json:"plantID"`
+	UserID                 uuid.UUID      `json:"userID"`
+	AdoptionDate           sql.NullTime   `json:"adoptionDate"`
+	Name                   sql.NullString `json:"name"`
+	WaterIntervalDays      sql.NullInt32  `json:"waterIntervalDays"`
+	LightNeedsID           uuid.NullUUID  `json:"lightNeedsID"`
+	FertilizerIntervalDays sql.NullInt32  `json:"fertilizerIntervalDays"`
+	LocationID             uuid.NullUUID  `json:"locationID"`
 }
 
 type WaterNeed struct {
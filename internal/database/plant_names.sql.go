@@ -8,6 +8,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -61,39 +62,61 @@ func (q *Queries) CreatePlantName(ctx context.Context, arg CreatePlantNameParams
 	return i, err
 }
 
-const getAllPlantNamesForLanguageOrderedByCreated = `-- name: GetAllPlantNamesForLanguageOrderedByCreated :many
+const getAllPlantNamesOrderedByCreated = `-- name: GetAllPlantNamesOrderedByCreated :many
 select 
 	id,
   plant_id,
   lang_code,
-  common_name
+  common_name,
+  created_at, updated_at
 from plant_names
-  where lang_code ilike $1
-  and deleted_at is null
-  order by created_at desc
+  where deleted_at is null
+  and ($1::text is null or lang_code ilike $1::text)
+  and (
+    $2::timestamptz is null or
+    (created_at, id) < ($2::timestamptz, $3::uuid)
+  )
+  order by created_at desc, id desc
+  limit $4
 `
 
-type GetAllPlantNamesForLanguageOrderedByCreatedRow struct {
+type GetAllPlantNamesOrderedByCreatedParams struct {
+	LangCode   sql.NullString `json:"langCode"`
+	CursorTime sql.NullTime   `json:"cursorTime"`
+	CursorID   uuid.NullUUID  `json:"cursorID"`
+	RowLimit   int32          `json:"rowLimit"`
+}
+
+type GetAllPlantNamesOrderedByCreatedRow struct {
 	ID         uuid.UUID      `json:"id"`
 	PlantID    uuid.UUID      `json:"plantID"`
 	LangCode   sql.NullString `json:"langCode"`
 	CommonName sql.NullString `json:"commonName"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
 }
 
-func (q *Queries) GetAllPlantNamesForLanguageOrderedByCreated(ctx context.Context, langCode sql.NullString) ([]GetAllPlantNamesForLanguageOrderedByCreatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPlantNamesForLanguageOrderedByCreated, langCode)
+func (q *Queries) GetAllPlantNamesOrderedByCreated(ctx context.Context, arg GetAllPlantNamesOrderedByCreatedParams) ([]GetAllPlantNamesOrderedByCreatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPlantNamesOrderedByCreated,
+		arg.LangCode,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetAllPlantNamesForLanguageOrderedByCreatedRow
+	var items []GetAllPlantNamesOrderedByCreatedRow
 	for rows.Next() {
-		var i GetAllPlantNamesForLanguageOrderedByCreatedRow
+		var i GetAllPlantNamesOrderedByCreatedRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.PlantID,
 			&i.LangCode,
 			&i.CommonName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -108,38 +131,61 @@ func (q *Queries) GetAllPlantNamesForLanguageOrderedByCreated(ctx context.Contex
 	return items, nil
 }
 
-const getAllPlantNamesOrderedByCreated = `-- name: GetAllPlantNamesOrderedByCreated :many
+const getAllPlantNamesOrderedByUpdated = `-- name: GetAllPlantNamesOrderedByUpdated :many
 select 
 	id,
   plant_id,
   lang_code,
-  common_name
+  common_name,
+  created_at, updated_at
 from plant_names
   where deleted_at is null
-  order by created_at desc
+  and ($1::text is null or lang_code ilike $1::text)
+  and (
+    $2::timestamptz is null or
+    (updated_at, id) < ($2::timestamptz, $3::uuid)
+  )
+  order by updated_at desc, id desc
+  limit $4
 `
 
-type GetAllPlantNamesOrderedByCreatedRow struct {
+type GetAllPlantNamesOrderedByUpdatedParams struct {
+	LangCode   sql.NullString `json:"langCode"`
+	CursorTime sql.NullTime   `json:"cursorTime"`
+	CursorID   uuid.NullUUID  `json:"cursorID"`
+	RowLimit   int32          `json:"rowLimit"`
+}
+
+type GetAllPlantNamesOrderedByUpdatedRow struct {
 	ID         uuid.UUID      `json:"id"`
 	PlantID    uuid.UUID      `json:"plantID"`
 	LangCode   sql.NullString `json:"langCode"`
 	CommonName sql.NullString `json:"commonName"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
 }
 
-func (q *Queries) GetAllPlantNamesOrderedByCreated(ctx context.Context) ([]GetAllPlantNamesOrderedByCreatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPlantNamesOrderedByCreated)
+func (q *Queries) GetAllPlantNamesOrderedByUpdated(ctx context.Context, arg GetAllPlantNamesOrderedByUpdatedParams) ([]GetAllPlantNamesOrderedByUpdatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPlantNamesOrderedByUpdated,
+		arg.LangCode,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetAllPlantNamesOrderedByCreatedRow
+	var items []GetAllPlantNamesOrderedByUpdatedRow
 	for rows.Next() {
-		var i GetAllPlantNamesOrderedByCreatedRow
+		var i GetAllPlantNamesOrderedByUpdatedRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.PlantID,
 			&i.LangCode,
 			&i.CommonName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -73,9 +73,20 @@ select
 	human_edible, pet_edible
 from plant_species
   where deleted_at is null
-  order by created_at desc
+  and (
+    $1::timestamptz is null or
+    (created_at, id) < ($1::timestamptz, $2::uuid)
+  )
+  order by created_at desc, id desc
+  limit $3
 `
 
+type GetAllPlantSpeciesOrderedByCreatedParams struct {
+	CursorTime sql.NullTime  `json:"cursorTime"`
+	CursorID   uuid.NullUUID `json:"cursorID"`
+	RowLimit   int32         `json:"rowLimit"`
+}
+
 type GetAllPlantSpeciesOrderedByCreatedRow struct {
 	ID               uuid.UUID    `json:"id"`
 	CreatedAt        time.Time    `json:"createdAt"`
@@ -89,8 +100,8 @@ type GetAllPlantSpeciesOrderedByCreatedRow struct {
 	PetEdible        sql.NullBool `json:"petEdible"`
 }
 
-func (q *Queries) GetAllPlantSpeciesOrderedByCreated(ctx context.Context) ([]GetAllPlantSpeciesOrderedByCreatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPlantSpeciesOrderedByCreated)
+func (q *Queries) GetAllPlantSpeciesOrderedByCreated(ctx context.Context, arg GetAllPlantSpeciesOrderedByCreatedParams) ([]GetAllPlantSpeciesOrderedByCreatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPlantSpeciesOrderedByCreated, arg.CursorTime, arg.CursorID, arg.RowLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -131,9 +142,20 @@ select
 	human_edible, pet_edible
 from plant_species
   where deleted_at is null
-  order by updated_at desc
+  and (
+    $1::timestamptz is null or
+    (updated_at, id) < ($1::timestamptz, $2::uuid)
+  )
+  order by updated_at desc, id desc
+  limit $3
 `
 
+type GetAllPlantSpeciesOrderedByUpdatedParams struct {
+	CursorTime sql.NullTime  `json:"cursorTime"`
+	CursorID   uuid.NullUUID `json:"cursorID"`
+	RowLimit   int32         `json:"rowLimit"`
+}
+
 type GetAllPlantSpeciesOrderedByUpdatedRow struct {
 	ID               uuid.UUID    `json:"id"`
 	CreatedAt        time.Time    `json:"createdAt"`
@@ -147,8 +169,8 @@ type GetAllPlantSpeciesOrderedByUpdatedRow struct {
 	PetEdible        sql.NullBool `json:"petEdible"`
 }
 
-func (q *Queries) GetAllPlantSpeciesOrderedByUpdated(ctx context.Context) ([]GetAllPlantSpeciesOrderedByUpdatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPlantSpeciesOrderedByUpdated)
+func (q *Queries) GetAllPlantSpeciesOrderedByUpdated(ctx context.Context, arg GetAllPlantSpeciesOrderedByUpdatedParams) ([]GetAllPlantSpeciesOrderedByUpdatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPlantSpeciesOrderedByUpdated, arg.CursorTime, arg.CursorID, arg.RowLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -224,49 +246,6 @@ func (q *Queries) GetPlantSpeciesByID(ctx context.Context, id uuid.UUID) (GetPla
 	return i, err
 }
 
-const getPlantSpeciesByName = `-- name: GetPlantSpeciesByName :one
-select 
-	id, created_at, updated_at,
-	created_by, updated_by, species_name,
-	human_poison_toxic, pet_poison_toxic,
-	human_edible, pet_edible
-from plant_species
-	where species_name like $1
-  and deleted_at is null
-  limit 1
-`
-
-type GetPlantSpeciesByNameRow struct {
-	ID               uuid.UUID    `json:"id"`
-	CreatedAt        time.Time    `json:"createdAt"`
-	UpdatedAt        time.Time    `json:"updatedAt"`
-	CreatedBy        uuid.UUID    `json:"createdBy"`
-	UpdatedBy        uuid.UUID    `json:"updatedBy"`
-	SpeciesName      string       `json:"speciesName"`
-	HumanPoisonToxic sql.NullBool `json:"humanPoisonToxic"`
-	PetPoisonToxic   sql.NullBool `json:"petPoisonToxic"`
-	HumanEdible      sql.NullBool `json:"humanEdible"`
-	PetEdible        sql.NullBool `json:"petEdible"`
-}
-
-func (q *Queries) GetPlantSpeciesByName(ctx context.Context, speciesName string) (GetPlantSpeciesByNameRow, error) {
-	row := q.db.QueryRowContext(ctx, getPlantSpeciesByName, speciesName)
-	var i GetPlantSpeciesByNameRow
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.CreatedBy,
-		&i.UpdatedBy,
-		&i.SpeciesName,
-		&i.HumanPoisonToxic,
-		&i.PetPoisonToxic,
-		&i.HumanEdible,
-		&i.PetEdible,
-	)
-	return i, err
-}
-
 const markPlantSpeciesAsDeletedByID = `-- name: MarkPlantSpeciesAsDeletedByID :exec
 update plant_species
   set
@@ -102,9 +102,20 @@ select
   soil_organic_mix, soil_grit_mix, soil_drainage_mix
 from plant_types
   where deleted_at is null
-  order by created_at desc
+  and (
+    $1::timestamptz is null or
+    (created_at, id) < ($1::timestamptz, $2::uuid)
+  )
+  order by created_at desc, id desc
+  limit $3
 `
 
+type GetAllPlantTypesOrderedByCreatedParams struct {
+	CursorTime sql.NullTime  `json:"cursorTime"`
+	CursorID   uuid.NullUUID `json:"cursorID"`
+	RowLimit   int32         `json:"rowLimit"`
+}
+
 type GetAllPlantTypesOrderedByCreatedRow struct {
 	ID                    uuid.UUID      `json:"id"`
 	CreatedAt             time.Time      `json:"createdAt"`
@@ -122,8 +133,8 @@ type GetAllPlantTypesOrderedByCreatedRow struct {
 	SoilDrainageMix       sql.NullString `json:"soilDrainageMix"`
 }
 
-func (q *Queries) GetAllPlantTypesOrderedByCreated(ctx context.Context) ([]GetAllPlantTypesOrderedByCreatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPlantTypesOrderedByCreated)
+func (q *Queries) GetAllPlantTypesOrderedByCreated(ctx context.Context, arg GetAllPlantTypesOrderedByCreatedParams) ([]GetAllPlantTypesOrderedByCreatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPlantTypesOrderedByCreated, arg.CursorTime, arg.CursorID, arg.RowLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +171,86 @@ func (q *Queries) GetAllPlantTypesOrderedByCreated(ctx context.Context) ([]GetAl
 	return items, nil
 }
 
+const getAllPlantTypesOrderedByUpdated = `-- name: GetAllPlantTypesOrderedByUpdated :many
+select 
+	id,
+  created_at, updated_at,
+	created_by, updated_by,
+  name, description,
+  max_temperature_celsius, min_temperature_celsius,
+  max_humidity_percent, min_humidity_percent,
+  soil_organic_mix, soil_grit_mix, soil_drainage_mix
+from plant_types
+  where deleted_at is null
+  and (
+    $1::timestamptz is null or
+    (updated_at, id) < ($1::timestamptz, $2::uuid)
+  )
+  order by updated_at desc, id desc
+  limit $3
+`
+
+type GetAllPlantTypesOrderedByUpdatedParams struct {
+	CursorTime sql.NullTime  `json:"cursorTime"`
+	CursorID   uuid.NullUUID `json:"cursorID"`
+	RowLimit   int32         `json:"rowLimit"`
+}
+
+type GetAllPlantTypesOrderedByUpdatedRow struct {
+	ID                    uuid.UUID      `json:"id"`
+	CreatedAt             time.Time      `json:"createdAt"`
+	UpdatedAt             time.Time      `json:"updatedAt"`
+	CreatedBy             uuid.UUID      `json:"createdBy"`
+	UpdatedBy             uuid.UUID      `json:"updatedBy"`
+	Name                  string         `json:"name"`
+	Description           string         `json:"description"`
+	MaxTemperatureCelsius sql.NullInt32  `json:"maxTemperatureCelsius"`
+	MinTemperatureCelsius sql.NullInt32  `json:"minTemperatureCelsius"`
+	MaxHumidityPercent    sql.NullInt32  `json:"maxHumidityPercent"`
+	MinHumidityPercent    sql.NullInt32  `json:"minHumidityPercent"`
+	SoilOrganicMix        sql.NullString `json:"soilOrganicMix"`
+	SoilGritMix           sql.NullString `json:"soilGritMix"`
+	SoilDrainageMix       sql.NullString `json:"soilDrainageMix"`
+}
+
+func (q *Queries) GetAllPlantTypesOrderedByUpdated(ctx context.Context, arg GetAllPlantTypesOrderedByUpdatedParams) ([]GetAllPlantTypesOrderedByUpdatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPlantTypesOrderedByUpdated, arg.CursorTime, arg.CursorID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllPlantTypesOrderedByUpdatedRow
+	for rows.Next() {
+		var i GetAllPlantTypesOrderedByUpdatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.Name,
+			&i.Description,
+			&i.MaxTemperatureCelsius,
+			&i.MinTemperatureCelsius,
+			&i.MaxHumidityPercent,
+			&i.MinHumidityPercent,
+			&i.SoilOrganicMix,
+			&i.SoilGritMix,
+			&i.SoilDrainageMix,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markPlantTypeAsDeletedByID = `-- name: MarkPlantTypeAsDeletedByID :exec
 update plant_types
   set
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,68 +15,144 @@ import (
 
 const createRefreshToken = `-- name: CreateRefreshToken :one
 insert into refresh_tokens (
-  refresh_token,
+  token_hash,
   created_at, updated_at,
   created_by, updated_by,
-  expires_at, user_id
+  expires_at, user_id,
+  family_id,
+  user_agent, ip_address, last_used_at
 ) values (
   $1,
   now(), now(),
   $2, $2,
-  $3, $2
+  $3, $2,
+  $4,
+  $5, $6, now()
 ) returning
-  refresh_token,
+  token_hash,
   created_at, updated_at,
   created_by, updated_by,
-  expires_at, user_id
+  expires_at, user_id,
+  family_id
 `
 
 type CreateRefreshTokenParams struct {
-	RefreshToken string    `json:"refreshToken"`
-	CreatedBy    uuid.UUID `json:"createdBy"`
-	ExpiresAt    time.Time `json:"expiresAt"`
+	TokenHash string         `json:"tokenHash"`
+	CreatedBy uuid.UUID      `json:"createdBy"`
+	ExpiresAt time.Time      `json:"expiresAt"`
+	FamilyID  uuid.UUID      `json:"familyID"`
+	UserAgent sql.NullString `json:"userAgent"`
+	IpAddress sql.NullString `json:"ipAddress"`
 }
 
 type CreateRefreshTokenRow struct {
-	RefreshToken string    `json:"refreshToken"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
-	CreatedBy    uuid.UUID `json:"createdBy"`
-	UpdatedBy    uuid.UUID `json:"updatedBy"`
-	ExpiresAt    time.Time `json:"expiresAt"`
-	UserID       uuid.UUID `json:"userID"`
+	TokenHash string    `json:"tokenHash"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	UpdatedBy uuid.UUID `json:"updatedBy"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	UserID    uuid.UUID `json:"userID"`
+	FamilyID  uuid.UUID `json:"familyID"`
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (CreateRefreshTokenRow, error) {
-	row := q.db.QueryRowContext(ctx, createRefreshToken, arg.RefreshToken, arg.CreatedBy, arg.ExpiresAt)
+	row := q.db.QueryRowContext(ctx, createRefreshToken,
+		arg.TokenHash,
+		arg.CreatedBy,
+		arg.ExpiresAt,
+		arg.FamilyID,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
 	var i CreateRefreshTokenRow
 	err := row.Scan(
-		&i.RefreshToken,
+		&i.TokenHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
 		&i.ExpiresAt,
 		&i.UserID,
+		&i.FamilyID,
 	)
 	return i, err
 }
 
-const getUserFromRefreshToken = `-- name: GetUserFromRefreshToken :one
-select refresh_token, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, revoked_at, revoked_by, expires_at, user_id from refresh_tokens
+const getAllActiveSessionsByUserID = `-- name: GetAllActiveSessionsByUserID :many
+select
+  t.family_id,
+  f.started_at,
+  coalesce(t.last_used_at, t.created_at) as last_used_at,
+  t.user_agent,
+  t.ip_address,
+  t.expires_at
+from refresh_tokens t
+join (
+  select family_id, min(created_at) as started_at
+  from refresh_tokens
+  where user_id = $1
+  group by family_id
+) f on f.family_id = t.family_id
 where
-  refresh_token = $1 and
-  deleted_by is null and
-  revoked_at is null
-order by created_at desc
-limit 1
+  t.user_id = $1 and
+  t.deleted_by is null and
+  t.revoked_at is null and
+  t.expires_at > now()
+order by coalesce(t.last_used_at, t.created_at) desc
+`
+
+type GetAllActiveSessionsByUserIDRow struct {
+	FamilyID   uuid.UUID      `json:"familyID"`
+	StartedAt  time.Time      `json:"startedAt"`
+	LastUsedAt time.Time      `json:"lastUsedAt"`
+	UserAgent  sql.NullString `json:"userAgent"`
+	IpAddress  sql.NullString `json:"ipAddress"`
+	ExpiresAt  time.Time      `json:"expiresAt"`
+}
+
+func (q *Queries) GetAllActiveSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]GetAllActiveSessionsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllActiveSessionsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllActiveSessionsByUserIDRow
+	for rows.Next() {
+		var i GetAllActiveSessionsByUserIDRow
+		if err := rows.Scan(
+			&i.FamilyID,
+			&i.StartedAt,
+			&i.LastUsedAt,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRefreshTokenByHash = `-- name: GetRefreshTokenByHash :one
+select token_hash, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, revoked_at, revoked_by, expires_at, user_id, family_id, replaced_by, user_agent, ip_address, last_used_at from refresh_tokens
+where
+  token_hash = $1 and
+  deleted_by is null
 `
 
-func (q *Queries) GetUserFromRefreshToken(ctx context.Context, refreshToken string) (RefreshToken, error) {
-	row := q.db.QueryRowContext(ctx, getUserFromRefreshToken, refreshToken)
+func (q *Queries) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshTokenByHash, tokenHash)
 	var i RefreshToken
 	err := row.Scan(
-		&i.RefreshToken,
+		&i.TokenHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -86,12 +163,17 @@ func (q *Queries) GetUserFromRefreshToken(ctx context.Context, refreshToken stri
 		&i.RevokedBy,
 		&i.ExpiresAt,
 		&i.UserID,
+		&i.FamilyID,
+		&i.ReplacedBy,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
 const getValidRefreshTokenFromUserID = `-- name: GetValidRefreshTokenFromUserID :one
-select refresh_token, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, revoked_at, revoked_by, expires_at, user_id from refresh_tokens
+select token_hash, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, revoked_at, revoked_by, expires_at, user_id, family_id, replaced_by, user_agent, ip_address, last_used_at from refresh_tokens
 where
   user_id = $1 and
   deleted_by is null and
@@ -104,7 +186,7 @@ func (q *Queries) GetValidRefreshTokenFromUserID(ctx context.Context, userID uui
 	row := q.db.QueryRowContext(ctx, getValidRefreshTokenFromUserID, userID)
 	var i RefreshToken
 	err := row.Scan(
-		&i.RefreshToken,
+		&i.TokenHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -115,10 +197,157 @@ func (q *Queries) GetValidRefreshTokenFromUserID(ctx context.Context, userID uui
 		&i.RevokedBy,
 		&i.ExpiresAt,
 		&i.UserID,
+		&i.FamilyID,
+		&i.ReplacedBy,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
+const isRefreshTokenFamilyActive = `-- name: IsRefreshTokenFamilyActive :one
+select exists (
+  select 1 from refresh_tokens
+  where
+    family_id = $1 and
+    user_id = $2 and
+    deleted_by is null and
+    revoked_at is null and
+    expires_at > now()
+) as active
+`
+
+type IsRefreshTokenFamilyActiveParams struct {
+	FamilyID uuid.UUID `json:"familyID"`
+	UserID   uuid.UUID `json:"userID"`
+}
+
+func (q *Queries) IsRefreshTokenFamilyActive(ctx context.Context, arg IsRefreshTokenFamilyActiveParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isRefreshTokenFamilyActive, arg.FamilyID, arg.UserID)
+	var active bool
+	err := row.Scan(&active)
+	return active, err
+}
+
+const purgeExpiredRefreshTokens = `-- name: PurgeExpiredRefreshTokens :execrows
+delete from refresh_tokens
+where
+  expires_at < now() or
+  -- revoked without a replacement, such as on logout, so reuse detection never needs it
+  (revoked_at is not null and replaced_by is null)
+`
+
+func (q *Queries) PurgeExpiredRefreshTokens(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeExpiredRefreshTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeAllRefreshTokensByUserID = `-- name: RevokeAllRefreshTokensByUserID :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $2,
+  revoked_at = now(),
+  revoked_by = $2
+where
+  user_id = $1 and
+  revoked_at is null
+`
+
+type RevokeAllRefreshTokensByUserIDParams struct {
+	UserID    uuid.UUID `json:"userID"`
+	UpdatedBy uuid.UUID `json:"updatedBy"`
+}
+
+func (q *Queries) RevokeAllRefreshTokensByUserID(ctx context.Context, arg RevokeAllRefreshTokensByUserIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeAllRefreshTokensByUserID, arg.UserID, arg.UpdatedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeOtherRefreshTokenFamilies = `-- name: RevokeOtherRefreshTokenFamilies :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $1,
+  revoked_at = now(),
+  revoked_by = $1
+where
+  user_id = $1 and
+  family_id <> $2 and
+  revoked_at is null
+`
+
+type RevokeOtherRefreshTokenFamiliesParams struct {
+	UpdatedBy uuid.UUID `json:"updatedBy"`
+	FamilyID  uuid.UUID `json:"familyID"`
+}
+
+func (q *Queries) RevokeOtherRefreshTokenFamilies(ctx context.Context, arg RevokeOtherRefreshTokenFamiliesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeOtherRefreshTokenFamilies, arg.UpdatedBy, arg.FamilyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $2,
+  revoked_at = now(),
+  revoked_by = $2
+where
+  family_id = $1 and
+  revoked_at is null
+`
+
+type RevokeRefreshTokenFamilyParams struct {
+	FamilyID  uuid.UUID `json:"familyID"`
+	UpdatedBy uuid.UUID `json:"updatedBy"`
+}
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, arg RevokeRefreshTokenFamilyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeRefreshTokenFamily, arg.FamilyID, arg.UpdatedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeRefreshTokenFamilyByUserID = `-- name: RevokeRefreshTokenFamilyByUserID :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $2,
+  revoked_at = now(),
+  revoked_by = $2
+where
+  family_id = $1 and
+  user_id = $2 and
+  revoked_at is null
+`
+
+type RevokeRefreshTokenFamilyByUserIDParams struct {
+	FamilyID  uuid.UUID `json:"familyID"`
+	UpdatedBy uuid.UUID `json:"updatedBy"`
+}
+
+func (q *Queries) RevokeRefreshTokenFamilyByUserID(ctx context.Context, arg RevokeRefreshTokenFamilyByUserIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeRefreshTokenFamilyByUserID, arg.FamilyID, arg.UpdatedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const revokeRefreshTokenWithToken = `-- name: RevokeRefreshTokenWithToken :one
 update refresh_tokens
 set
@@ -126,18 +355,47 @@ set
   updated_by = $2,
   revoked_at = now(),
   revoked_by = $2
-where refresh_token = $1
+where
+  token_hash = $1 and
+  revoked_at is null
 returning user_id
 `
 
 type RevokeRefreshTokenWithTokenParams struct {
-	RefreshToken string    `json:"refreshToken"`
-	UpdatedBy    uuid.UUID `json:"updatedBy"`
+	TokenHash string    `json:"tokenHash"`
+	UpdatedBy uuid.UUID `json:"updatedBy"`
 }
 
 func (q *Queries) RevokeRefreshTokenWithToken(ctx context.Context, arg RevokeRefreshTokenWithTokenParams) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, revokeRefreshTokenWithToken, arg.RefreshToken, arg.UpdatedBy)
+	row := q.db.QueryRowContext(ctx, revokeRefreshTokenWithToken, arg.TokenHash, arg.UpdatedBy)
 	var user_id uuid.UUID
 	err := row.Scan(&user_id)
 	return user_id, err
 }
+
+const rotateRefreshToken = `-- name: RotateRefreshToken :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $2,
+  revoked_at = now(),
+  revoked_by = $2,
+  replaced_by = $3
+where
+  token_hash = $1 and
+  revoked_at is null
+`
+
+type RotateRefreshTokenParams struct {
+	TokenHash  string         `json:"tokenHash"`
+	UpdatedBy  uuid.UUID      `json:"updatedBy"`
+	ReplacedBy sql.NullString `json:"replacedBy"`
+}
+
+func (q *Queries) RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rotateRefreshToken, arg.TokenHash, arg.UpdatedBy, arg.ReplacedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
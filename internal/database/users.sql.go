@@ -188,8 +188,8 @@ func (q *Queries) GetAllUsersWithoutPasswordByUpdated(ctx context.Context) ([]Ge
 }
 
 const getUserByEmailWithPassword = `-- name: GetUserByEmailWithPassword :one
-select id, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, lang_code_pref, join_date, is_admin, email, hashed_password from users
-  where email like $1
+select id, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, lang_code_pref, join_date, is_admin, email, hashed_password, email_verified_at from users
+  where lower(email) = lower($1)
   and deleted_at is null
   limit 1
 `
@@ -210,6 +210,7 @@ func (q *Queries) GetUserByEmailWithPassword(ctx context.Context, email string)
 		&i.IsAdmin,
 		&i.Email,
 		&i.HashedPassword,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
@@ -220,7 +221,7 @@ select
   created_by, updated_by,
   lang_code_pref, join_date, is_admin, email
 from users
-  where email like $1
+  where lower(email) = lower($1)
   and deleted_at is null
   limit 1
 `
@@ -255,7 +256,7 @@ func (q *Queries) GetUserByEmailWithoutPassword(ctx context.Context, email strin
 }
 
 const getUserByIDWithPassword = `-- name: GetUserByIDWithPassword :one
-select id, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, lang_code_pref, join_date, is_admin, email, hashed_password from users
+select id, created_at, updated_at, deleted_at, created_by, updated_by, deleted_by, lang_code_pref, join_date, is_admin, email, hashed_password, email_verified_at from users
   where id = $1
   and deleted_at is null
   limit 1
@@ -277,6 +278,7 @@ func (q *Queries) GetUserByIDWithPassword(ctx context.Context, id uuid.UUID) (Us
 		&i.IsAdmin,
 		&i.Email,
 		&i.HashedPassword,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
@@ -321,6 +323,20 @@ func (q *Queries) GetUserByIDWithoutPassword(ctx context.Context, id uuid.UUID)
 	return i, err
 }
 
+const isUserEmailVerifiedByID = `-- name: IsUserEmailVerifiedByID :one
+select (email_verified_at is not null) as email_verified
+from users
+  where id = $1
+  and deleted_at is null
+`
+
+func (q *Queries) IsUserEmailVerifiedByID(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isUserEmailVerifiedByID, id)
+	var email_verified bool
+	err := row.Scan(&email_verified)
+	return email_verified, err
+}
+
 const promoteUserToAdminByID = `-- name: PromoteUserToAdminByID :exec
 update users
 set
@@ -350,6 +366,21 @@ func (q *Queries) ResetUsersTable(ctx context.Context) error {
 	return err
 }
 
+const setUserEmailVerifiedByID = `-- name: SetUserEmailVerifiedByID :exec
+update users
+set
+  email_verified_at = coalesce(email_verified_at, now()),
+  updated_at = now(),
+  updated_by = $1
+where
+  id = $1
+`
+
+func (q *Queries) SetUserEmailVerifiedByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, setUserEmailVerifiedByID, id)
+	return err
+}
+
 const updateUserPasswordByID = `-- name: UpdateUserPasswordByID :exec
 update users
 set
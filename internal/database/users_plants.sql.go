@@ -78,7 +78,7 @@ func (q *Queries) CreateUsersPlants(ctx context.Context, arg CreateUsersPlantsPa
 	return i, err
 }
 
-const deleteUsersPlantByID = `-- name: DeleteUsersPlantByID :exec
+const deleteUsersPlantByID = `-- name: DeleteUsersPlantByID :execrows
 update users_plants
 set
   deleted_at = now(),
@@ -86,6 +86,7 @@ set
   updated_at = now(),
   updated_by = $2
 where id = $1
+  and user_id = $2
   and deleted_at is null
 `
 
@@ -94,45 +95,113 @@ type DeleteUsersPlantByIDParams struct {
 	DeletedBy uuid.NullUUID `json:"deletedBy"`
 }
 
-func (q *Queries) DeleteUsersPlantByID(ctx context.Context, arg DeleteUsersPlantByIDParams) error {
-	_, err := q.db.ExecContext(ctx, deleteUsersPlantByID, arg.ID, arg.DeletedBy)
-	return err
+func (q *Queries) DeleteUsersPlantByID(ctx context.Context, arg DeleteUsersPlantByIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUsersPlantByID, arg.ID, arg.DeletedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getAllUsersPlantsOrderedByCreated = `-- name: GetAllUsersPlantsOrderedByCreated :many
 with users_plant as (
-  select 
-    id, plant_id, adoption_date, name, created_at
+  select
+    id, plant_id, adoption_date, name, created_at, updated_at,
+    water_interval_days, light_needs_id, fertilizer_interval_days,
+    location_id
   from users_plants
   where
     deleted_at is null and
-    user_id = $1
+    user_id = $1 and
+    ($2::uuid is null or location_id = $2::uuid)
 )
 select
   up.id as users_plant_id,
   up.adoption_date,
   up.name as plant_name,
   up.created_at,
+  up.updated_at,
   ps.id as plant_species_id,
-  ps.species_name
+  ps.species_name,
+  up.water_interval_days,
+  up.light_needs_id,
+  up.fertilizer_interval_days,
+  wn.dry_soil_days as species_dry_soil_days,
+  wn.dry_soil_mm as species_dry_soil_mm,
+  ps.light_needs_id as species_light_needs_id,
+  ln.name as light_need_name,
+  up.location_id,
+  loc.name as location_name,
+  loc.min_temperature_celsius as location_min_temperature_celsius,
+  loc.max_temperature_celsius as location_max_temperature_celsius,
+  loc.min_humidity_percent as location_min_humidity_percent,
+  loc.max_humidity_percent as location_max_humidity_percent,
+  pt.min_temperature_celsius as plant_type_min_temperature_celsius,
+  pt.max_temperature_celsius as plant_type_max_temperature_celsius,
+  pt.min_humidity_percent as plant_type_min_humidity_percent,
+  pt.max_humidity_percent as plant_type_max_humidity_percent
 from
   users_plant as up
 join
   plant_species as ps on up.plant_id = ps.id
-order by up.created_at desc
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+left join
+  light_needs as ln on coalesce(up.light_needs_id, ps.light_needs_id) = ln.id and ln.deleted_at is null
+left join
+  locations as loc on up.location_id = loc.id and loc.deleted_at is null
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
+where
+  $3::timestamptz is null or
+  (up.created_at, up.id) < ($3::timestamptz, $4::uuid)
+order by up.created_at desc, up.id desc
+limit $5
 `
 
+type GetAllUsersPlantsOrderedByCreatedParams struct {
+	UserID     uuid.UUID     `json:"userID"`
+	LocationID uuid.NullUUID `json:"locationID"`
+	CursorTime sql.NullTime  `json:"cursorTime"`
+	CursorID   uuid.NullUUID `json:"cursorID"`
+	RowLimit   int32         `json:"rowLimit"`
+}
+
 type GetAllUsersPlantsOrderedByCreatedRow struct {
-	UsersPlantID   uuid.UUID      `json:"usersPlantID"`
-	AdoptionDate   sql.NullTime   `json:"adoptionDate"`
-	PlantName      sql.NullString `json:"plantName"`
-	CreatedAt      time.Time      `json:"createdAt"`
-	PlantSpeciesID uuid.UUID      `json:"plantSpeciesID"`
-	SpeciesName    string         `json:"speciesName"`
+	UsersPlantID                   uuid.UUID      `json:"usersPlantID"`
+	AdoptionDate                   sql.NullTime   `json:"adoptionDate"`
+	PlantName                      sql.NullString `json:"plantName"`
+	CreatedAt                      time.Time      `json:"createdAt"`
+	UpdatedAt                      time.Time      `json:"updatedAt"`
+	PlantSpeciesID                 uuid.UUID      `json:"plantSpeciesID"`
+	SpeciesName                    string         `json:"speciesName"`
+	WaterIntervalDays              sql.NullInt32  `json:"waterIntervalDays"`
+	LightNeedsID                   uuid.NullUUID  `json:"lightNeedsID"`
+	FertilizerIntervalDays         sql.NullInt32  `json:"fertilizerIntervalDays"`
+	SpeciesDrySoilDays             sql.NullInt32  `json:"speciesDrySoilDays"`
+	SpeciesDrySoilMm               sql.NullInt32  `json:"speciesDrySoilMm"`
+	SpeciesLightNeedsID            uuid.NullUUID  `json:"speciesLightNeedsID"`
+	LightNeedName                  sql.NullString `json:"lightNeedName"`
+	LocationID                     uuid.NullUUID  `json:"locationID"`
+	LocationName                   sql.NullString `json:"locationName"`
+	LocationMinTemperatureCelsius  sql.NullInt32  `json:"locationMinTemperatureCelsius"`
+	LocationMaxTemperatureCelsius  sql.NullInt32  `json:"locationMaxTemperatureCelsius"`
+	LocationMinHumidityPercent     sql.NullInt32  `json:"locationMinHumidityPercent"`
+	LocationMaxHumidityPercent     sql.NullInt32  `json:"locationMaxHumidityPercent"`
+	PlantTypeMinTemperatureCelsius sql.NullInt32  `json:"plantTypeMinTemperatureCelsius"`
+	PlantTypeMaxTemperatureCelsius sql.NullInt32  `json:"plantTypeMaxTemperatureCelsius"`
+	PlantTypeMinHumidityPercent    sql.NullInt32  `json:"plantTypeMinHumidityPercent"`
+	PlantTypeMaxHumidityPercent    sql.NullInt32  `json:"plantTypeMaxHumidityPercent"`
 }
 
-func (q *Queries) GetAllUsersPlantsOrderedByCreated(ctx context.Context, userID uuid.UUID) ([]GetAllUsersPlantsOrderedByCreatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllUsersPlantsOrderedByCreated, userID)
+func (q *Queries) GetAllUsersPlantsOrderedByCreated(ctx context.Context, arg GetAllUsersPlantsOrderedByCreatedParams) ([]GetAllUsersPlantsOrderedByCreatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllUsersPlantsOrderedByCreated,
+		arg.UserID,
+		arg.LocationID,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -145,8 +214,26 @@ func (q *Queries) GetAllUsersPlantsOrderedByCreated(ctx context.Context, userID
 			&i.AdoptionDate,
 			&i.PlantName,
 			&i.CreatedAt,
+			&i.UpdatedAt,
 			&i.PlantSpeciesID,
 			&i.SpeciesName,
+			&i.WaterIntervalDays,
+			&i.LightNeedsID,
+			&i.FertilizerIntervalDays,
+			&i.SpeciesDrySoilDays,
+			&i.SpeciesDrySoilMm,
+			&i.SpeciesLightNeedsID,
+			&i.LightNeedName,
+			&i.LocationID,
+			&i.LocationName,
+			&i.LocationMinTemperatureCelsius,
+			&i.LocationMaxTemperatureCelsius,
+			&i.LocationMinHumidityPercent,
+			&i.LocationMaxHumidityPercent,
+			&i.PlantTypeMinTemperatureCelsius,
+			&i.PlantTypeMaxTemperatureCelsius,
+			&i.PlantTypeMinHumidityPercent,
+			&i.PlantTypeMaxHumidityPercent,
 		); err != nil {
 			return nil, err
 		}
@@ -164,37 +251,102 @@ func (q *Queries) GetAllUsersPlantsOrderedByCreated(ctx context.Context, userID
 const getAllUsersPlantsOrderedByUpdated = `-- name: GetAllUsersPlantsOrderedByUpdated :many
 with users_plant as (
   select
-    id, plant_id, adoption_date, name, updated_at
+    id, plant_id, adoption_date, name, created_at, updated_at,
+    water_interval_days, light_needs_id, fertilizer_interval_days,
+    location_id
   from users_plants
   where
     deleted_at is null and
-    user_id = $1
+    user_id = $1 and
+    ($2::uuid is null or location_id = $2::uuid)
 )
 select
   up.id as users_plant_id,
   up.adoption_date,
   up.name as plant_name,
+  up.created_at,
   up.updated_at,
   ps.id as plant_species_id,
-  ps.species_name
+  ps.species_name,
+  up.water_interval_days,
+  up.light_needs_id,
+  up.fertilizer_interval_days,
+  wn.dry_soil_days as species_dry_soil_days,
+  wn.dry_soil_mm as species_dry_soil_mm,
+  ps.light_needs_id as species_light_needs_id,
+  ln.name as light_need_name,
+  up.location_id,
+  loc.name as location_name,
+  loc.min_temperature_celsius as location_min_temperature_celsius,
+  loc.max_temperature_celsius as location_max_temperature_celsius,
+  loc.min_humidity_percent as location_min_humidity_percent,
+  loc.max_humidity_percent as location_max_humidity_percent,
+  pt.min_temperature_celsius as plant_type_min_temperature_celsius,
+  pt.max_temperature_celsius as plant_type_max_temperature_celsius,
+  pt.min_humidity_percent as plant_type_min_humidity_percent,
+  pt.max_humidity_percent as plant_type_max_humidity_percent
 from
   users_plant as up
 join
   plant_species as ps on up.plant_id = ps.id
-order by up.updated_at desc
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+left join
+  light_needs as ln on coalesce(up.light_needs_id, ps.light_needs_id) = ln.id and ln.deleted_at is null
+left join
+  locations as loc on up.location_id = loc.id and loc.deleted_at is null
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
+where
+  $3::timestamptz is null or
+  (up.updated_at, up.id) < ($3::timestamptz, $4::uuid)
+order by up.updated_at desc, up.id desc
+limit $5
 `
 
+type GetAllUsersPlantsOrderedByUpdatedParams struct {
+	UserID     uuid.UUID     `json:"userID"`
+	LocationID uuid.NullUUID `json:"locationID"`
+	CursorTime sql.NullTime  `json:"cursorTime"`
+	CursorID   uuid.NullUUID `json:"cursorID"`
+	RowLimit   int32         `json:"rowLimit"`
+}
+
 type GetAllUsersPlantsOrderedByUpdatedRow struct {
-	UsersPlantID   uuid.UUID      `json:"usersPlantID"`
-	AdoptionDate   sql.NullTime   `json:"adoptionDate"`
-	PlantName      sql.NullString `json:"plantName"`
-	UpdatedAt      time.Time      `json:"updatedAt"`
-	PlantSpeciesID uuid.UUID      `json:"plantSpeciesID"`
-	SpeciesName    string         `json:"speciesName"`
+	UsersPlantID                   uuid.UUID      `json:"usersPlantID"`
+	AdoptionDate                   sql.NullTime   `json:"adoptionDate"`
+	PlantName                      sql.NullString `json:"plantName"`
+	CreatedAt                      time.Time      `json:"createdAt"`
+	UpdatedAt                      time.Time      `json:"updatedAt"`
+	PlantSpeciesID                 uuid.UUID      `json:"plantSpeciesID"`
+	SpeciesName                    string         `json:"speciesName"`
+	WaterIntervalDays              sql.NullInt32  `json:"waterIntervalDays"`
+	LightNeedsID                   uuid.NullUUID  `json:"lightNeedsID"`
+	FertilizerIntervalDays         sql.NullInt32  `json:"fertilizerIntervalDays"`
+	SpeciesDrySoilDays             sql.NullInt32  `json:"speciesDrySoilDays"`
+	SpeciesDrySoilMm               sql.NullInt32  `json:"speciesDrySoilMm"`
+	SpeciesLightNeedsID            uuid.NullUUID  `json:"speciesLightNeedsID"`
+	LightNeedName                  sql.NullString `json:"lightNeedName"`
+	LocationID                     uuid.NullUUID  `json:"locationID"`
+	LocationName                   sql.NullString `json:"locationName"`
+	LocationMinTemperatureCelsius  sql.NullInt32  `json:"locationMinTemperatureCelsius"`
+	LocationMaxTemperatureCelsius  sql.NullInt32  `json:"locationMaxTemperatureCelsius"`
+	LocationMinHumidityPercent     sql.NullInt32  `json:"locationMinHumidityPercent"`
+	LocationMaxHumidityPercent     sql.NullInt32  `json:"locationMaxHumidityPercent"`
+	PlantTypeMinTemperatureCelsius sql.NullInt32  `json:"plantTypeMinTemperatureCelsius"`
+	PlantTypeMaxTemperatureCelsius sql.NullInt32  `json:"plantTypeMaxTemperatureCelsius"`
+	PlantTypeMinHumidityPercent    sql.NullInt32  `json:"plantTypeMinHumidityPercent"`
+	PlantTypeMaxHumidityPercent    sql.NullInt32  `json:"plantTypeMaxHumidityPercent"`
 }
 
-func (q *Queries) GetAllUsersPlantsOrderedByUpdated(ctx context.Context, userID uuid.UUID) ([]GetAllUsersPlantsOrderedByUpdatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllUsersPlantsOrderedByUpdated, userID)
+func (q *Queries) GetAllUsersPlantsOrderedByUpdated(ctx context.Context, arg GetAllUsersPlantsOrderedByUpdatedParams) ([]GetAllUsersPlantsOrderedByUpdatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllUsersPlantsOrderedByUpdated,
+		arg.UserID,
+		arg.LocationID,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -206,9 +358,27 @@ func (q *Queries) GetAllUsersPlantsOrderedByUpdated(ctx context.Context, userID
 			&i.UsersPlantID,
 			&i.AdoptionDate,
 			&i.PlantName,
+			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PlantSpeciesID,
 			&i.SpeciesName,
+			&i.WaterIntervalDays,
+			&i.LightNeedsID,
+			&i.FertilizerIntervalDays,
+			&i.SpeciesDrySoilDays,
+			&i.SpeciesDrySoilMm,
+			&i.SpeciesLightNeedsID,
+			&i.LightNeedName,
+			&i.LocationID,
+			&i.LocationName,
+			&i.LocationMinTemperatureCelsius,
+			&i.LocationMaxTemperatureCelsius,
+			&i.LocationMinHumidityPercent,
+			&i.LocationMaxHumidityPercent,
+			&i.PlantTypeMinTemperatureCelsius,
+			&i.PlantTypeMaxTemperatureCelsius,
+			&i.PlantTypeMinHumidityPercent,
+			&i.PlantTypeMaxHumidityPercent,
 		); err != nil {
 			return nil, err
 		}
@@ -226,7 +396,9 @@ func (q *Queries) GetAllUsersPlantsOrderedByUpdated(ctx context.Context, userID
 const getUsersPlantByID = `-- name: GetUsersPlantByID :one
 with users_plant as (
   select 
-    id, plant_id, adoption_date, name, created_at
+    id, plant_id, adoption_date, name, created_at,
+    water_interval_days, light_needs_id, fertilizer_interval_days,
+    location_id
   from users_plants
   where
     deleted_at is null and
@@ -239,11 +411,36 @@ select
   up.name as plant_name,
   up.created_at,
   ps.id as plant_species_id,
-  ps.species_name
+  ps.species_name,
+  up.water_interval_days,
+  up.light_needs_id,
+  up.fertilizer_interval_days,
+  wn.dry_soil_days as species_dry_soil_days,
+  wn.dry_soil_mm as species_dry_soil_mm,
+  ps.light_needs_id as species_light_needs_id,
+  ln.name as light_need_name,
+  up.location_id,
+  loc.name as location_name,
+  loc.min_temperature_celsius as location_min_temperature_celsius,
+  loc.max_temperature_celsius as location_max_temperature_celsius,
+  loc.min_humidity_percent as location_min_humidity_percent,
+  loc.max_humidity_percent as location_max_humidity_percent,
+  pt.min_temperature_celsius as plant_type_min_temperature_celsius,
+  pt.max_temperature_celsius as plant_type_max_temperature_celsius,
+  pt.min_humidity_percent as plant_type_min_humidity_percent,
+  pt.max_humidity_percent as plant_type_max_humidity_percent
 from
   users_plant as up
 join
   plant_species as ps on up.plant_id = ps.id
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+left join
+  light_needs as ln on coalesce(up.light_needs_id, ps.light_needs_id) = ln.id and ln.deleted_at is null
+left join
+  locations as loc on up.location_id = loc.id and loc.deleted_at is null
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
 order by up.created_at desc
 limit 1
 `
@@ -254,12 +451,29 @@ type GetUsersPlantByIDParams struct {
 }
 
 type GetUsersPlantByIDRow struct {
-	UsersPlantID   uuid.UUID      `json:"usersPlantID"`
-	AdoptionDate   sql.NullTime   `json:"adoptionDate"`
-	PlantName      sql.NullString `json:"plantName"`
-	CreatedAt      time.Time      `json:"createdAt"`
-	PlantSpeciesID uuid.UUID      `json:"plantSpeciesID"`
-	SpeciesName    string         `json:"speciesName"`
+	UsersPlantID                   uuid.UUID      `json:"usersPlantID"`
+	AdoptionDate                   sql.NullTime   `json:"adoptionDate"`
+	PlantName                      sql.NullString `json:"plantName"`
+	CreatedAt                      time.Time      `json:"createdAt"`
+	PlantSpeciesID                 uuid.UUID      `json:"plantSpeciesID"`
+	SpeciesName                    string         `json:"speciesName"`
+	WaterIntervalDays              sql.NullInt32  `json:"waterIntervalDays"`
+	LightNeedsID                   uuid.NullUUID  `json:"lightNeedsID"`
+	FertilizerIntervalDays         sql.NullInt32  `json:"fertilizerIntervalDays"`
+	SpeciesDrySoilDays             sql.NullInt32  `json:"speciesDrySoilDays"`
+	SpeciesDrySoilMm               sql.NullInt32  `json:"speciesDrySoilMm"`
+	SpeciesLightNeedsID            uuid.NullUUID  `json:"speciesLightNeedsID"`
+	LightNeedName                  sql.NullString `json:"lightNeedName"`
+	LocationID                     uuid.NullUUID  `json:"locationID"`
+	LocationName                   sql.NullString `json:"locationName"`
+	LocationMinTemperatureCelsius  sql.NullInt32  `json:"locationMinTemperatureCelsius"`
+	LocationMaxTemperatureCelsius  sql.NullInt32  `json:"locationMaxTemperatureCelsius"`
+	LocationMinHumidityPercent     sql.NullInt32  `json:"locationMinHumidityPercent"`
+	LocationMaxHumidityPercent     sql.NullInt32  `json:"locationMaxHumidityPercent"`
+	PlantTypeMinTemperatureCelsius sql.NullInt32  `json:"plantTypeMinTemperatureCelsius"`
+	PlantTypeMaxTemperatureCelsius sql.NullInt32  `json:"plantTypeMaxTemperatureCelsius"`
+	PlantTypeMinHumidityPercent    sql.NullInt32  `json:"plantTypeMinHumidityPercent"`
+	PlantTypeMaxHumidityPercent    sql.NullInt32  `json:"plantTypeMaxHumidityPercent"`
 }
 
 func (q *Queries) GetUsersPlantByID(ctx context.Context, arg GetUsersPlantByIDParams) (GetUsersPlantByIDRow, error) {
@@ -272,17 +486,54 @@ func (q *Queries) GetUsersPlantByID(ctx context.Context, arg GetUsersPlantByIDPa
 		&i.CreatedAt,
 		&i.PlantSpeciesID,
 		&i.SpeciesName,
+		&i.WaterIntervalDays,
+		&i.LightNeedsID,
+		&i.FertilizerIntervalDays,
+		&i.SpeciesDrySoilDays,
+		&i.SpeciesDrySoilMm,
+		&i.SpeciesLightNeedsID,
+		&i.LightNeedName,
+		&i.LocationID,
+		&i.LocationName,
+		&i.LocationMinTemperatureCelsius,
+		&i.LocationMaxTemperatureCelsius,
+		&i.LocationMinHumidityPercent,
+		&i.LocationMaxHumidityPercent,
+		&i.PlantTypeMinTemperatureCelsius,
+		&i.PlantTypeMaxTemperatureCelsius,
+		&i.PlantTypeMinHumidityPercent,
+		&i.PlantTypeMaxHumidityPercent,
 	)
 	return i, err
 }
 
-const updateUsersPlantByID = `-- name: UpdateUsersPlantByID :exec
+const unassignUsersPlantsFromLocation = `-- name: UnassignUsersPlantsFromLocation :exec
+update users_plants
+set updated_at = now(),
+  updated_by = $2,
+  location_id = null
+where location_id = $1
+  and deleted_at is null
+`
+
+type UnassignUsersPlantsFromLocationParams struct {
+	LocationID uuid.NullUUID `json:"locationID"`
+	UpdatedBy  uuid.UUID     `json:"updatedBy"`
+}
+
+func (q *Queries) UnassignUsersPlantsFromLocation(ctx context.Context, arg UnassignUsersPlantsFromLocationParams) error {
+	_, err := q.db.ExecContext(ctx, unassignUsersPlantsFromLocation, arg.LocationID, arg.UpdatedBy)
+	return err
+}
+
+const updateUsersPlantByID = `-- name: UpdateUsersPlantByID :execrows
 update users_plants
 set updated_at = now(),
   updated_by = $2,
   adoption_date = $3,
   name = $4
 where id = $1
+  and user_id = $2
   and deleted_at is null
 `
 
@@ -293,12 +544,73 @@ type UpdateUsersPlantByIDParams struct {
 	Name         sql.NullString `json:"name"`
 }
 
-func (q *Queries) UpdateUsersPlantByID(ctx context.Context, arg UpdateUsersPlantByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateUsersPlantByID,
+func (q *Queries) UpdateUsersPlantByID(ctx context.Context, arg UpdateUsersPlantByIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUsersPlantByID,
 		arg.ID,
 		arg.UpdatedBy,
 		arg.AdoptionDate,
 		arg.Name,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUsersPlantCareByID = `-- name: UpdateUsersPlantCareByID :execrows
+update users_plants
+set updated_at = now(),
+  updated_by = $2,
+  water_interval_days = $3,
+  light_needs_id = $4,
+  fertilizer_interval_days = $5
+where id = $1
+  and user_id = $2
+  and deleted_at is null
+`
+
+type UpdateUsersPlantCareByIDParams struct {
+	ID                     uuid.UUID     `json:"id"`
+	UpdatedBy              uuid.UUID     `json:"updatedBy"`
+	WaterIntervalDays      sql.NullInt32 `json:"waterIntervalDays"`
+	LightNeedsID           uuid.NullUUID `json:"lightNeedsID"`
+	FertilizerIntervalDays sql.NullInt32 `json:"fertilizerIntervalDays"`
+}
+
+func (q *Queries) UpdateUsersPlantCareByID(ctx context.Context, arg UpdateUsersPlantCareByIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUsersPlantCareByID,
+		arg.ID,
+		arg.UpdatedBy,
+		arg.WaterIntervalDays,
+		arg.LightNeedsID,
+		arg.FertilizerIntervalDays,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUsersPlantLocationByID = `-- name: UpdateUsersPlantLocationByID :execrows
+update users_plants
+set updated_at = now(),
+  updated_by = $2,
+  location_id = $3
+where id = $1
+  and user_id = $2
+  and deleted_at is null
+`
+
+type UpdateUsersPlantLocationByIDParams struct {
+	ID         uuid.UUID     `json:"id"`
+	UpdatedBy  uuid.UUID     `json:"updatedBy"`
+	LocationID uuid.NullUUID `json:"locationID"`
+}
+
+func (q *Queries) UpdateUsersPlantLocationByID(ctx context.Context, arg UpdateUsersPlantLocationByIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUsersPlantLocationByID, arg.ID, arg.UpdatedBy, arg.LocationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
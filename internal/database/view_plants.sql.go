@@ -8,10 +8,188 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const getAllViewPlantsOrderedByCreated = `-- name: GetAllViewPlantsOrderedByCreated :many
+select
+  ps.id as plant_species_id,
+  ps.species_name as plant_species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  nullif(max(pn.lang_code), '') as lang_code,
+  nullif(string_agg(pn.common_name, ', '), '') as common_names,
+  pt.name as plant_type_name,
+  pt.description as plant_type_description,
+  ln.name as light_need_name,
+  ln.description as light_need_description,
+  wn.plant_type as water_need_type,
+  wn.description as water_need_description,
+  wn.dry_soil_mm as water_need_dry_soil_mm,
+  wn.dry_soil_days as water_need_dry_soil_days,
+  ps.created_at,
+  ps.updated_at
+from
+  plant_species as ps
+left join
+  plant_names as pn on ps.id = pn.plant_id
+left join
+  plant_types as pt on ps.plant_type_id = pt.id
+left join
+  light_needs as ln on ps.light_needs_id = ln.id
+left join
+  water_needs as wn on ps.water_needs_id = wn.id
+where
+  (pn.lang_code = $1 or pn.lang_code is null) and
+  ps.deleted_at is null and
+  pn.deleted_at is null and
+  ln.deleted_at is null and
+  wn.deleted_at is null and
+  -- optional catalog filters, a null filter matches every plant
+  ($2::boolean is null or ps.pet_poison_toxic = not $2::boolean) and
+  ($3::boolean is null or ps.human_poison_toxic = not $3::boolean) and
+  ($4::boolean is null or ps.pet_edible = $4::boolean) and
+  ($5::boolean is null or ps.human_edible = $5::boolean) and
+  ($6::text is null or lower(pt.name) = lower($6::text)) and
+  ($7::text is null or lower(ln.name) = lower($7::text)) and
+  (
+    $8::text is null or
+    ($8::text = 'days' and wn.dry_soil_days is not null) or
+    ($8::text = 'mm' and wn.dry_soil_mm is not null)
+  ) and
+  -- the plant type's range overlaps the requested range when each starts before the other ends
+  ($9::integer is null or pt.max_temperature_celsius >= $9::integer) and
+  ($10::integer is null or pt.min_temperature_celsius <= $10::integer) and
+  ($11::integer is null or pt.max_humidity_percent >= $11::integer) and
+  ($12::integer is null or pt.min_humidity_percent <= $12::integer) and
+  (
+    $13::timestamptz is null or
+    (ps.created_at, ps.id) < ($13::timestamptz, $14::uuid)
+  )
+group by
+  ps.id,
+  ps.species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  pt.name,
+  pt.description,
+  ln.name,
+  ln.description,
+  wn.plant_type,
+  wn.description,
+  wn.dry_soil_mm,
+  wn.dry_soil_days,
+  ps.created_at,
+  ps.updated_at
+order by
+  ps.created_at desc,
+  ps.id desc
+limit $15
+`
+
+type GetAllViewPlantsOrderedByCreatedParams struct {
+	LangCode              sql.NullString `json:"langCode"`
+	PetSafe               sql.NullBool   `json:"petSafe"`
+	HumanSafe             sql.NullBool   `json:"humanSafe"`
+	PetEdible             sql.NullBool   `json:"petEdible"`
+	HumanEdible           sql.NullBool   `json:"humanEdible"`
+	PlantType             sql.NullString `json:"plantType"`
+	LightNeed             sql.NullString `json:"lightNeed"`
+	WaterModel            sql.NullString `json:"waterModel"`
+	MinTemperatureCelsius sql.NullInt32  `json:"minTemperatureCelsius"`
+	MaxTemperatureCelsius sql.NullInt32  `json:"maxTemperatureCelsius"`
+	MinHumidityPercent    sql.NullInt32  `json:"minHumidityPercent"`
+	MaxHumidityPercent    sql.NullInt32  `json:"maxHumidityPercent"`
+	CursorTime            sql.NullTime   `json:"cursorTime"`
+	CursorID              uuid.NullUUID  `json:"cursorID"`
+	RowLimit              int32          `json:"rowLimit"`
+}
+
+type GetAllViewPlantsOrderedByCreatedRow struct {
+	PlantSpeciesID       uuid.UUID      `json:"plantSpeciesID"`
+	PlantSpeciesName     string         `json:"plantSpeciesName"`
+	HumanPoisonToxic     sql.NullBool   `json:"humanPoisonToxic"`
+	PetPoisonToxic       sql.NullBool   `json:"petPoisonToxic"`
+	HumanEdible          sql.NullBool   `json:"humanEdible"`
+	PetEdible            sql.NullBool   `json:"petEdible"`
+	LangCode             sql.NullString `json:"langCode"`
+	CommonNames          sql.NullString `json:"commonNames"`
+	PlantTypeName        sql.NullString `json:"plantTypeName"`
+	PlantTypeDescription sql.NullString `json:"plantTypeDescription"`
+	LightNeedName        sql.NullString `json:"lightNeedName"`
+	LightNeedDescription sql.NullString `json:"lightNeedDescription"`
+	WaterNeedType        sql.NullString `json:"waterNeedType"`
+	WaterNeedDescription sql.NullString `json:"waterNeedDescription"`
+	WaterNeedDrySoilMm   sql.NullInt32  `json:"waterNeedDrySoilMm"`
+	WaterNeedDrySoilDays sql.NullInt32  `json:"waterNeedDrySoilDays"`
+	CreatedAt            time.Time      `json:"createdAt"`
+	UpdatedAt            time.Time      `json:"updatedAt"`
+}
+
+func (q *Queries) GetAllViewPlantsOrderedByCreated(ctx context.Context, arg GetAllViewPlantsOrderedByCreatedParams) ([]GetAllViewPlantsOrderedByCreatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllViewPlantsOrderedByCreated,
+		arg.LangCode,
+		arg.PetSafe,
+		arg.HumanSafe,
+		arg.PetEdible,
+		arg.HumanEdible,
+		arg.PlantType,
+		arg.LightNeed,
+		arg.WaterModel,
+		arg.MinTemperatureCelsius,
+		arg.MaxTemperatureCelsius,
+		arg.MinHumidityPercent,
+		arg.MaxHumidityPercent,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllViewPlantsOrderedByCreatedRow
+	for rows.Next() {
+		var i GetAllViewPlantsOrderedByCreatedRow
+		if err := rows.Scan(
+			&i.PlantSpeciesID,
+			&i.PlantSpeciesName,
+			&i.HumanPoisonToxic,
+			&i.PetPoisonToxic,
+			&i.HumanEdible,
+			&i.PetEdible,
+			&i.LangCode,
+			&i.CommonNames,
+			&i.PlantTypeName,
+			&i.PlantTypeDescription,
+			&i.LightNeedName,
+			&i.LightNeedDescription,
+			&i.WaterNeedType,
+			&i.WaterNeedDescription,
+			&i.WaterNeedDrySoilMm,
+			&i.WaterNeedDrySoilDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllViewPlantsOrderedByUpdated = `-- name: GetAllViewPlantsOrderedByUpdated :many
 select
   ps.id as plant_species_id,
@@ -29,7 +207,9 @@ select
   wn.plant_type as water_need_type,
   wn.description as water_need_description,
   wn.dry_soil_mm as water_need_dry_soil_mm,
-  wn.dry_soil_days as water_need_dry_soil_days
+  wn.dry_soil_days as water_need_dry_soil_days,
+  ps.created_at,
+  ps.updated_at
 from
   plant_species as ps
 left join
@@ -45,7 +225,28 @@ where
   ps.deleted_at is null and
   pn.deleted_at is null and
   ln.deleted_at is null and
-  wn.deleted_at is null
+  wn.deleted_at is null and
+  -- optional catalog filters, a null filter matches every plant
+  ($2::boolean is null or ps.pet_poison_toxic = not $2::boolean) and
+  ($3::boolean is null or ps.human_poison_toxic = not $3::boolean) and
+  ($4::boolean is null or ps.pet_edible = $4::boolean) and
+  ($5::boolean is null or ps.human_edible = $5::boolean) and
+  ($6::text is null or lower(pt.name) = lower($6::text)) and
+  ($7::text is null or lower(ln.name) = lower($7::text)) and
+  (
+    $8::text is null or
+    ($8::text = 'days' and wn.dry_soil_days is not null) or
+    ($8::text = 'mm' and wn.dry_soil_mm is not null)
+  ) and
+  -- the plant type's range overlaps the requested range when each starts before the other ends
+  ($9::integer is null or pt.max_temperature_celsius >= $9::integer) and
+  ($10::integer is null or pt.min_temperature_celsius <= $10::integer) and
+  ($11::integer is null or pt.max_humidity_percent >= $11::integer) and
+  ($12::integer is null or pt.min_humidity_percent <= $12::integer) and
+  (
+    $13::timestamptz is null or
+    (ps.updated_at, ps.id) < ($13::timestamptz, $14::uuid)
+  )
 group by
   ps.id,
   ps.species_name,
@@ -60,11 +261,33 @@ group by
   wn.plant_type,
   wn.description,
   wn.dry_soil_mm,
-  wn.dry_soil_days
+  wn.dry_soil_days,
+  ps.created_at,
+  ps.updated_at
 order by
-  ps.updated_at desc
+  ps.updated_at desc,
+  ps.id desc
+limit $15
 `
 
+type GetAllViewPlantsOrderedByUpdatedParams struct {
+	LangCode              sql.NullString `json:"langCode"`
+	PetSafe               sql.NullBool   `json:"petSafe"`
+	HumanSafe             sql.NullBool   `json:"humanSafe"`
+	PetEdible             sql.NullBool   `json:"petEdible"`
+	HumanEdible           sql.NullBool   `json:"humanEdible"`
+	PlantType             sql.NullString `json:"plantType"`
+	LightNeed             sql.NullString `json:"lightNeed"`
+	WaterModel            sql.NullString `json:"waterModel"`
+	MinTemperatureCelsius sql.NullInt32  `json:"minTemperatureCelsius"`
+	MaxTemperatureCelsius sql.NullInt32  `json:"maxTemperatureCelsius"`
+	MinHumidityPercent    sql.NullInt32  `json:"minHumidityPercent"`
+	MaxHumidityPercent    sql.NullInt32  `json:"maxHumidityPercent"`
+	CursorTime            sql.NullTime   `json:"cursorTime"`
+	CursorID              uuid.NullUUID  `json:"cursorID"`
+	RowLimit              int32          `json:"rowLimit"`
+}
+
 type GetAllViewPlantsOrderedByUpdatedRow struct {
 	PlantSpeciesID       uuid.UUID      `json:"plantSpeciesID"`
 	PlantSpeciesName     string         `json:"plantSpeciesName"`
@@ -82,10 +305,28 @@ type GetAllViewPlantsOrderedByUpdatedRow struct {
 	WaterNeedDescription sql.NullString `json:"waterNeedDescription"`
 	WaterNeedDrySoilMm   sql.NullInt32  `json:"waterNeedDrySoilMm"`
 	WaterNeedDrySoilDays sql.NullInt32  `json:"waterNeedDrySoilDays"`
+	CreatedAt            time.Time      `json:"createdAt"`
+	UpdatedAt            time.Time      `json:"updatedAt"`
 }
 
-func (q *Queries) GetAllViewPlantsOrderedByUpdated(ctx context.Context, langCode sql.NullString) ([]GetAllViewPlantsOrderedByUpdatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllViewPlantsOrderedByUpdated, langCode)
+func (q *Queries) GetAllViewPlantsOrderedByUpdated(ctx context.Context, arg GetAllViewPlantsOrderedByUpdatedParams) ([]GetAllViewPlantsOrderedByUpdatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllViewPlantsOrderedByUpdated,
+		arg.LangCode,
+		arg.PetSafe,
+		arg.HumanSafe,
+		arg.PetEdible,
+		arg.HumanEdible,
+		arg.PlantType,
+		arg.LightNeed,
+		arg.WaterModel,
+		arg.MinTemperatureCelsius,
+		arg.MaxTemperatureCelsius,
+		arg.MinHumidityPercent,
+		arg.MaxHumidityPercent,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +351,294 @@ func (q *Queries) GetAllViewPlantsOrderedByUpdated(ctx context.Context, langCode
 			&i.WaterNeedDescription,
 			&i.WaterNeedDrySoilMm,
 			&i.WaterNeedDrySoilDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersPlantSpeciesDetailsByID = `-- name: GetUsersPlantSpeciesDetailsByID :one
+select
+  ps.id as plant_species_id,
+  ps.species_name as plant_species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  u.lang_code_pref as lang_code,
+  (
+    select
+      string_agg(pn.common_name, ', ')
+    from plant_names as pn
+    where
+      pn.plant_id = ps.id and
+      pn.lang_code = u.lang_code_pref and
+      pn.deleted_at is null
+  ) as common_names,
+  pt.name as plant_type_name,
+  pt.description as plant_type_description,
+  pt.max_temperature_celsius,
+  pt.min_temperature_celsius,
+  pt.max_humidity_percent,
+  pt.min_humidity_percent,
+  pt.soil_organic_mix,
+  pt.soil_grit_mix,
+  pt.soil_drainage_mix,
+  ln.name as light_need_name,
+  ln.description as light_need_description,
+  wn.plant_type as water_need_type,
+  wn.description as water_need_description,
+  wn.dry_soil_mm as water_need_dry_soil_mm,
+  wn.dry_soil_days as water_need_dry_soil_days
+from
+  users_plants as up
+join
+  users as u on up.user_id = u.id
+join
+  plant_species as ps on up.plant_id = ps.id
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
+left join
+  light_needs as ln on ps.light_needs_id = ln.id and ln.deleted_at is null
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+where
+  up.user_id = $1 and
+  up.id = $2 and
+  up.deleted_at is null
+`
+
+type GetUsersPlantSpeciesDetailsByIDParams struct {
+	UserID uuid.UUID `json:"userID"`
+	ID     uuid.UUID `json:"id"`
+}
+
+type GetUsersPlantSpeciesDetailsByIDRow struct {
+	PlantSpeciesID        uuid.UUID      `json:"plantSpeciesID"`
+	PlantSpeciesName      string         `json:"plantSpeciesName"`
+	HumanPoisonToxic      sql.NullBool   `json:"humanPoisonToxic"`
+	PetPoisonToxic        sql.NullBool   `json:"petPoisonToxic"`
+	HumanEdible           sql.NullBool   `json:"humanEdible"`
+	PetEdible             sql.NullBool   `json:"petEdible"`
+	LangCode              string         `json:"langCode"`
+	CommonNames           sql.NullString `json:"commonNames"`
+	PlantTypeName         sql.NullString `json:"plantTypeName"`
+	PlantTypeDescription  sql.NullString `json:"plantTypeDescription"`
+	MaxTemperatureCelsius sql.NullInt32  `json:"maxTemperatureCelsius"`
+	MinTemperatureCelsius sql.NullInt32  `json:"minTemperatureCelsius"`
+	MaxHumidityPercent    sql.NullInt32  `json:"maxHumidityPercent"`
+	MinHumidityPercent    sql.NullInt32  `json:"minHumidityPercent"`
+	SoilOrganicMix        sql.NullString `json:"soilOrganicMix"`
+	SoilGritMix           sql.NullString `json:"soilGritMix"`
+	SoilDrainageMix       sql.NullString `json:"soilDrainageMix"`
+	LightNeedName         sql.NullString `json:"lightNeedName"`
+	LightNeedDescription  sql.NullString `json:"lightNeedDescription"`
+	WaterNeedType         sql.NullString `json:"waterNeedType"`
+	WaterNeedDescription  sql.NullString `json:"waterNeedDescription"`
+	WaterNeedDrySoilMm    sql.NullInt32  `json:"waterNeedDrySoilMm"`
+	WaterNeedDrySoilDays  sql.NullInt32  `json:"waterNeedDrySoilDays"`
+}
+
+func (q *Queries) GetUsersPlantSpeciesDetailsByID(ctx context.Context, arg GetUsersPlantSpeciesDetailsByIDParams) (GetUsersPlantSpeciesDetailsByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getUsersPlantSpeciesDetailsByID, arg.UserID, arg.ID)
+	var i GetUsersPlantSpeciesDetailsByIDRow
+	err := row.Scan(
+		&i.PlantSpeciesID,
+		&i.PlantSpeciesName,
+		&i.HumanPoisonToxic,
+		&i.PetPoisonToxic,
+		&i.HumanEdible,
+		&i.PetEdible,
+		&i.LangCode,
+		&i.CommonNames,
+		&i.PlantTypeName,
+		&i.PlantTypeDescription,
+		&i.MaxTemperatureCelsius,
+		&i.MinTemperatureCelsius,
+		&i.MaxHumidityPercent,
+		&i.MinHumidityPercent,
+		&i.SoilOrganicMix,
+		&i.SoilGritMix,
+		&i.SoilDrainageMix,
+		&i.LightNeedName,
+		&i.LightNeedDescription,
+		&i.WaterNeedType,
+		&i.WaterNeedDescription,
+		&i.WaterNeedDrySoilMm,
+		&i.WaterNeedDrySoilDays,
+	)
+	return i, err
+}
+
+const searchViewPlants = `-- name: SearchViewPlants :many
+with name_matches as (
+  -- species names and the common names in the requested language are both searched,
+  -- each table is matched with the expressions of its indexes from 013_plant_search.sql.
+  -- prefix matches use ilike, which the trigram indexes support, with the wildcards in the query escaped
+  select
+    ps.id as plant_id,
+    ps.species_name as matched_name
+  from plant_species as ps
+  where
+    ps.deleted_at is null and (
+      ps.species_name ilike replace(replace(replace($1::text, '\', '\\'), '%', '\%'), '_', '\_') || '%' or
+      to_tsvector('simple', ps.species_name) @@ plainto_tsquery('simple', $1::text) or
+      $1::text <% ps.species_name
+    )
+  union all
+  select
+    pn.plant_id,
+    pn.common_name as matched_name
+  from plant_names as pn
+  where
+    pn.lang_code = $2::text and
+    pn.common_name is not null and
+    pn.deleted_at is null and (
+      pn.common_name ilike replace(replace(replace($1::text, '\', '\\'), '%', '\%'), '_', '\_') || '%' or
+      to_tsvector('simple', coalesce(pn.common_name, '')) @@ plainto_tsquery('simple', $1::text) or
+      $1::text <% pn.common_name
+    )
+),
+-- exact matches rank first, then prefix matches, then full text matches, then fuzzy matches
+ranked_matches as (
+  select
+    plant_id,
+    min(
+      case
+        when lower(matched_name) = lower($1::text) then 0
+        when starts_with(lower(matched_name), lower($1::text)) then 1
+        when to_tsvector('simple', matched_name) @@ plainto_tsquery('simple', $1::text) then 2
+        else 3
+      end
+    ) as match_rank,
+    max(word_similarity($1::text, matched_name)) as match_similarity
+  from name_matches
+  group by plant_id
+)
+select
+  ps.id as plant_species_id,
+  ps.species_name as plant_species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  nullif(max(pn.lang_code), '') as lang_code,
+  nullif(string_agg(pn.common_name, ', '), '') as common_names,
+  pt.name as plant_type_name,
+  pt.description as plant_type_description,
+  ln.name as light_need_name,
+  ln.description as light_need_description,
+  wn.plant_type as water_need_type,
+  wn.description as water_need_description,
+  wn.dry_soil_mm as water_need_dry_soil_mm,
+  wn.dry_soil_days as water_need_dry_soil_days,
+  ps.created_at,
+  ps.updated_at
+from
+  ranked_matches as rm
+join
+  plant_species as ps on rm.plant_id = ps.id
+left join
+  plant_names as pn on ps.id = pn.plant_id and pn.lang_code = $2::text and pn.deleted_at is null
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
+left join
+  light_needs as ln on ps.light_needs_id = ln.id and ln.deleted_at is null
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+where
+  ps.deleted_at is null
+group by
+  ps.id,
+  ps.species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  pt.name,
+  pt.description,
+  ln.name,
+  ln.description,
+  wn.plant_type,
+  wn.description,
+  wn.dry_soil_mm,
+  wn.dry_soil_days,
+  ps.created_at,
+  ps.updated_at,
+  rm.match_rank,
+  rm.match_similarity
+order by
+  rm.match_rank asc,
+  rm.match_similarity desc,
+  ps.species_name asc
+limit $3
+`
+
+type SearchViewPlantsParams struct {
+	Query    string `json:"query"`
+	LangCode string `json:"langCode"`
+	RowLimit int32  `json:"rowLimit"`
+}
+
+type SearchViewPlantsRow struct {
+	PlantSpeciesID       uuid.UUID      `json:"plantSpeciesID"`
+	PlantSpeciesName     string         `json:"plantSpeciesName"`
+	HumanPoisonToxic     sql.NullBool   `json:"humanPoisonToxic"`
+	PetPoisonToxic       sql.NullBool   `json:"petPoisonToxic"`
+	HumanEdible          sql.NullBool   `json:"humanEdible"`
+	PetEdible            sql.NullBool   `json:"petEdible"`
+	LangCode             sql.NullString `json:"langCode"`
+	CommonNames          sql.NullString `json:"commonNames"`
+	PlantTypeName        sql.NullString `json:"plantTypeName"`
+	PlantTypeDescription sql.NullString `json:"plantTypeDescription"`
+	LightNeedName        sql.NullString `json:"lightNeedName"`
+	LightNeedDescription sql.NullString `json:"lightNeedDescription"`
+	WaterNeedType        sql.NullString `json:"waterNeedType"`
+	WaterNeedDescription sql.NullString `json:"waterNeedDescription"`
+	WaterNeedDrySoilMm   sql.NullInt32  `json:"waterNeedDrySoilMm"`
+	WaterNeedDrySoilDays sql.NullInt32  `json:"waterNeedDrySoilDays"`
+	CreatedAt            time.Time      `json:"createdAt"`
+	UpdatedAt            time.Time      `json:"updatedAt"`
+}
+
+func (q *Queries) SearchViewPlants(ctx context.Context, arg SearchViewPlantsParams) ([]SearchViewPlantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchViewPlants, arg.Query, arg.LangCode, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchViewPlantsRow
+	for rows.Next() {
+		var i SearchViewPlantsRow
+		if err := rows.Scan(
+			&i.PlantSpeciesID,
+			&i.PlantSpeciesName,
+			&i.HumanPoisonToxic,
+			&i.PetPoisonToxic,
+			&i.HumanEdible,
+			&i.PetEdible,
+			&i.LangCode,
+			&i.CommonNames,
+			&i.PlantTypeName,
+			&i.PlantTypeDescription,
+			&i.LightNeedName,
+			&i.LightNeedDescription,
+			&i.WaterNeedType,
+			&i.WaterNeedDescription,
+			&i.WaterNeedDrySoilMm,
+			&i.WaterNeedDrySoilDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,332 @@
+/*
+Package logfile provides a log file writer that rotates by size and age,
+compresses rotated files, and removes old ones.
+*/
+package logfile
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat sorts rotated files by name in the order they were rotated
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+const compressedSuffix = ".gz"
+
+// === Types ===
+
+// Options control when the log file is rotated and how long rotated files are kept.
+// A zero value disables that limit.
+type Options struct {
+	// MaxSize rotates the file before a write would make it larger, in bytes
+	MaxSize int64
+	// Interval rotates the file once it has been written to for this long
+	Interval time.Duration
+	// MaxBackups is how many rotated files are kept
+	MaxBackups int
+	// MaxAge removes rotated files older than this
+	MaxAge time.Duration
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// RotatingWriter is an io.WriteCloser over a log file that is rotated as it is written to.
+// Rotated files are renamed with the time they were rotated, such as
+// 'server-2025-07-01T12-00-00.000.log', and are compressed and pruned in the background.
+type RotatingWriter struct {
+	path string
+	opts Options
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// one cleanup runs at a time, Close waits for it
+	cleanupMu sync.Mutex
+	cleanups  sync.WaitGroup
+
+	now func() time.Time
+}
+
+// === Constructor ===
+
+// New opens the log file at path for appending, creating it and its directory when missing.
+func New(path string, opts Options) (*RotatingWriter, error) {
+	if path == "" {
+		return nil, errors.New("log file path is empty")
+	}
+
+	w := &RotatingWriter{
+		path: path,
+		opts: opts,
+		now:  time.Now,
+	}
+
+	err := w.open()
+	if err != nil {
+		return nil, err
+	}
+
+	// rotated files from previous runs are pruned on start
+	w.startCleanup("")
+	return w, nil
+}
+
+func (w *RotatingWriter) open() error {
+	err := os.MkdirAll(filepath.Dir(w.path), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.now()
+	return nil
+}
+
+// === Writing ===
+
+// Write appends p to the log file, rotating it first when it is too large or too old.
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		err := w.rotate()
+		if w.file == nil {
+			return 0, err
+		}
+		// the file could not be rotated but is still open, so the write is not lost
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logfile: could not rotate %q: %v\n", w.path, err)
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotatingWriter) shouldRotate(writeSize int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.opts.MaxSize > 0 && w.size+writeSize > w.opts.MaxSize {
+		return true
+	}
+	if w.opts.Interval > 0 && w.now().Sub(w.openedAt) >= w.opts.Interval {
+		return true
+	}
+	return false
+}
+
+// Rotate closes the current file, renames it, and opens a new one.
+func (w *RotatingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// must be called with the lock held.
+// the log file at path is opened again even when it could not be renamed, and the file is only
+// left closed when that fails as well
+func (w *RotatingWriter) rotate() error {
+	err := w.file.Close()
+	w.file = nil
+
+	backup := ""
+	if err == nil {
+		backup = w.backupName(w.now())
+		err = os.Rename(w.path, backup)
+		if err != nil {
+			backup = ""
+			if errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		}
+	}
+
+	openErr := w.open()
+	if backup != "" {
+		w.startCleanup(backup)
+	}
+	return errors.Join(err, openErr)
+}
+
+// Close waits for background cleanup and closes the file.
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	w.cleanups.Wait()
+	return err
+}
+
+// === Backups ===
+
+// splits 'log/server.log' into 'log/server' and '.log'
+func (w *RotatingWriter) nameParts() (string, string) {
+	ext := filepath.Ext(w.path)
+	return strings.TrimSuffix(w.path, ext), ext
+}
+
+func (w *RotatingWriter) backupName(rotatedAt time.Time) string {
+	prefix, ext := w.nameParts()
+	return fmt.Sprintf("%s-%s%s", prefix, rotatedAt.UTC().Format(backupTimeFormat), ext)
+}
+
+// compresses the file that was just rotated, if any, and prunes old backups
+func (w *RotatingWriter) startCleanup(rotated string) {
+	w.cleanups.Add(1)
+	go func() {
+		defer w.cleanups.Done()
+		w.cleanupMu.Lock()
+		defer w.cleanupMu.Unlock()
+
+		// cleanups may run out of order, a newer one can already have pruned the rotated file
+		if rotated != "" && w.opts.Compress {
+			err := compressFile(rotated)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				fmt.Fprintf(os.Stderr, "logfile: could not compress %q: %v\n", rotated, err)
+			}
+		}
+
+		err := w.prune()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logfile: could not remove old log files: %v\n", err)
+		}
+	}()
+}
+
+type backup struct {
+	path      string
+	rotatedAt time.Time
+}
+
+// lists rotated files, newest first
+func (w *RotatingWriter) backups() ([]backup, error) {
+	prefix, ext := w.nameParts()
+	dir := filepath.Dir(w.path)
+	base := filepath.Base(prefix) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, base) {
+			continue
+		}
+
+		timestamp := strings.TrimPrefix(name, base)
+		timestamp = strings.TrimSuffix(timestamp, compressedSuffix)
+		timestamp, found := strings.CutSuffix(timestamp, ext)
+		if !found {
+			continue
+		}
+
+		rotatedAt, err := time.Parse(backupTimeFormat, timestamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), rotatedAt: rotatedAt})
+	}
+
+	slices.SortFunc(backups, func(a, b backup) int {
+		return b.rotatedAt.Compare(a.rotatedAt)
+	})
+	return backups, nil
+}
+
+// removes backups past MaxBackups or older than MaxAge
+func (w *RotatingWriter) prune() error {
+	if w.opts.MaxBackups <= 0 && w.opts.MaxAge <= 0 {
+		return nil
+	}
+
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	cutoff := w.now().Add(-w.opts.MaxAge)
+	for i, backup := range backups {
+		tooMany := w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups
+		tooOld := w.opts.MaxAge > 0 && backup.rotatedAt.Before(cutoff)
+		if tooMany || tooOld {
+			err := os.Remove(backup.path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// gzips path into 'path.gz' and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressedSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + compressedSuffix)
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,287 @@
+package logfile
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock is the time of a writer, moved forward by the test
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestWriter(t *testing.T, opts Options) (*RotatingWriter, *testClock) {
+	t.Helper()
+
+	clock := &testClock{now: time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)}
+	w, err := New(filepath.Join(t.TempDir(), "log", "server.log"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { w.Close() })
+
+	// the cleanup started by New reads the clock
+	w.cleanups.Wait()
+	w.mu.Lock()
+	w.now = clock.Now
+	w.openedAt = clock.Now()
+	w.mu.Unlock()
+	return w, clock
+}
+
+func write(t *testing.T, w *RotatingWriter, s string) {
+	t.Helper()
+	_, err := w.Write([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// lists the file names in the directory of the log file, waiting for cleanup to finish first
+func listFiles(t *testing.T, w *RotatingWriter) []string {
+	t.Helper()
+	w.cleanups.Wait()
+
+	entries, err := os.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestRotateBySize(t *testing.T) {
+	w, clock := newTestWriter(t, Options{MaxSize: 10})
+
+	write(t, w, "first\n")
+	clock.Add(time.Second)
+	write(t, w, "second\n")
+	write(t, w, "x\n")
+
+	want := []string{"server-2025-07-01T12-00-01.000.log", "server.log"}
+	if got := listFiles(t, w); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := readFile(t, filepath.Join(filepath.Dir(w.path), want[0])); got != "first\n" {
+		t.Errorf("rotated file = %q, want %q", got, "first\n")
+	}
+	if got := readFile(t, w.path); got != "second\nx\n" {
+		t.Errorf("log file = %q, want %q", got, "second\nx\n")
+	}
+}
+
+func TestNoRotateWhenEmpty(t *testing.T) {
+	w, _ := newTestWriter(t, Options{MaxSize: 4})
+
+	// a single write larger than the limit goes to the empty file
+	write(t, w, "longer than four\n")
+
+	if got := listFiles(t, w); !slices.Equal(got, []string{"server.log"}) {
+		t.Errorf("files = %v, want only the log file", got)
+	}
+}
+
+func TestRotateByInterval(t *testing.T) {
+	w, clock := newTestWriter(t, Options{Interval: time.Hour})
+
+	write(t, w, "first\n")
+	clock.Add(time.Minute * 59)
+	write(t, w, "second\n")
+	if got := listFiles(t, w); len(got) != 1 {
+		t.Fatalf("rotated before the interval passed, files = %v", got)
+	}
+
+	clock.Add(time.Minute)
+	write(t, w, "third\n")
+
+	want := []string{"server-2025-07-01T13-00-00.000.log", "server.log"}
+	if got := listFiles(t, w); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if got := readFile(t, w.path); got != "third\n" {
+		t.Errorf("log file = %q, want %q", got, "third\n")
+	}
+
+	// the interval starts again from the rotation
+	clock.Add(time.Minute * 30)
+	write(t, w, "fourth\n")
+	if got := listFiles(t, w); len(got) != 2 {
+		t.Errorf("rotated again before the interval passed, files = %v", got)
+	}
+}
+
+func TestPruneByCount(t *testing.T) {
+	w, clock := newTestWriter(t, Options{MaxBackups: 2})
+
+	for range 4 {
+		write(t, w, "line\n")
+		clock.Add(time.Minute)
+		err := w.Rotate()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"server-2025-07-01T12-03-00.000.log", "server-2025-07-01T12-04-00.000.log", "server.log"}
+	if got := listFiles(t, w); !slices.Equal(got, want) {
+		t.Errorf("files = %v, want the two newest backups %v", got, want)
+	}
+}
+
+func TestPruneByAge(t *testing.T) {
+	w, clock := newTestWriter(t, Options{MaxAge: time.Hour * 24})
+
+	write(t, w, "old\n")
+	err := w.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Add(time.Hour * 23)
+	write(t, w, "recent\n")
+	err = w.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := listFiles(t, w); len(got) != 3 {
+		t.Fatalf("pruned a backup that is not too old yet, files = %v", got)
+	}
+
+	clock.Add(time.Hour * 2)
+	write(t, w, "new\n")
+	err = w.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"server-2025-07-02T11-00-00.000.log", "server-2025-07-02T13-00-00.000.log", "server.log"}
+	if got := listFiles(t, w); !slices.Equal(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	w, _ := newTestWriter(t, Options{Compress: true})
+
+	write(t, w, "compressed\n")
+	err := w.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"server-2025-07-01T12-00-00.000.log.gz", "server.log"}
+	if got := listFiles(t, w); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	file, err := os.Open(filepath.Join(filepath.Dir(w.path), want[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "compressed\n" {
+		t.Errorf("compressed file = %q, want %q", data, "compressed\n")
+	}
+}
+
+func TestCompressedBackupsArePruned(t *testing.T) {
+	w, clock := newTestWriter(t, Options{MaxBackups: 1, Compress: true})
+
+	for range 3 {
+		write(t, w, "line\n")
+		clock.Add(time.Minute)
+		err := w.Rotate()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := listFiles(t, w)
+	if len(got) != 2 || !strings.HasSuffix(got[0], ".log.gz") {
+		t.Errorf("files = %v, want one compressed backup and the log file", got)
+	}
+}
+
+func TestRotateFailureKeepsWriting(t *testing.T) {
+	w, _ := newTestWriter(t, Options{MaxSize: 10})
+
+	// a directory in the way of the backup makes renaming the log file fail
+	blocker := w.backupName(w.now())
+	err := os.MkdirAll(filepath.Join(blocker, "taken"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write(t, w, "first\n")
+	_, err = w.Write([]byte("second\n"))
+	if err != nil {
+		t.Fatalf("Write after a failed rotation = %v, want the line written", err)
+	}
+
+	err = w.Rotate()
+	if err == nil {
+		t.Error("Rotate with the backup name taken returned no error")
+	}
+	write(t, w, "third\n")
+
+	if got := readFile(t, w.path); got != "first\nsecond\nthird\n" {
+		t.Errorf("log file = %q, want every line in the original file", got)
+	}
+}
+
+func TestClose(t *testing.T) {
+	w, _ := newTestWriter(t, Options{})
+
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = w.Write([]byte("closed\n"))
+	if err != os.ErrClosed {
+		t.Errorf("Write after Close = %v, want %v", err, os.ErrClosed)
+	}
+	if err := w.Rotate(); err != os.ErrClosed {
+		t.Errorf("Rotate after Close = %v, want %v", err, os.ErrClosed)
+	}
+}
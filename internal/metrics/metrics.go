@@ -0,0 +1,374 @@
+/*
+Package metrics records counters, gauges, and histograms and serves them
+in the Prometheus text exposition format.
+*/
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the Prometheus text exposition format, version 0.0.4.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the histogram upper bounds in seconds, suited to request latencies.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// === Registry ===
+
+// Registry holds every metric served by its handler.
+type Registry struct {
+	mu         sync.Mutex
+	metrics    []metric
+	onScrape   []func(ctx context.Context)
+	scrapeLock sync.Mutex
+}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// OnScrape adds a function that runs before every scrape,
+// used to update gauges that are read from elsewhere, such as the database.
+func (r *Registry) OnScrape(fn func(ctx context.Context)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onScrape = append(r.onScrape, fn)
+}
+
+// WriteTo writes every metric in the text exposition format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	metrics := slices.Clone(r.metrics)
+	r.mu.Unlock()
+
+	counter := &countingWriter{w: w}
+	buf := bufio.NewWriter(counter)
+	for _, m := range metrics {
+		m.write(buf)
+	}
+	err := buf.Flush()
+	return counter.n, err
+}
+
+// Handler serves the metrics, running the scrape functions first.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// scrapes do not overlap, so the gauges they set are consistent
+		r.scrapeLock.Lock()
+		defer r.scrapeLock.Unlock()
+
+		r.mu.Lock()
+		onScrape := slices.Clone(r.onScrape)
+		r.mu.Unlock()
+		for _, fn := range onScrape {
+			fn(req.Context())
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		w.WriteHeader(http.StatusOK)
+		r.WriteTo(w)
+	})
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// === Labels ===
+
+// labelled keeps one series per combination of label values
+type labelled[T any] struct {
+	mu     sync.Mutex
+	labels []string
+	series map[string]*T
+	order  []string
+	values map[string][]string
+	create func() *T
+}
+
+func newLabelled[T any](labels []string, create func() *T) *labelled[T] {
+	return &labelled[T]{
+		labels: labels,
+		series: make(map[string]*T),
+		values: make(map[string][]string),
+		create: create,
+	}
+}
+
+func (l *labelled[T]) with(values []string) *T {
+	if len(values) != len(l.labels) {
+		panic(fmt.Sprintf("metrics: expected %d label values, got %d", len(l.labels), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	series, ok := l.series[key]
+	if !ok {
+		series = l.create()
+		l.series[key] = series
+		l.values[key] = slices.Clone(values)
+		l.order = append(l.order, key)
+		slices.Sort(l.order)
+	}
+	return series
+}
+
+// calls fn for every series in a stable order
+func (l *labelled[T]) each(fn func(values []string, series *T)) {
+	l.mu.Lock()
+	values := make([][]string, 0, len(l.order))
+	series := make([]*T, 0, len(l.order))
+	for _, key := range l.order {
+		values = append(values, l.values[key])
+		series = append(series, l.series[key])
+	}
+	l.mu.Unlock()
+
+	for i := range series {
+		fn(values[i], series[i])
+	}
+}
+
+// formats labels as '{name="value",...}', extra is appended after the labels
+func formatLabels(names, values []string, extra ...string) string {
+	if len(names) == 0 && len(extra) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", name, escapeLabel(values[i]))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", extra[i], escapeLabel(extra[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, helpEscaper.Replace(help), name, kind)
+}
+
+// === Counter ===
+
+// CounterVec is a set of counters that only go up, one per combination of label values.
+type CounterVec struct {
+	name string
+	help string
+	vec  *labelled[counter]
+}
+
+type counter struct {
+	mu    sync.Mutex
+	value float64
+}
+
+// NewCounterVec registers a counter with the given label names.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		name: name,
+		help: help,
+		vec:  newLabelled(labels, func() *counter { return &counter{} }),
+	}
+	r.register(c)
+	return c
+}
+
+// Add increases the counter of the label values by delta, which must not be negative.
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		panic("metrics: counters can not decrease")
+	}
+	series := c.vec.with(values)
+	series.mu.Lock()
+	series.value += delta
+	series.mu.Unlock()
+}
+
+// Inc increases the counter of the label values by one.
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	c.vec.each(func(values []string, series *counter) {
+		series.mu.Lock()
+		value := series.value
+		series.mu.Unlock()
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.vec.labels, values), formatValue(value))
+	})
+}
+
+// === Gauge ===
+
+// Gauge is a single value that can go up and down.
+type Gauge struct {
+	name  string
+	help  string
+	kind  string
+	mu    sync.Mutex
+	value float64
+}
+
+// NewGauge registers a gauge.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	g := &Gauge{name: name, help: help, kind: "gauge"}
+	r.register(g)
+	return g
+}
+
+// NewCounter registers a counter that is set from a cumulative total kept elsewhere,
+// such as the wait count of a database pool.
+func (r *Registry) NewCounter(name, help string) *Gauge {
+	g := &Gauge{name: name, help: help, kind: "counter"}
+	r.register(g)
+	return g
+}
+
+// Set replaces the value.
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	g.value = value
+	g.mu.Unlock()
+}
+
+// Add changes the value by delta.
+func (g *Gauge) Add(delta float64) {
+	g.mu.Lock()
+	g.value += delta
+	g.mu.Unlock()
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.mu.Lock()
+	value := g.value
+	g.mu.Unlock()
+
+	writeHeader(w, g.name, g.help, g.kind)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatValue(value))
+}
+
+// === Histogram ===
+
+// HistogramVec counts observations into buckets, one histogram per combination of label values.
+type HistogramVec struct {
+	name    string
+	help    string
+	buckets []float64
+	vec     *labelled[histogram]
+}
+
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec registers a histogram with the given bucket upper bounds and label names.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	h := &HistogramVec{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		vec: newLabelled(labels, func() *histogram {
+			return &histogram{counts: make([]uint64, len(buckets))}
+		}),
+	}
+	r.register(h)
+	return h
+}
+
+// Observe records a value in the histogram of the label values.
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	series := h.vec.with(values)
+	series.mu.Lock()
+	defer series.mu.Unlock()
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	h.vec.each(func(values []string, series *histogram) {
+		series.mu.Lock()
+		counts := slices.Clone(series.counts)
+		count, sum := series.count, series.sum
+		series.mu.Unlock()
+
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.vec.labels, values, "le", formatValue(bound)), counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.vec.labels, values, "le", "+Inf"), count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.vec.labels, values), formatValue(sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.vec.labels, values), count)
+	})
+}
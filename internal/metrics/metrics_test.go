@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExposition(t *testing.T) {
+	registry := NewRegistry()
+
+	requests := registry.NewCounterVec("test_requests_total", "Requests handled.", "method", "code")
+	requests.Inc("POST", "201")
+	requests.Add(2, "GET", "200")
+
+	inFlight := registry.NewGauge("test_in_flight", "Requests being handled.")
+	inFlight.Add(3)
+	inFlight.Add(-1)
+
+	waits := registry.NewCounter("test_waits_total", "Waits for a connection.")
+	waits.Set(5)
+
+	duration := registry.NewHistogramVec("test_duration_seconds", "Time taken.", []float64{1, 0.5}, "route")
+	duration.Observe(0.25, "/a")
+	duration.Observe(0.75, "/a")
+	duration.Observe(2, "/a")
+
+	var b strings.Builder
+	n, err := registry.WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// series are sorted by label values, buckets by their bound
+	want := `# HELP test_requests_total Requests handled.
+# TYPE test_requests_total counter
+test_requests_total{method="GET",code="200"} 2
+test_requests_total{method="POST",code="201"} 1
+# HELP test_in_flight Requests being handled.
+# TYPE test_in_flight gauge
+test_in_flight 2
+# HELP test_waits_total Waits for a connection.
+# TYPE test_waits_total counter
+test_waits_total 5
+# HELP test_duration_seconds Time taken.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{route="/a",le="0.5"} 1
+test_duration_seconds_bucket{route="/a",le="1"} 2
+test_duration_seconds_bucket{route="/a",le="+Inf"} 3
+test_duration_seconds_sum{route="/a"} 3
+test_duration_seconds_count{route="/a"} 3
+`
+	if b.String() != want {
+		t.Errorf("exposition =\n%s\nwant\n%s", b.String(), want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("WriteTo returned %d bytes, want %d", n, len(want))
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.NewCounterVec("test_total", "Help with a \\ backslash\nand a newline.", "path")
+	counter.Inc("a\\b\"c\nd")
+
+	var b strings.Builder
+	_, err := registry.WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP test_total Help with a \\ backslash\nand a newline.
+# TYPE test_total counter
+test_total{path="a\\b\"c\nd"} 1
+`
+	if b.String() != want {
+		t.Errorf("exposition =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0"},
+		{1.5, "1.5"},
+		{1e21, "1e+21"},
+		{math.Inf(1), "+Inf"},
+		{math.Inf(-1), "-Inf"},
+		{math.NaN(), "NaN"},
+	}
+
+	for _, test := range tests {
+		got := formatValue(test.value)
+		if got != test.want {
+			t.Errorf("formatValue(%v) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestLabelValueCount(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounterVec("test_total", "Help.", "method", "code")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc with the wrong number of label values did not panic")
+		}
+	}()
+	counter.Inc("GET")
+}
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	gauge := registry.NewGauge("test_users", "Users.")
+	registry.OnScrape(func(_ context.Context) {
+		gauge.Set(7)
+	})
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	if !strings.Contains(rec.Body.String(), "test_users 7\n") {
+		t.Errorf("body does not have the gauge set on scrape:\n%s", rec.Body.String())
+	}
+}
@@ -0,0 +1,415 @@
+/*
+Package migrate applies the goose formatted schema files bundled into the binary.
+
+It keeps its state in the same 'goose_db_version' table as the goose cli,
+and holds the same advisory lock while migrating, so a database can be migrated by either one.
+*/
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionTable is the table goose records applied migrations in.
+const VersionTable = "goose_db_version"
+
+// LockID is the postgres advisory lock goose takes while migrating.
+// Up and Down hold it as well, so servers that start at the same time migrate one after another.
+const LockID int64 = 5887940537704921958
+
+const (
+	annotationUp            = "-- +goose Up"
+	annotationDown          = "-- +goose Down"
+	annotationNoTransaction = "-- +goose NO TRANSACTION"
+)
+
+// ErrNoMigration is returned by Down when there is nothing left to roll back.
+var ErrNoMigration = errors.New("no migration to roll back")
+
+// ErrNoVersionTable is returned by Version when the database has never been migrated.
+var ErrNoVersionTable = errors.New("version table does not exist, the database has not been migrated")
+
+// === Types ===
+
+// Migration is a single numbered schema file, split into its up and down sections.
+type Migration struct {
+	Version       int64
+	Name          string
+	Up            string
+	Down          string
+	NoTransaction bool
+}
+
+// Status is a migration and whether it has been applied to the database.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator runs the migrations from a directory against a database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// querier is the database, or the connection that holds the migration lock
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// === Loading ===
+
+// New reads every '.sql' file in dir of fsys, the files are ordered by the version
+// number that prefixes their name, such as '001_plants_and_users.sql'.
+func New(db *sql.DB, fsys fs.FS, dir string) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		migration, err := parseMigration(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrations %q and %q have the same version", migrations[i-1].Name, migrations[i].Name)
+		}
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func parseMigration(fsys fs.FS, file string) (Migration, error) {
+	name := path.Base(file)
+	versionStr, _, found := strings.Cut(name, "_")
+	if !found {
+		return Migration{}, fmt.Errorf("migration %q is not named as 'version_name.sql'", name)
+	}
+	version, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil || version < 1 {
+		return Migration{}, fmt.Errorf("migration %q does not start with a positive version number", name)
+	}
+
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	migration := Migration{Version: version, Name: name}
+	var up, down strings.Builder
+	var section *strings.Builder
+	for line := range strings.Lines(string(data)) {
+		switch strings.TrimSpace(line) {
+		case annotationUp:
+			section = &up
+			continue
+		case annotationDown:
+			section = &down
+			continue
+		case annotationNoTransaction:
+			migration.NoTransaction = true
+			continue
+		}
+
+		if section != nil {
+			section.WriteString(line)
+		}
+	}
+	if up.Len() == 0 {
+		return Migration{}, fmt.Errorf("migration %q has no '%s' section", name, annotationUp)
+	}
+
+	migration.Up = up.String()
+	migration.Down = down.String()
+	return migration, nil
+}
+
+// Migrations returns the loaded migrations, oldest first.
+func (m *Migrator) Migrations() []Migration {
+	return slices.Clone(m.migrations)
+}
+
+// Latest is the version of the newest loaded migration.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// === Database State ===
+
+func versionTableExists(ctx context.Context, db querier) (bool, error) {
+	var exists bool
+	err := db.QueryRowContext(ctx, "select to_regclass($1) is not null", VersionTable).Scan(&exists)
+	return exists, err
+}
+
+// creates the version table the same way goose does, when it is missing
+func ensureVersionTable(ctx context.Context, db querier) error {
+	exists, err := versionTableExists(ctx, db)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `create table `+VersionTable+` (
+  id integer primary key generated by default as identity,
+  version_id bigint not null,
+  is_applied boolean not null,
+  tstamp timestamp not null default now()
+)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "insert into "+VersionTable+" (version_id, is_applied) values (0, true)")
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// reads when each applied version was applied, only reading so it is safe for health checks,
+// returns ErrNoVersionTable when the version table is missing
+func applied(ctx context.Context, db querier) (map[int64]time.Time, error) {
+	exists, err := versionTableExists(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNoVersionTable
+	}
+
+	// rows are read oldest first so the latest row for a version wins
+	rows, err := db.QueryContext(ctx, "select version_id, is_applied, tstamp from "+VersionTable+" order by id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var appliedAt time.Time
+		err = rows.Scan(&version, &isApplied, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if isApplied {
+			applied[version] = appliedAt
+		} else {
+			delete(applied, version)
+		}
+	}
+
+	return applied, rows.Err()
+}
+
+// Version is the newest migration applied to the database, 0 when none are.
+// It returns ErrNoVersionTable for a database that has never been migrated.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	applied, err := applied(ctx, m.db)
+	if err != nil {
+		return 0, err
+	}
+	return newestVersion(applied), nil
+}
+
+func newestVersion(applied map[int64]time.Time) int64 {
+	var version int64
+	for appliedVersion := range applied {
+		version = max(version, appliedVersion)
+	}
+	return version
+}
+
+// Status lists every loaded migration and whether it is applied,
+// none are applied in a database that has never been migrated.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := applied(ctx, m.db)
+	if err != nil && !errors.Is(err, ErrNoVersionTable) {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// === Migrating ===
+
+// Up applies every migration that is not applied yet, oldest first,
+// and returns the migrations it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var ran []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		err := ensureVersionTable(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		// read once the lock is held, another server may have just migrated
+		applied, err := applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err = run(ctx, conn, migration, migration.Up, "insert into "+VersionTable+" (version_id, is_applied) values ($1, true)")
+			if err != nil {
+				return fmt.Errorf("applying %q: %w", migration.Name, err)
+			}
+			ran = append(ran, migration)
+		}
+		return nil
+	})
+
+	return ran, err
+}
+
+// Down rolls back the newest applied migration and returns it.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+	var migration Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := applied(ctx, conn)
+		if errors.Is(err, ErrNoVersionTable) {
+			return ErrNoMigration
+		} else if err != nil {
+			return err
+		}
+		version := newestVersion(applied)
+		if version == 0 {
+			return ErrNoMigration
+		}
+
+		index := slices.IndexFunc(m.migrations, func(migration Migration) bool {
+			return migration.Version == version
+		})
+		if index == -1 {
+			return fmt.Errorf("applied version %d has no migration file", version)
+		}
+		migration = m.migrations[index]
+
+		err = run(ctx, conn, migration, migration.Down, "delete from "+VersionTable+" where version_id = $1")
+		if err != nil {
+			return fmt.Errorf("rolling back %q: %w", migration.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Migration{}, err
+	}
+
+	return migration, nil
+}
+
+// runs fn on a connection that holds the migration lock, waiting for the lock when another process holds it
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "select pg_advisory_lock($1)", LockID)
+	if err != nil {
+		return fmt.Errorf("taking the migration lock: %w", err)
+	}
+	defer func() {
+		// unlocked without ctx, so a cancelled migration still releases the lock
+		_, unlockErr := conn.ExecContext(context.Background(), "select pg_advisory_unlock($1)", LockID)
+		if unlockErr != nil {
+			// the lock belongs to the connection, so it is closed instead of going back to the pool
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+			err = errors.Join(err, fmt.Errorf("releasing the migration lock: %w", unlockErr))
+		}
+	}()
+
+	return fn(conn)
+}
+
+// runs one section of a migration and records it in the version table,
+// both happen in one transaction unless the migration opts out
+func run(ctx context.Context, db querier, migration Migration, statements, record string) error {
+	if migration.NoTransaction {
+		if strings.TrimSpace(statements) != "" {
+			_, err := db.ExecContext(ctx, statements)
+			if err != nil {
+				return err
+			}
+		}
+		_, err := db.ExecContext(ctx, record, migration.Version)
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if strings.TrimSpace(statements) != "" {
+		_, err = tx.ExecContext(ctx, statements)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, record, migration.Version)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,372 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var testSchema = fstest.MapFS{
+	"schema/002_locations.sql": {Data: []byte(`-- +goose Up
+create table locations (id uuid primary key);
+
+-- +goose Down
+drop table locations;
+`)},
+	"schema/001_plants.sql": {Data: []byte(`-- +goose Up
+create table plants (id uuid primary key);
+create table users (id uuid primary key);
+
+-- +goose Down
+drop table users;
+drop table plants;
+`)},
+	"schema/010_index.sql": {Data: []byte(`-- +goose NO TRANSACTION
+-- +goose Up
+create index concurrently plants_idx on plants (id);
+
+-- +goose Down
+drop index concurrently plants_idx;
+`)},
+	"schema/README.md":       {Data: []byte("not a migration")},
+	"schema/archive/003.sql": {Data: []byte("-- +goose Up\nselect 1;\n")},
+}
+
+func TestNew(t *testing.T) {
+	migrator, err := New(nil, testSchema, "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrations := migrator.Migrations()
+	var names []string
+	for _, migration := range migrations {
+		names = append(names, migration.Name)
+	}
+	want := []string{"001_plants.sql", "002_locations.sql", "010_index.sql"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("migrations = %v, want %v", names, want)
+	}
+
+	if migrator.Latest() != 10 {
+		t.Errorf("Latest() = %d, want 10", migrator.Latest())
+	}
+
+	plants := migrations[0]
+	if plants.Version != 1 || plants.NoTransaction {
+		t.Errorf("001_plants.sql has version %d and no transaction %v", plants.Version, plants.NoTransaction)
+	}
+	wantUp := "create table plants (id uuid primary key);\ncreate table users (id uuid primary key);\n\n"
+	if plants.Up != wantUp {
+		t.Errorf("001_plants.sql up = %q, want %q", plants.Up, wantUp)
+	}
+	wantDown := "drop table users;\ndrop table plants;\n"
+	if plants.Down != wantDown {
+		t.Errorf("001_plants.sql down = %q, want %q", plants.Down, wantDown)
+	}
+
+	if !migrations[2].NoTransaction {
+		t.Error("010_index.sql is not marked as no transaction")
+	}
+}
+
+func TestNewEmpty(t *testing.T) {
+	migrator, err := New(nil, fstest.MapFS{"schema": {Mode: fs.ModeDir | 0o755}}, "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrator.Latest() != 0 {
+		t.Errorf("Latest() = %d, want 0", migrator.Latest())
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"no version", fstest.MapFS{"schema/plants.sql": {Data: []byte("-- +goose Up\nselect 1;\n")}}},
+		{"version not a number", fstest.MapFS{"schema/one_plants.sql": {Data: []byte("-- +goose Up\nselect 1;\n")}}},
+		{"version zero", fstest.MapFS{"schema/000_plants.sql": {Data: []byte("-- +goose Up\nselect 1;\n")}}},
+		{"no up section", fstest.MapFS{"schema/001_plants.sql": {Data: []byte("create table plants ();\n")}}},
+		{"same version", fstest.MapFS{
+			"schema/001_plants.sql": {Data: []byte("-- +goose Up\nselect 1;\n")},
+			"schema/01_users.sql":   {Data: []byte("-- +goose Up\nselect 1;\n")},
+		}},
+		{"missing dir", fstest.MapFS{}},
+	}
+
+	for _, test := range tests {
+		_, err := New(nil, test.files, "schema")
+		if err == nil {
+			t.Errorf("%s: New returned no error", test.name)
+		}
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	db, state := openFakeDB(t)
+	migrator, err := New(db, testSchema, "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	ran, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 3 {
+		t.Fatalf("Up ran %d migrations, want 3", len(ran))
+	}
+
+	// the lock is held from before the version table is read until every migration is recorded
+	statements := state.statements()
+	lock := slices.Index(statements, "select pg_advisory_lock($1)")
+	unlock := slices.Index(statements, "select pg_advisory_unlock($1)")
+	read := slices.IndexFunc(statements, func(s string) bool { return strings.HasPrefix(s, "select version_id") })
+	index := slices.IndexFunc(statements, func(s string) bool { return strings.HasPrefix(s, "create index concurrently") })
+	if lock == -1 || unlock == -1 || !(lock < read && read < index && index < unlock) {
+		t.Errorf("statements are not run while holding the lock:\n%s", strings.Join(statements, "\n"))
+	}
+
+	version, err := migrator.Version(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 10 {
+		t.Errorf("Version() = %d after Up, want 10", version)
+	}
+
+	ran, err = migrator.Up(ctx)
+	if err != nil || len(ran) != 0 {
+		t.Errorf("second Up ran %d migrations with error %v, want none", len(ran), err)
+	}
+
+	migration, err := migrator.Down(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migration.Version != 10 {
+		t.Errorf("Down rolled back version %d, want 10", migration.Version)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var applied []bool
+	for _, status := range statuses {
+		applied = append(applied, status.Applied)
+	}
+	if !slices.Equal(applied, []bool{true, true, false}) {
+		t.Errorf("applied after Down = %v, want [true true false]", applied)
+	}
+
+	for range 2 {
+		_, err = migrator.Down(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = migrator.Down(ctx)
+	if !errors.Is(err, ErrNoMigration) {
+		t.Errorf("Down with nothing applied = %v, want %v", err, ErrNoMigration)
+	}
+}
+
+func TestReadsHaveNoSideEffects(t *testing.T) {
+	db, state := openFakeDB(t)
+	migrator, err := New(db, testSchema, "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	_, err = migrator.Version(ctx)
+	if !errors.Is(err, ErrNoVersionTable) {
+		t.Errorf("Version() of a new database = %v, want %v", err, ErrNoVersionTable)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			t.Errorf("%s is applied in a new database", status.Name)
+		}
+	}
+
+	_, err = migrator.Down(ctx)
+	if !errors.Is(err, ErrNoMigration) {
+		t.Errorf("Down of a new database = %v, want %v", err, ErrNoMigration)
+	}
+
+	for _, statement := range state.statements() {
+		if !strings.HasPrefix(statement, "select") {
+			t.Errorf("reading the version changed the database with %q", statement)
+		}
+	}
+}
+
+func TestUpFailure(t *testing.T) {
+	db, state := openFakeDB(t)
+	state.fail = "create table locations"
+	migrator, err := New(db, testSchema, "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ran, err := migrator.Up(context.Background())
+	if err == nil {
+		t.Fatal("Up returned no error")
+	}
+	if len(ran) != 1 || ran[0].Version != 1 {
+		t.Errorf("Up ran %v before failing, want only version 1", ran)
+	}
+
+	// the lock is released even though a migration failed
+	if !slices.Contains(state.statements(), "select pg_advisory_unlock($1)") {
+		t.Error("the migration lock was not released")
+	}
+}
+
+// === Fake Database ===
+
+// fakeState is a database that only knows the version table, and records every other statement
+type fakeState struct {
+	mu       sync.Mutex
+	exists   bool
+	versions []int64
+	executed []string
+	fail     string
+}
+
+func (s *fakeState) statements() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.executed)
+}
+
+func (s *fakeState) exec(query string, args []driver.NamedValue) ([][]driver.Value, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query = strings.TrimSpace(query)
+	s.executed = append(s.executed, query)
+	if s.fail != "" && strings.Contains(query, s.fail) {
+		return nil, errors.New("statement failed")
+	}
+
+	switch {
+	case strings.HasPrefix(query, "select to_regclass"):
+		return [][]driver.Value{{s.exists}}, nil
+	case strings.HasPrefix(query, "create table "+VersionTable):
+		s.exists = true
+	case strings.HasPrefix(query, "insert into "+VersionTable) && len(args) == 0:
+		s.versions = append(s.versions, 0)
+	case strings.HasPrefix(query, "insert into "+VersionTable):
+		s.versions = append(s.versions, args[0].Value.(int64))
+	case strings.HasPrefix(query, "delete from "+VersionTable):
+		s.versions = slices.DeleteFunc(s.versions, func(v int64) bool { return v == args[0].Value.(int64) })
+	case strings.HasPrefix(query, "select version_id"):
+		var rows [][]driver.Value
+		for _, version := range s.versions {
+			rows = append(rows, []driver.Value{version, true, time.Now()})
+		}
+		return rows, nil
+	case strings.HasPrefix(query, "select pg_advisory"):
+		return [][]driver.Value{{true}}, nil
+	}
+	return nil, nil
+}
+
+func openFakeDB(t *testing.T) (*sql.DB, *fakeState) {
+	state := &fakeState{}
+	db := sql.OpenDB(fakeConnector{state: state})
+	t.Cleanup(func() { db.Close() })
+	return db, state
+}
+
+type fakeConnector struct {
+	state *fakeState
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{state: c.state}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	state *fakeState
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	_, err := c.state.exec(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.state.exec(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{rows: rows}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return []string{"version_id", "is_applied", "tstamp"}
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
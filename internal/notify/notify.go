@@ -0,0 +1,339 @@
+/*
+Package notify provides the channels that plant care reminders are delivered through,
+and the mailer that account emails, such as password resets, are sent with.
+*/
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// === Types ===
+
+// Reminder is a single plant that is due for care.
+type Reminder struct {
+	PlantID          string    `json:"plantID"`
+	PlantName        string    `json:"plantName,omitempty"`
+	PlantSpeciesName string    `json:"plantSpeciesName"`
+	Status           string    `json:"status"`
+	DueAt            time.Time `json:"dueAt"`
+}
+
+// Message is what is sent to a user through a notification channel.
+// To is the channel target, an email address or a webhook url.
+type Message struct {
+	To        string     `json:"-"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	Reminders []Reminder `json:"reminders"`
+}
+
+// Notifier delivers a message through a single kind of channel.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Mail is a plain text email to a single address.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends account emails, which are not tied to a notification channel of the user.
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// === Log Notifier ===
+
+// LogNotifier writes messages to the log instead of delivering them.
+// It is used for the 'log' channel type, and in place of channels that are not configured.
+// It is also the mailer when SMTP is not configured.
+type LogNotifier struct {
+	sl *slog.Logger
+}
+
+// NewLogNotifier returns a notifier that logs every message at info level.
+func NewLogNotifier(sl *slog.Logger) *LogNotifier {
+	return &LogNotifier{sl: sl}
+}
+
+// Notify logs the message.
+func (n *LogNotifier) Notify(_ context.Context, msg Message) error {
+	n.sl.Info("Notification", "to", msg.To, "subject", msg.Subject, "reminders", len(msg.Reminders))
+	return nil
+}
+
+// Send logs who the mail is to and its subject, the body may hold secrets and is left out.
+func (n *LogNotifier) Send(_ context.Context, mail Mail) error {
+	n.sl.Info("Mail", "to", mail.To, "subject", mail.Subject)
+	return nil
+}
+
+// === SMTP Notifier ===
+
+// smtpTimeout bounds a whole email exchange, unless the context of Send has an earlier deadline
+const smtpTimeout = time.Second * 30
+
+// SMTPNotifier delivers messages as plain text email, and is the mailer when SMTP is configured.
+type SMTPNotifier struct {
+	host string
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPNotifier returns a notifier that sends email through the SMTP server at host:port.
+// Authentication is skipped when username is empty, which is useful for local SMTP servers.
+func NewSMTPNotifier(host, port, username, password, from string) (*SMTPNotifier, error) {
+	if host == "" || port == "" {
+		return nil, errors.New("smtp host and port are required")
+	}
+	if from == "" {
+		return nil, errors.New("smtp from address is required")
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPNotifier{
+		host: host,
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}, nil
+}
+
+// Notify sends the message as an email to msg.To.
+func (n *SMTPNotifier) Notify(ctx context.Context, msg Message) error {
+	var body strings.Builder
+	body.WriteString(msg.Body)
+	if len(msg.Reminders) > 0 {
+		body.WriteString("\r\n\r\n")
+		for _, reminder := range msg.Reminders {
+			name := reminder.PlantSpeciesName
+			if reminder.PlantName != "" {
+				name = fmt.Sprintf("%s (%s)", reminder.PlantName, reminder.PlantSpeciesName)
+			}
+			fmt.Fprintf(&body, "- %s: %s, %s\r\n", name, reminder.Status, reminder.DueAt.Format("Mon 2 Jan 2006"))
+		}
+	}
+
+	return n.Send(ctx, Mail{To: msg.To, Subject: msg.Subject, Body: body.String()})
+}
+
+// Send sends the mail to mail.To, giving up once ctx is done or after smtpTimeout.
+func (n *SMTPNotifier) Send(ctx context.Context, mail Mail) error {
+	if mail.To == "" {
+		return errors.New("no email address to send to")
+	}
+	if strings.ContainsAny(mail.To, "\r\n") || strings.ContainsAny(mail.Subject, "\r\n") {
+		return errors.New("email headers cannot contain line breaks")
+	}
+
+	var data bytes.Buffer
+	fmt.Fprintf(&data, "From: %s\r\n", n.from)
+	fmt.Fprintf(&data, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&data, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&data, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	data.WriteString("MIME-Version: 1.0\r\n")
+	data.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	data.WriteString("\r\n")
+	data.WriteString(mail.Body)
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+	return n.send(ctx, mail.To, data.Bytes())
+}
+
+// send is the exchange of smtp.SendMail, on a connection that stops waiting for the server
+// at the deadline of ctx, or as soon as ctx is cancelled
+func (n *SMTPNotifier) send(ctx context.Context, to string, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		err = conn.SetDeadline(deadline)
+		if err != nil {
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: n.host})
+		if err != nil {
+			return err
+		}
+	}
+	if n.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support authentication")
+		}
+		err = client.Auth(n.auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(n.from)
+	if err != nil {
+		return err
+	}
+	err = client.Rcpt(to)
+	if err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(msg)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// === Webhook Notifier ===
+
+// ErrPrivateAddress is returned for webhooks that resolve to an address that is not on the public internet,
+// so users cannot make the server send requests into its own network.
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// WebhookNotifier delivers messages as a JSON POST request to msg.To.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a notifier that gives up on a webhook after timeout.
+// It only connects to public addresses, including after redirects.
+func NewWebhookNotifier(timeout time.Duration) *WebhookNotifier {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// checked on the address that is dialed, as dns can change after the url was validated
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !publicIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	return &WebhookNotifier{
+		client: &http.Client{
+			Timeout: timeout,
+			// no proxy, the dialer would only check the address of the proxy
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+			},
+		},
+	}
+}
+
+// ValidateWebhookURL checks that target is an http(s) url with a host that only resolves to public addresses.
+func ValidateWebhookURL(ctx context.Context, target string) error {
+	webhookURL, err := url.Parse(target)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Hostname() == "" {
+		return errors.New("invalid webhook url")
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, webhookURL.Hostname())
+	if err != nil {
+		return fmt.Errorf("could not resolve webhook host: %w", err)
+	}
+	for _, address := range addresses {
+		if !publicIP(address.IP) {
+			return ErrPrivateAddress
+		}
+	}
+
+	return nil
+}
+
+// returns false for loopback, private, link-local, multicast, and unspecified addresses
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	// carrier grade nat, shared address space that is not reachable from the internet
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return false
+	}
+
+	return true
+}
+
+// Notify posts the message as JSON to the webhook url in msg.To.
+// Any response other than 2xx is treated as a failure.
+func (n *WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return errors.New("no webhook url to send to")
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.To, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", "plantae-notifier")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
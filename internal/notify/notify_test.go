@@ -0,0 +1,319 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts mail on a local port and keeps the data of every message
+type fakeSMTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+	conns    int
+	wg       sync.WaitGroup
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &fakeSMTPServer{listener: listener}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+func (s *fakeSMTPServer) notifier(t *testing.T) *SMTPNotifier {
+	t.Helper()
+	host, port, err := net.SplitHostPort(s.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier, err := NewSMTPNotifier(host, port, "", "", "plantae@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notifier
+}
+
+func (s *fakeSMTPServer) received() (int, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns, append([]string(nil), s.messages...)
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	s.mu.Lock()
+	s.conns++
+	s.mu.Unlock()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case command == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 ok")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSMTPSend(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	err := server.notifier(t).Send(context.Background(), Mail{
+		To:      "craig@example.com",
+		Subject: "Reset your Plantae password",
+		Body:    "token\r\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	headers, body, _ := strings.Cut(messages[0], "\r\n\r\n")
+	for _, header := range []string{
+		"From: plantae@example.com",
+		"To: craig@example.com",
+		"Subject: Reset your Plantae password",
+		"Content-Type: text/plain; charset=utf-8",
+	} {
+		if !strings.Contains(headers+"\r\n", header+"\r\n") {
+			t.Errorf("headers are missing %q:\n%s", header, headers)
+		}
+	}
+	if body != "token\r\n" {
+		t.Errorf("body = %q, want %q", body, "token\r\n")
+	}
+}
+
+func TestSMTPNotifyListsReminders(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	err := server.notifier(t).Notify(context.Background(), Message{
+		To:      "craig@example.com",
+		Subject: "2 of your plants need care",
+		Body:    "The following plants are due.",
+		Reminders: []Reminder{
+			{PlantName: "Fernando", PlantSpeciesName: "Boston fern", Status: "due", DueAt: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+			{PlantSpeciesName: "Snake plant", Status: "overdue", DueAt: time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	for _, line := range []string{
+		"- Fernando (Boston fern): due, Tue 1 Jul 2025\r\n",
+		"- Snake plant: overdue, Sat 28 Jun 2025\r\n",
+	} {
+		if !strings.Contains(messages[0], line) {
+			t.Errorf("message is missing %q:\n%s", line, messages[0])
+		}
+	}
+}
+
+func TestSMTPRejectsHeaderInjection(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	notifier := server.notifier(t)
+
+	mails := []Mail{
+		{To: "craig@example.com\r\nBcc: everyone@example.com", Subject: "Hello"},
+		{To: "craig@example.com", Subject: "Hello\r\nBcc: everyone@example.com"},
+		{To: "craig@example.com", Subject: "Hello\nBcc: everyone@example.com"},
+		{To: "", Subject: "Hello"},
+	}
+	for _, mail := range mails {
+		err := notifier.Send(context.Background(), mail)
+		if err == nil {
+			t.Errorf("Send to %q with subject %q returned no error", mail.To, mail.Subject)
+		}
+	}
+
+	// nothing reaches the server
+	conns, messages := server.received()
+	if conns != 0 || len(messages) != 0 {
+		t.Errorf("server had %d connections and %d messages, want none", conns, len(messages))
+	}
+}
+
+func TestSMTPSendGivesUpOnSilentServer(t *testing.T) {
+	// accepts connections but never sends the greeting
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	var conns []net.Conn
+	var mu sync.Mutex
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	notifier, err := NewSMTPNotifier(host, port, "", "", "plantae@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- notifier.Send(ctx, Mail{To: "craig@example.com", Subject: "Hello"})
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Send to a server that never answers returned no error")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Send did not return after the deadline of its context")
+	}
+}
+
+func TestPublicIP(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"100.64.0.1", false},
+		{"100.127.255.255", false},
+		{"100.128.0.1", true},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, test := range tests {
+		got := publicIP(net.ParseIP(test.ip))
+		if got != test.public {
+			t.Errorf("publicIP(%s) = %v, want %v", test.ip, got, test.public)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		target  string
+		private bool
+	}{
+		{"http://127.0.0.1:8080/hook", true},
+		{"https://[::1]/hook", true},
+		{"http://169.254.169.254/latest/meta-data", true},
+		{"http://localhost/hook", true},
+	}
+
+	for _, test := range tests {
+		err := ValidateWebhookURL(context.Background(), test.target)
+		if !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want %v", test.target, err, ErrPrivateAddress)
+		}
+	}
+
+	for _, target := range []string{"not a url", "ftp://example.com/hook", "http:///hook"} {
+		err := ValidateWebhookURL(context.Background(), target)
+		if err == nil {
+			t.Errorf("ValidateWebhookURL(%q) = nil, want error", target)
+		}
+	}
+}
+
+func TestWebhookNotifierRefusesPrivateAddress(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	notifier := NewWebhookNotifier(time.Second)
+	err = notifier.Notify(context.Background(), Message{To: "http://" + listener.Addr().String() + "/hook"})
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("Notify to loopback = %v, want %v", err, ErrPrivateAddress)
+	}
+}
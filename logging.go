@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/logfile"
+)
+
+// requestIDHeader carries the request id in from a proxy, and back out to the client
+const requestIDHeader = "X-Request-ID"
+
+// longest request id accepted from a client, longer ones are replaced
+const requestIDMaxLength = 128
+
+// Log formats for 'LOG_FORMAT'
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// Log outputs for 'LOG_OUTPUT'
+const (
+	logOutputBoth   = "both"
+	logOutputStdout = "stdout"
+	logOutputFile   = "file"
+)
+
+// defaults of the log file settings, when they are not set in '.env'
+const (
+	logFileDefaultPath       = "log/server.log"
+	logFileDefaultMaxSizeMB  = 100
+	logFileDefaultInterval   = time.Hour * 24
+	logFileDefaultMaxBackups = 7
+)
+
+// === Request Info ===
+
+type requestInfoKey struct{}
+
+// requestInfo identifies a request in every log line written while handling it
+type requestInfo struct {
+	id     string
+	userID uuid.UUID
+}
+
+func withRequestInfo(ctx context.Context, info *requestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func requestInfoFromContext(ctx context.Context) (*requestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(*requestInfo)
+	return info, ok
+}
+
+// records the authenticated user of the request, so it is included in later log lines
+func setRequestUserID(ctx context.Context, userID uuid.UUID) {
+	if info, ok := requestInfoFromContext(ctx); ok {
+		info.userID = userID
+	}
+}
+
+// returns the request id from the client when it is safe to log, otherwise a new one
+func requestIDFromHeader(header string) string {
+	if header == "" || len(header) > requestIDMaxLength {
+		return uuid.NewString()
+	}
+	for _, c := range header {
+		isAlphanumeric := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlphanumeric && c != '-' && c != '_' && c != '.' && c != ':' {
+			return uuid.NewString()
+		}
+	}
+	return header
+}
+
+// === Log Handler ===
+
+// contextLogHandler adds the request id and user id to records logged with a request context,
+// such as cfg.sl.DebugContext(r.Context(), ...)
+type contextLogHandler struct {
+	slog.Handler
+}
+
+func (h contextLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if info, ok := requestInfoFromContext(ctx); ok {
+		record.AddAttrs(slog.String("request id", info.id))
+		if info.userID != uuid.Nil {
+			record.AddAttrs(slog.String("user id", info.userID.String()))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextLogHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextLogHandler) WithGroup(name string) slog.Handler {
+	return contextLogHandler{h.Handler.WithGroup(name)}
+}
+
+// opens where logs are written, stdout and or a rotating log file, configured by
+// 'LOG_OUTPUT', 'LOG_FILE', 'LOG_MAX_SIZE_MB', 'LOG_ROTATE_INTERVAL', 'LOG_MAX_BACKUPS', 'LOG_MAX_AGE', and 'LOG_COMPRESS'.
+// the returned function closes the log file.
+func openLogOutput() (io.Writer, func() error, error) {
+	output := os.Getenv("LOG_OUTPUT")
+	switch output {
+	case "":
+		output = logOutputBoth
+	case logOutputBoth, logOutputStdout, logOutputFile:
+	default:
+		return nil, nil, fmt.Errorf("'LOG_OUTPUT' must be either '%s', '%s', or '%s'", logOutputBoth, logOutputStdout, logOutputFile)
+	}
+
+	if output == logOutputStdout {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	path := os.Getenv("LOG_FILE")
+	if path == "" {
+		path = logFileDefaultPath
+	}
+
+	opts := logfile.Options{
+		MaxSize:    logFileDefaultMaxSizeMB << 20,
+		Interval:   logFileDefaultInterval,
+		MaxBackups: logFileDefaultMaxBackups,
+		Compress:   true,
+	}
+	if maxSizeStr := os.Getenv("LOG_MAX_SIZE_MB"); maxSizeStr != "" {
+		maxSize, err := strconv.ParseInt(maxSizeStr, 10, 64)
+		if err != nil || maxSize < 0 {
+			return nil, nil, errors.New("'LOG_MAX_SIZE_MB' is not a positive number, use '0' to not rotate by size")
+		}
+		opts.MaxSize = maxSize << 20
+	}
+	if intervalStr := os.Getenv("LOG_ROTATE_INTERVAL"); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil || interval < 0 {
+			return nil, nil, errors.New("'LOG_ROTATE_INTERVAL' is not a valid duration, use '0' to not rotate by time")
+		}
+		opts.Interval = interval
+	}
+	if maxBackupsStr := os.Getenv("LOG_MAX_BACKUPS"); maxBackupsStr != "" {
+		maxBackups, err := strconv.Atoi(maxBackupsStr)
+		if err != nil || maxBackups < 0 {
+			return nil, nil, errors.New("'LOG_MAX_BACKUPS' is not a positive number, use '0' to keep every rotated file")
+		}
+		opts.MaxBackups = maxBackups
+	}
+	if maxAgeStr := os.Getenv("LOG_MAX_AGE"); maxAgeStr != "" {
+		maxAge, err := time.ParseDuration(maxAgeStr)
+		if err != nil || maxAge < 0 {
+			return nil, nil, errors.New("'LOG_MAX_AGE' is not a valid duration, use '0' to keep rotated files regardless of age")
+		}
+		opts.MaxAge = maxAge
+	}
+	if compressStr := os.Getenv("LOG_COMPRESS"); compressStr != "" {
+		compress, err := strconv.ParseBool(compressStr)
+		if err != nil {
+			return nil, nil, errors.New("'LOG_COMPRESS' is not 'true' or 'false'")
+		}
+		opts.Compress = compress
+	}
+
+	logFile, err := logfile.New(path, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open log file %q: %w", path, err)
+	}
+
+	if output == logOutputFile {
+		return logFile, logFile.Close, nil
+	}
+	return io.MultiWriter(os.Stdout, logFile), logFile.Close, nil
+}
+
+// builds the logger for 'LOG_LEVEL' and 'LOG_FORMAT', both may be empty for the defaults
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	if level != "" {
+		var logLevel slog.Level
+		err := logLevel.UnmarshalText([]byte(level))
+		if err != nil {
+			return nil, fmt.Errorf("'LOG_LEVEL' must be either 'debug', 'info', 'warn', or 'error': %w", err)
+		}
+		loggingLevel.Set(logLevel)
+	}
+
+	opts := slog.HandlerOptions{Level: loggingLevel}
+	var handler slog.Handler
+	switch format {
+	case "", logFormatText:
+		handler = slog.NewTextHandler(w, &opts)
+	case logFormatJSON:
+		handler = slog.NewJSONHandler(w, &opts)
+	default:
+		return nil, fmt.Errorf("'LOG_FORMAT' must be either '%s' or '%s'", logFormatText, logFormatJSON)
+	}
+
+	return slog.New(contextLogHandler{handler}), nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicholasss/plantae/internal/notify"
+)
+
+const (
+	// account emails waiting to be sent, more are dropped until the queue has room
+	mailQueueSize = 100
+	// how long sending a single queued email may take
+	mailSendTimeout = time.Second * 30
+)
+
+// === Mail Queue ===
+
+// mailQueue sends account emails in the background, so a request does not wait for the mail server,
+// and how long a request takes does not tell whether an email was sent.
+type mailQueue struct {
+	cfg   *apiConfig
+	mails chan notify.Mail
+}
+
+func newMailQueue(cfg *apiConfig) *mailQueue {
+	return &mailQueue{
+		cfg:   cfg,
+		mails: make(chan notify.Mail, mailQueueSize),
+	}
+}
+
+// enqueue never blocks, the email is dropped if the queue is full
+func (q *mailQueue) enqueue(ctx context.Context, mail notify.Mail) {
+	select {
+	case q.mails <- mail:
+	default:
+		q.cfg.sl.WarnContext(ctx, "Mail queue is full, dropping email", "subject", mail.Subject)
+	}
+}
+
+// run blocks, sending queued emails until the context is cancelled.
+// the emails still queued by then are sent before it returns
+func (q *mailQueue) run(ctx context.Context) {
+	q.cfg.sl.InfoContext(ctx, "Mail queue started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			q.drain(context.WithoutCancel(ctx))
+			q.cfg.sl.InfoContext(ctx, "Mail queue stopped")
+			return
+		case mail := <-q.mails:
+			q.send(ctx, mail)
+		}
+	}
+}
+
+func (q *mailQueue) drain(ctx context.Context) {
+	for {
+		select {
+		case mail := <-q.mails:
+			q.send(ctx, mail)
+		default:
+			return
+		}
+	}
+}
+
+func (q *mailQueue) send(ctx context.Context, mail notify.Mail) {
+	ctx, cancel := context.WithTimeout(ctx, mailSendTimeout)
+	defer cancel()
+
+	err := q.cfg.mailer.Send(ctx, mail)
+	if err != nil {
+		q.cfg.sl.WarnContext(ctx, "Could not send queued email", "error", err, "subject", mail.Subject)
+	}
+}
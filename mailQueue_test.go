@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/nicholasss/plantae/internal/notify"
+)
+
+// testMailer records the emails it is asked to send
+type testMailer struct {
+	mu   sync.Mutex
+	sent []notify.Mail
+}
+
+func (m *testMailer) Send(_ context.Context, mail notify.Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+func newTestMailQueue() (*mailQueue, *testMailer) {
+	mailer := &testMailer{}
+	cfg := &apiConfig{
+		sl:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		mailer: mailer,
+	}
+	return newMailQueue(cfg), mailer
+}
+
+func TestMailQueueSendsQueuedMail(t *testing.T) {
+	queue, mailer := newTestMailQueue()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	queue.enqueue(ctx, notify.Mail{To: "a@example.com"})
+	queue.enqueue(ctx, notify.Mail{To: "b@example.com"})
+
+	// emails still queued when the queue is stopped are sent before run returns
+	cancel()
+	queue.run(ctx)
+
+	if len(mailer.sent) != 2 || mailer.sent[0].To != "a@example.com" || mailer.sent[1].To != "b@example.com" {
+		t.Errorf("sent = %v, want both emails in order", mailer.sent)
+	}
+}
+
+func TestMailQueueDropsWhenFull(t *testing.T) {
+	queue, mailer := newTestMailQueue()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// enqueue does not block without anything sending
+	for range mailQueueSize + 1 {
+		queue.enqueue(ctx, notify.Mail{To: "a@example.com"})
+	}
+
+	cancel()
+	queue.run(ctx)
+
+	if len(mailer.sent) != mailQueueSize {
+		t.Errorf("sent %d emails, want %d", len(mailer.sent), mailQueueSize)
+	}
+}
@@ -1,9 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	_ "github.com/lib/pq"
 )
@@ -18,6 +25,16 @@ $$ |      $$ |$$  __$$ |$$ |  $$ | $$ |$$\ $$  __$$ |$$   ____|
 $$ |      $$ |\$$$$$$$ |$$ |  $$ | \$$$$  |\$$$$$$$ |\$$$$$$$\
 \__|      \__| \_______|\__|  \__|  \____/  \_______| \_______|`
 
+// http.Server limits, the read and write timeouts leave room for photo uploads and catalog exports
+const (
+	serverReadHeaderTimeout = time.Second * 10
+	serverReadTimeout       = time.Second * 60
+	serverWriteTimeout      = time.Second * 120
+	serverIdleTimeout       = time.Second * 120
+	serverMaxHeaderBytes    = 1 << 20
+	serverShutdownTimeout   = time.Second * 30
+)
+
 // === Handler Functions ===
 
 func healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -31,103 +48,240 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 func main() {
 	fmt.Printf("%s\n\n", logo)
 
-	cfg, closeLogFile, err := loadAPIConfig()
+	err := runCommand(os.Args[1:])
 	if err != nil {
-		log.Fatalf("Issue loading config: %q", err)
+		log.Fatalf("Issue running command: %q", err)
 	}
-	defer closeLogFile()
+}
+
+// === Server ===
+
+// registers every endpoint and serves them until the server fails,
+// or until SIGINT or SIGTERM, when in-flight requests are drained before returning
+func runServer(cfg *apiConfig) error {
 	cfg.sl.Info("Starting server...")
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	mux := http.NewServeMux()
 
-	// health endpoint
-	mux.Handle("GET /api/v1/health", cfg.logMW(http.HandlerFunc(healthHandler)))
+	// health endpoints
+	mux.Handle("GET /api/v1/health", http.HandlerFunc(healthHandler))
+	mux.Handle("GET /api/v1/health/live", http.HandlerFunc(cfg.healthLiveHandler))
+	mux.Handle("GET /api/v1/health/ready", http.HandlerFunc(cfg.healthReadyHandler))
+
+	// prometheus metrics endpoint, scraped with the super-admin token
+	mux.Handle("GET /metrics", cfg.authSuperAdminMW(cfg.metrics.registry.Handler()))
 
 	// === super-admin endpoints ===
 
-	mux.Handle("POST /api/v1/super-admin/promote-user", cfg.logMW(cfg.authSuperAdminMW(http.HandlerFunc(cfg.promoteUserToAdminHandler))))
-	mux.Handle("POST /api/v1/super-admin/demote-user", cfg.logMW(cfg.authSuperAdminMW(http.HandlerFunc(cfg.demoteUserToAdminHandler))))
+	mux.Handle("POST /api/v1/super-admin/promote-user", cfg.authSuperAdminMW(http.HandlerFunc(cfg.promoteUserToAdminHandler)))
+	mux.Handle("POST /api/v1/super-admin/demote-user", cfg.authSuperAdminMW(http.HandlerFunc(cfg.demoteUserToAdminHandler)))
+
+	// super-admin catalog backup endpoints
+	mux.Handle("GET /api/v1/super-admin/export", cfg.authSuperAdminMW(http.HandlerFunc(cfg.exportCatalogHandler)))
+	mux.Handle("POST /api/v1/super-admin/import", cfg.authSuperAdminMW(http.HandlerFunc(cfg.importCatalogHandler)))
 
 	// reset endpoints utilized for development & testing
 	// requires super-admin token & for platform to be not production.
 
 	// suepr-admin user reset endpoints
-	mux.Handle("POST /api/v1/super-admin/reset-users", cfg.logMW(cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetUsersHandler))))
+	mux.Handle("POST /api/v1/super-admin/reset-users", cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetUsersHandler)))
 
 	// super-admin plant reset endpoints
-	mux.Handle("POST /api/v1/super-admin/reset-plant-species", cfg.logMW(cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetPlantSpeciesHandler))))
-	mux.Handle("POST /api/v1/super-admin/reset-plant-names", cfg.logMW(cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetPlantNamesHandler))))
-	mux.Handle("POST /api/v1/super-admin/reset-plant-types", cfg.logMW(cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetPlantTypesHandler))))
-	mux.Handle("POST /api/v1/super-admin/reset-light", cfg.logMW(cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetLightNeedsHandler))))
-	mux.Handle("POST /api/v1/super-admin/reset-water", cfg.logMW(cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetWaterNeedsHandler))))
+	mux.Handle("POST /api/v1/super-admin/reset-plant-species", cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetPlantSpeciesHandler)))
+	mux.Handle("POST /api/v1/super-admin/reset-plant-names", cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetPlantNamesHandler)))
+	mux.Handle("POST /api/v1/super-admin/reset-plant-types", cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetPlantTypesHandler)))
+	mux.Handle("POST /api/v1/super-admin/reset-light", cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetLightNeedsHandler)))
+	mux.Handle("POST /api/v1/super-admin/reset-water", cfg.authSuperAdminMW(http.HandlerFunc(cfg.resetWaterNeedsHandler)))
 
 	// === admin endpoints ===
 
 	// admin plant species endpoints
-	mux.Handle("GET /api/v1/admin/plant-species", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantSpeciesViewHandler))))
-	mux.Handle("POST /api/v1/admin/plant-species", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantSpeciesCreateHandler))))
-	mux.Handle("PUT /api/v1/admin/plant-species/{plantSpeciesID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminReplacePlantSpeciesInfoHandler))))
-	mux.Handle("DELETE /api/v1/admin/plant-species/{plantSpeciesID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminDeletePlantSpeciesHandler))))
+	mux.Handle("GET /api/v1/admin/plant-species", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantSpeciesViewHandler)))
+	mux.Handle("POST /api/v1/admin/plant-species", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantSpeciesCreateHandler)))
+	mux.Handle("POST /api/v1/admin/plant-species/import", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantSpeciesImportHandler)))
+	mux.Handle("PUT /api/v1/admin/plant-species/{plantSpeciesID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminReplacePlantSpeciesInfoHandler)))
+	mux.Handle("DELETE /api/v1/admin/plant-species/{plantSpeciesID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminDeletePlantSpeciesHandler)))
 
 	// admin plant names endpoints
-	mux.Handle("POST /api/v1/admin/plant-names", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantNamesCreateHandler))))
-	mux.Handle("GET /api/v1/admin/plant-names", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantNamesViewHandler))))
+	mux.Handle("POST /api/v1/admin/plant-names", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantNamesCreateHandler)))
+	mux.Handle("GET /api/v1/admin/plant-names", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantNamesViewHandler)))
 	// mux.Handle("PUT /api/v1/admin/plant-names")
-	mux.Handle("DELETE /api/v1/admin/plant-names/{plantNameID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantNamesDeleteHandler))))
+	mux.Handle("DELETE /api/v1/admin/plant-names/{plantNameID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantNamesDeleteHandler)))
 
 	// admin plant type endpoints
-	mux.Handle("POST /api/v1/admin/plant-types", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantTypesCreateHandler))))
-	mux.Handle("GET /api/v1/admin/plant-types", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantTypesViewHandler))))
-	mux.Handle("PUT /api/v1/admin/plant-types/{plantTypeID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantTypesUpdateHandler))))
-	mux.Handle("DELETE /api/v1/admin/plant-types/{plantTypeID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantTypeDeleteHandler))))
+	mux.Handle("POST /api/v1/admin/plant-types", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantTypesCreateHandler)))
+	mux.Handle("GET /api/v1/admin/plant-types", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantTypesViewHandler)))
+	mux.Handle("PUT /api/v1/admin/plant-types/{plantTypeID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantTypesUpdateHandler)))
+	mux.Handle("DELETE /api/v1/admin/plant-types/{plantTypeID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminPlantTypeDeleteHandler)))
 
 	// admin set/unset plant species to plant type
 	// set plant species to plant type
-	mux.Handle("POST /api/v1/admin/plant-types/link/{plantTypeID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminSetPlantAsTypeHandler))))
+	mux.Handle("POST /api/v1/admin/plant-types/link/{plantTypeID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminSetPlantAsTypeHandler)))
 	// unset plant species to lighting need
-	mux.Handle("DELETE /api/v1/admin/plant-types/link/{plantTypeID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminUnsetPlantAsTypeHandler))))
+	mux.Handle("DELETE /api/v1/admin/plant-types/link/{plantTypeID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminUnsetPlantAsTypeHandler)))
 
 	// admin lighting needs endpoints
-	mux.Handle("POST /api/v1/admin/light", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminLightCreateHandler))))
-	mux.Handle("GET /api/v1/admin/light", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminLightViewHandler))))
-	mux.Handle("PUT /api/v1/admin/light/{lightID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminLightUpdateHandler))))
-	mux.Handle("DELETE /api/v1/admin/light/{lightID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminLightDeleteHandler))))
+	mux.Handle("POST /api/v1/admin/light", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminLightCreateHandler)))
+	mux.Handle("GET /api/v1/admin/light", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminLightViewHandler)))
+	mux.Handle("PUT /api/v1/admin/light/{lightID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminLightUpdateHandler)))
+	mux.Handle("DELETE /api/v1/admin/light/{lightID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminLightDeleteHandler)))
 
 	// admin set/unset plant species to lighting need
 	// set plant species to lighting need
-	mux.Handle("POST /api/v1/admin/light/link/{lightID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminSetPlantAsLightNeedHandler))))
+	mux.Handle("POST /api/v1/admin/light/link/{lightID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminSetPlantAsLightNeedHandler)))
 	// unset plant species to lighting need
-	mux.Handle("DELETE /api/v1/admin/light/link/{lightID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminUnsetPlantAsLightNeedHandler))))
+	mux.Handle("DELETE /api/v1/admin/light/link/{lightID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminUnsetPlantAsLightNeedHandler)))
 
 	// admin watering needs endpoints
-	mux.Handle("POST /api/v1/admin/water", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminWaterCreateHandler))))
-	mux.Handle("GET /api/v1/admin/water", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminWaterViewHandler))))
-	mux.Handle("DELETE /api/v1/admin/water/{waterID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminWaterDeleteHandler))))
+	mux.Handle("POST /api/v1/admin/water", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminWaterCreateHandler)))
+	mux.Handle("GET /api/v1/admin/water", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminWaterViewHandler)))
+	mux.Handle("DELETE /api/v1/admin/water/{waterID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminWaterDeleteHandler)))
 
 	// admin set/unset plant species to watering need
 	// set plant species to watering need
-	mux.Handle("POST /api/v1/admin/water/link/{waterID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminSetPlantAsWaterNeedHandler))))
+	mux.Handle("POST /api/v1/admin/water/link/{waterID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminSetPlantAsWaterNeedHandler)))
 	// unset plant species to watering need
-	mux.Handle("DELETE /api/v1/admin/water/link/{waterID}", cfg.logMW(cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminUnsetPlantAsWaterNeedHandler))))
+	mux.Handle("DELETE /api/v1/admin/water/link/{waterID}", cfg.authNormalAdminMW(http.HandlerFunc(cfg.adminUnsetPlantAsWaterNeedHandler)))
 
 	// === user endpoints ===
 
 	// user auth endpoints
-	mux.Handle("POST /api/v1/auth/register", cfg.logMW(http.HandlerFunc(cfg.registerUserHandler)))
-	mux.Handle("POST /api/v1/auth/login", cfg.logMW(http.HandlerFunc(cfg.loginHandler)))
-	mux.Handle("POST /api/v1/auth/refresh", cfg.logMW(http.HandlerFunc(cfg.refreshTokenHandler)))
-	mux.Handle("POST /api/v1/auth/revoke", cfg.logMW(http.HandlerFunc(cfg.revokeRefreshTokenHandler)))
+	mux.Handle("POST /api/v1/auth/register", http.HandlerFunc(cfg.registerUserHandler))
+	mux.Handle("POST /api/v1/auth/login", http.HandlerFunc(cfg.loginHandler))
+	mux.Handle("POST /api/v1/auth/refresh", http.HandlerFunc(cfg.refreshTokenHandler))
+	mux.Handle("POST /api/v1/auth/revoke", http.HandlerFunc(cfg.revokeRefreshTokenHandler))
+	mux.Handle("POST /api/v1/auth/forgot-password", http.HandlerFunc(cfg.forgotPasswordHandler))
+	mux.Handle("POST /api/v1/auth/reset-password", http.HandlerFunc(cfg.resetPasswordHandler))
+	mux.Handle("POST /api/v1/auth/verify-email", http.HandlerFunc(cfg.verifyEmailHandler))
 
 	// === user data endpoints
-	mux.Handle("GET /api/v1/my/plants", cfg.logMW(http.HandlerFunc(cfg.usersPlantsListHandler)))
-	mux.Handle("POST /api/v1/my/plants", cfg.logMW(http.HandlerFunc(cfg.usersPlantsCreateHandler)))
-	mux.Handle("PUT /api/v1/my/plants/{plantID}", cfg.logMW(http.HandlerFunc(cfg.userPlantsUpdateHandler)))
-	mux.Handle("DELETE /api/v1/my/plants/{plantID}", cfg.logMW(http.HandlerFunc(cfg.userPlantsDeleteHandler)))
+	mux.Handle("GET /api/v1/my/plants", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantsListHandler)))
+	mux.Handle("POST /api/v1/my/plants", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantsCreateHandler)))
+	mux.Handle("GET /api/v1/my/plants/{plantID}", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantsViewHandler)))
+	mux.Handle("PUT /api/v1/my/plants/{plantID}", cfg.authUserMW(http.HandlerFunc(cfg.userPlantsUpdateHandler)))
+	mux.Handle("DELETE /api/v1/my/plants/{plantID}", cfg.authUserMW(http.HandlerFunc(cfg.userPlantsDeleteHandler)))
+	mux.Handle("PUT /api/v1/my/plants/{plantID}/care", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantsCareUpdateHandler)))
+	mux.Handle("PUT /api/v1/my/plants/{plantID}/location", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantsLocationUpdateHandler)))
+
+	// user plant care event endpoints
+	mux.Handle("GET /api/v1/my/plants/{plantID}/events", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantEventsListHandler)))
+	mux.Handle("POST /api/v1/my/plants/{plantID}/events", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantEventsCreateHandler)))
+	mux.Handle("PUT /api/v1/my/plants/{plantID}/events/{eventID}", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantEventsUpdateHandler)))
+	mux.Handle("DELETE /api/v1/my/plants/{plantID}/events/{eventID}", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantEventsDeleteHandler)))
+
+	// user plant photo endpoints
+	mux.Handle("GET /api/v1/my/plants/{plantID}/photos", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantPhotosListHandler)))
+	mux.Handle("POST /api/v1/my/plants/{plantID}/photos", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantPhotosCreateHandler)))
+	mux.Handle("GET /api/v1/my/plants/{plantID}/photos/{photoID}", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantPhotosViewHandler)))
+	mux.Handle("GET /api/v1/my/plants/{plantID}/photos/{photoID}/thumbnail", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantPhotosThumbnailHandler)))
+	mux.Handle("DELETE /api/v1/my/plants/{plantID}/photos/{photoID}", cfg.authUserMW(http.HandlerFunc(cfg.usersPlantPhotosDeleteHandler)))
+
+	// user location endpoints
+	mux.Handle("GET /api/v1/my/locations", cfg.authUserMW(http.HandlerFunc(cfg.usersLocationsListHandler)))
+	mux.Handle("POST /api/v1/my/locations", cfg.authUserMW(http.HandlerFunc(cfg.usersLocationsCreateHandler)))
+	mux.Handle("PUT /api/v1/my/locations/{locationID}", cfg.authUserMW(http.HandlerFunc(cfg.usersLocationsUpdateHandler)))
+	mux.Handle("DELETE /api/v1/my/locations/{locationID}", cfg.authUserMW(http.HandlerFunc(cfg.usersLocationsDeleteHandler)))
+
+	// user watering schedule endpoint
+	mux.Handle("GET /api/v1/my/schedule", cfg.authUserMW(http.HandlerFunc(cfg.usersScheduleHandler)))
+
+	// user notification endpoints
+	mux.Handle("GET /api/v1/my/notifications", cfg.authUserMW(http.HandlerFunc(cfg.usersNotificationsViewHandler)))
+	mux.Handle("PUT /api/v1/my/notifications", cfg.authUserMW(http.HandlerFunc(cfg.usersNotificationsUpdateHandler)))
+	mux.Handle("POST /api/v1/my/notifications/channels", cfg.authUserMW(http.HandlerFunc(cfg.usersNotificationChannelsCreateHandler)))
+	mux.Handle("PUT /api/v1/my/notifications/channels/{channelID}", cfg.authUserMW(http.HandlerFunc(cfg.usersNotificationChannelsUpdateHandler)))
+	mux.Handle("DELETE /api/v1/my/notifications/channels/{channelID}", cfg.authUserMW(http.HandlerFunc(cfg.usersNotificationChannelsDeleteHandler)))
+
+	// user password and email endpoints
+	mux.Handle("POST /api/v1/my/password", cfg.authUserMW(http.HandlerFunc(cfg.usersPasswordChangeHandler)))
+	mux.Handle("POST /api/v1/my/email/verification", cfg.authUserMW(http.HandlerFunc(cfg.usersEmailVerificationRequestHandler)))
+
+	// user session endpoints
+	mux.Handle("GET /api/v1/my/sessions", cfg.authUserMW(http.HandlerFunc(cfg.usersSessionsListHandler)))
+	mux.Handle("POST /api/v1/my/sessions/revoke-others", cfg.authUserMW(http.HandlerFunc(cfg.usersSessionsRevokeOthersHandler)))
+	mux.Handle("DELETE /api/v1/my/sessions/{sessionID}", cfg.authUserMW(http.HandlerFunc(cfg.usersSessionsDeleteHandler)))
 
-	// listing all plants on the server
-	mux.Handle("GET /api/v1/plants", cfg.logMW(http.HandlerFunc(cfg.usersViewPlantsListHandler)))
+	// listing and searching all plants on the server
+	mux.Handle("GET /api/v1/plants", cfg.authUserMW(http.HandlerFunc(cfg.usersViewPlantsListHandler)))
+	mux.Handle("GET /api/v1/plants/search", cfg.authUserMW(http.HandlerFunc(cfg.usersViewPlantsSearchHandler)))
+
+	// background workers stop when ctx is cancelled
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		// background plant care reminders
+		newReminderScheduler(cfg).run(ctx)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		// background removal of expired refresh tokens
+		newRefreshTokenPurger(cfg).run(ctx)
+	}()
+	// the mail queue stops after the server, so emails of draining requests are still sent
+	mailCtx, stopMail := context.WithCancel(context.Background())
+	defer stopMail()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		cfg.mails.run(mailCtx)
+	}()
+
+	server := &http.Server{
+		Addr:              cfg.port,
+		Handler:           cfg.accessLogMW(cfg.metricsMW(mux)),
+		ReadHeaderTimeout: serverReadHeaderTimeout,
+		ReadTimeout:       serverReadTimeout,
+		WriteTimeout:      serverWriteTimeout,
+		IdleTimeout:       serverIdleTimeout,
+		MaxHeaderBytes:    serverMaxHeaderBytes,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
 
 	serverAddress := fmt.Sprintf("http://%s%s", cfg.localAddr, cfg.port)
 	cfg.sl.Info("Server is now online", "address", serverAddress)
-	log.Fatal(http.ListenAndServe(cfg.port, mux))
+
+	var err error
+	select {
+	case err = <-serverErr:
+		// the server failed on its own, the workers are still stopped before returning
+		stop()
+	case <-ctx.Done():
+		cfg.sl.Info("Shutting down server...", "timeout", serverShutdownTimeout)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+
+	shutdownErr := server.Shutdown(shutdownCtx)
+	if shutdownErr != nil {
+		cfg.sl.Warn("Server did not drain connections before the deadline", "error", shutdownErr)
+	}
+	stopMail()
+
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		cfg.sl.Warn("Background workers did not stop before the deadline")
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	cfg.sl.Info("Server is now offline")
+	return nil
 }
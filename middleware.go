@@ -1,12 +1,44 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/nicholasss/plantae/internal/auth"
 )
 
+// === Request Context ===
+
+type authUserKey struct{}
+
+// authUser is the user authenticated by authUserMW, and the session of their access token
+type authUser struct {
+	userID    uuid.UUID
+	sessionID uuid.UUID
+}
+
+func withAuthUser(ctx context.Context, user authUser) context.Context {
+	return context.WithValue(ctx, authUserKey{}, user)
+}
+
+// returns the user authenticated by authUserMW, or uuid.Nil outside of it
+func userIDFromContext(ctx context.Context) uuid.UUID {
+	user, _ := ctx.Value(authUserKey{}).(authUser)
+	return user.userID
+}
+
+// returns the session of the access token authenticated by authUserMW, or uuid.Nil outside of it
+func sessionIDFromContext(ctx context.Context) uuid.UUID {
+	user, _ := ctx.Value(authUserKey{}).(authUser)
+	return user.sessionID
+}
+
 // === Middleware Functions ===
 
 // auth super admin middleware
@@ -14,53 +46,183 @@ func (cfg *apiConfig) authSuperAdminMW(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestToken, err := auth.GetSuperAdminToken(r.Header, cfg.sl)
 		if err != nil {
-			cfg.sl.Debug("Unable to get superadmin token from headers", "error", err)
+			cfg.sl.DebugContext(r.Context(), "Unable to get superadmin token from headers", "error", err)
 			respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 			return
 		}
 
 		if ok := auth.ValidateSuperAdmin(cfg.superAdminToken, requestToken, cfg.sl); !ok {
-			cfg.sl.Debug("Unable to validate superadmin token in request")
+			cfg.sl.DebugContext(r.Context(), "Unable to validate superadmin token in request")
 			respondWithError(err, http.StatusForbidden, w, cfg.sl)
 			return
 		}
 
-		cfg.sl.Debug("Authenticated Super Admin successfully")
+		cfg.sl.DebugContext(r.Context(), "Authenticated Super Admin successfully")
 		next.ServeHTTP(w, r)
 	})
 }
 
+// auth normal user middleware
+// validates the access token once and stores the user and session in the request context,
+// handlers behind it read it with userIDFromContext
+func (cfg *apiConfig) authUserMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestUserID, requestSessionID, err := cfg.getSessionFromToken(r)
+		if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Could not authenticate user in request", "error", err)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			respondWithError(err, http.StatusUnauthorized, w, cfg.sl)
+			return
+		}
+
+		err = cfg.checkSessionActive(r.Context(), requestUserID, requestSessionID)
+		if errors.Is(err, errSessionRevoked) {
+			cfg.sl.DebugContext(r.Context(), "Access token is for a revoked session", "user id", requestUserID, "session id", requestSessionID)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			respondWithError(err, http.StatusUnauthorized, w, cfg.sl)
+			return
+		} else if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Could not check session of access token", "error", err)
+			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+			return
+		}
+
+		setRequestUserID(r.Context(), requestUserID)
+		user := authUser{userID: requestUserID, sessionID: requestSessionID}
+		next.ServeHTTP(w, r.WithContext(withAuthUser(r.Context(), user)))
+	})
+}
+
 // auth normal admin middleware
 func (cfg *apiConfig) authNormalAdminMW(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestUserID, err := cfg.getUserIDFromToken(r)
+		requestUserID, requestSessionID, err := cfg.getSessionFromToken(r)
 		if err != nil {
-			cfg.sl.Debug("Could not authorize user in request", "error", err)
+			cfg.sl.DebugContext(r.Context(), "Could not authorize user in request", "error", err)
 			respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 			return
 		}
 
+		err = cfg.checkSessionActive(r.Context(), requestUserID, requestSessionID)
+		if errors.Is(err, errSessionRevoked) {
+			cfg.sl.DebugContext(r.Context(), "Access token is for a revoked session", "user id", requestUserID, "session id", requestSessionID)
+			respondWithError(err, http.StatusUnauthorized, w, cfg.sl)
+			return
+		} else if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Could not check session of access token", "error", err)
+			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+			return
+		}
+
+		setRequestUserID(r.Context(), requestUserID)
+
 		userRecord, err := cfg.db.GetUserByIDWithoutPassword(r.Context(), requestUserID)
 		if err != nil {
-			cfg.sl.Debug("Could not find user record", "user id", requestUserID, "error", err)
+			cfg.sl.DebugContext(r.Context(), "Could not find user record", "user id", requestUserID, "error", err)
 			respondWithError(errors.New("either cannot find user record or user does not exist"), http.StatusInternalServerError, w, cfg.sl)
 			return
 		}
 
 		if !userRecord.IsAdmin {
-			cfg.sl.Debug("Non-Admin is performing requests to admin endpoints", "email", userRecord.Email, "id", requestUserID)
+			cfg.sl.DebugContext(r.Context(), "Non-Admin is performing requests to admin endpoints", "email", userRecord.Email, "id", requestUserID)
 			respondWithError(errors.New("unauthorized user performing request"), http.StatusUnauthorized, w, cfg.sl)
 			return
 		}
 
-		cfg.sl.Debug("Authenticated normal admin successfully")
+		cfg.sl.DebugContext(r.Context(), "Authenticated normal admin successfully")
 		next.ServeHTTP(w, r)
 	})
 }
 
-func (cfg *apiConfig) logMW(next http.Handler) http.Handler {
+// assigns every request an id and logs one line once it is handled.
+// it wraps the whole mux, so the line includes the route pattern the request matched.
+func (cfg *apiConfig) accessLogMW(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cfg.sl.Debug("Incoming request", "method", r.Method, "path", r.URL.Path, "queries", r.URL.RawQuery)
-		next.ServeHTTP(w, r)
+		start := time.Now()
+
+		info := &requestInfo{id: requestIDFromHeader(r.Header.Get(requestIDHeader))}
+		w.Header().Set(requestIDHeader, info.id)
+
+		// the user id is filled in by the auth middleware once it authenticates the request
+		r = r.WithContext(withRequestInfo(r.Context(), info))
+		recorder := newStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelWarn
+		}
+		cfg.sl.Log(r.Context(), level, "Request handled",
+			"method", r.Method,
+			"route", r.Pattern,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"bytes", recorder.bytes,
+			"duration", time.Since(start),
+		)
+	})
+}
+
+// records the request count, status code, and latency of every request by its route pattern.
+// it wraps the whole mux, the pattern is only known once the mux has matched the request.
+func (cfg *apiConfig) metricsMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		cfg.metrics.requestsInFlight.Add(1)
+		defer cfg.metrics.requestsInFlight.Add(-1)
+
+		recorder := newStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+
+		// patterns keep ids out of the labels, unmatched paths share one label
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		} else if _, path, found := strings.Cut(route, " "); found {
+			route = path
+		}
+
+		method := metricsMethod(r.Method)
+		cfg.metrics.requests.Inc(method, route, strconv.Itoa(recorder.status))
+		cfg.metrics.requestDuration.Observe(time.Since(start).Seconds(), method, route)
 	})
 }
+
+// returns the method as a metrics label, any method clients make up shares the label 'other'
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return method
+	}
+	return "other"
+}
+
+// === Response Recorder ===
+
+// statusRecorder captures the status code and body size written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// List endpoints can be sorted by either of these keys, always newest first.
+const (
+	pageSortCreated = "created"
+	pageSortUpdated = "updated"
+)
+
+const (
+	pageDefaultLimit = 50
+	pageMaxLimit     = 200
+)
+
+// === request response types ===
+
+// PageResponse is the envelope of every paginated list response.
+// NextCursor is null on the last page.
+type PageResponse[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+// pageRequest holds the 'limit', 'cursor', and 'sort' query parameters of a list request
+type pageRequest struct {
+	sort       string
+	limit      int32
+	cursorTime sql.NullTime
+	cursorID   uuid.NullUUID
+}
+
+// === pagination functions ===
+
+// parses the pagination query parameters, using defaultSort when 'sort' is not provided
+func parsePageRequest(query url.Values, defaultSort string) (pageRequest, error) {
+	page := pageRequest{
+		sort:  defaultSort,
+		limit: pageDefaultLimit,
+	}
+
+	if sortStr := query.Get("sort"); sortStr != "" {
+		if sortStr != pageSortCreated && sortStr != pageSortUpdated {
+			return pageRequest{}, errors.New("sort must be either 'created' or 'updated'")
+		}
+		page.sort = sortStr
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > pageMaxLimit {
+			return pageRequest{}, errors.New("limit must be a number from 1 to 200")
+		}
+		page.limit = int32(limit)
+	}
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursorSort, cursorTime, cursorID, err := decodeCursor(cursorStr)
+		if err != nil {
+			return pageRequest{}, err
+		}
+		// a cursor only points into the ordering it came from
+		if cursorSort != page.sort {
+			return pageRequest{}, errors.New("cursor was created for a different sort")
+		}
+		page.cursorTime = sql.NullTime{Time: cursorTime, Valid: true}
+		page.cursorID = uuid.NullUUID{UUID: cursorID, Valid: true}
+	}
+
+	return page, nil
+}
+
+// the number of rows to query, one more than the limit to tell if there is a next page
+func (page pageRequest) rowLimit() int32 {
+	return page.limit + 1
+}
+
+// trims the extra row queried by rowLimit, and creates the cursor for the next page if there is one
+// key returns the created and updated times, and the id of a record
+func nextPage[T any](records []T, page pageRequest, key func(T) (time.Time, time.Time, uuid.UUID)) ([]T, *string) {
+	if len(records) <= int(page.limit) {
+		return records, nil
+	}
+
+	records = records[:page.limit]
+	createdAt, updatedAt, id := key(records[len(records)-1])
+
+	cursorTime := createdAt
+	if page.sort == pageSortUpdated {
+		cursorTime = updatedAt
+	}
+	cursor := encodeCursor(page.sort, cursorTime, id)
+	return records, &cursor
+}
+
+// cursors are opaque to clients, they hold the sort key, the time, and the id of the last record of a page
+func encodeCursor(sort string, cursorTime time.Time, id uuid.UUID) string {
+	raw := sort + "|" + cursorTime.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (string, time.Time, uuid.UUID, error) {
+	invalidErr := errors.New("invalid cursor")
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", time.Time{}, uuid.UUID{}, invalidErr
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return "", time.Time{}, uuid.UUID{}, invalidErr
+	}
+
+	cursorTime, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return "", time.Time{}, uuid.UUID{}, invalidErr
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return "", time.Time{}, uuid.UUID{}, invalidErr
+	}
+
+	return parts[0], cursorTime, id, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// === Refresh Token Purger ===
+
+// refreshTokenPurger periodically deletes expired refresh tokens, and revoked tokens that were not replaced.
+// replaced tokens are kept until they expire, so a replaced token that is used again
+// is still recognized and its family revoked.
+type refreshTokenPurger struct {
+	cfg      *apiConfig
+	interval time.Duration
+}
+
+func newRefreshTokenPurger(cfg *apiConfig) *refreshTokenPurger {
+	return &refreshTokenPurger{
+		cfg:      cfg,
+		interval: cfg.refreshTokenPurgeInterval,
+	}
+}
+
+// run blocks, purging every interval until the context is cancelled
+func (p *refreshTokenPurger) run(ctx context.Context) {
+	if p.interval <= 0 {
+		p.cfg.sl.InfoContext(ctx, "Refresh token purger is disabled")
+		return
+	}
+
+	p.cfg.sl.InfoContext(ctx, "Refresh token purger started", "interval", p.interval)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.cfg.sl.InfoContext(ctx, "Refresh token purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *refreshTokenPurger) purge(ctx context.Context) {
+	rowsDeleted, err := p.cfg.db.PurgeExpiredRefreshTokens(ctx)
+	if err != nil {
+		p.cfg.sl.WarnContext(ctx, "Refresh token purger could not delete tokens", "error", err)
+		return
+	}
+
+	if rowsDeleted > 0 {
+		p.cfg.sl.InfoContext(ctx, "Refresh token purger deleted tokens", "count", rowsDeleted)
+	}
+}
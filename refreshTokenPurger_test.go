@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// testPurgeDB records the statements it is asked to execute
+type testPurgeDB struct {
+	database.DBTX
+	queries []string
+}
+
+func (db *testPurgeDB) ExecContext(_ context.Context, query string, _ ...any) (sql.Result, error) {
+	db.queries = append(db.queries, query)
+	return testPurgeResult(0), nil
+}
+
+type testPurgeResult int64
+
+func (r testPurgeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r testPurgeResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestRefreshTokenPurgerDeletesRevokedTokens(t *testing.T) {
+	db := &testPurgeDB{}
+	cfg := &apiConfig{
+		sl: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		db: database.New(db),
+	}
+
+	newRefreshTokenPurger(cfg).purge(context.Background())
+
+	if len(db.queries) != 1 {
+		t.Fatalf("executed %d statements, want 1", len(db.queries))
+	}
+	query := strings.Join(strings.Fields(db.queries[0]), " ")
+	for _, clause := range []string{
+		"expires_at < now()",
+		// logged out tokens are purged, replaced ones are kept for reuse detection
+		"(revoked_at is not null and replaced_by is null)",
+	} {
+		if !strings.Contains(query, clause) {
+			t.Errorf("purge %q does not delete tokens where %s", query, clause)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/database"
+	"github.com/nicholasss/plantae/internal/notify"
+)
+
+// === Reminder Scheduler ===
+
+// reminderScheduler periodically scans for plants that are due for care
+// and sends each user at most one reminder per day through their enabled channels.
+type reminderScheduler struct {
+	cfg      *apiConfig
+	interval time.Duration
+}
+
+func newReminderScheduler(cfg *apiConfig) *reminderScheduler {
+	return &reminderScheduler{
+		cfg:      cfg,
+		interval: cfg.reminderInterval,
+	}
+}
+
+// run blocks, scanning every interval until the context is cancelled
+func (s *reminderScheduler) run(ctx context.Context) {
+	if s.interval <= 0 {
+		s.cfg.sl.InfoContext(ctx, "Reminder scheduler is disabled")
+		return
+	}
+
+	s.cfg.sl.InfoContext(ctx, "Reminder scheduler started", "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.scan(ctx)
+		// the readiness check watches the heartbeat to notice a stalled scheduler
+		s.cfg.reminderHeartbeat.Store(time.Now().UnixNano())
+
+		select {
+		case <-ctx.Done():
+			s.cfg.sl.InfoContext(ctx, "Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan checks every user with an enabled channel once
+func (s *reminderScheduler) scan(ctx context.Context) {
+	users, err := s.cfg.db.GetAllUsersWithNotificationChannels(ctx)
+	if err != nil {
+		s.cfg.sl.WarnContext(ctx, "Reminder scheduler could not get users with notification channels", "error", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := s.remindUser(ctx, user, now)
+		if err != nil {
+			s.cfg.sl.WarnContext(ctx, "Reminder scheduler could not remind user", "user id", user.UserID, "error", err)
+		}
+	}
+}
+
+// sends a reminder to the user if any of their plants are due
+func (s *reminderScheduler) remindUser(ctx context.Context, user database.GetAllUsersWithNotificationChannelsRow, now time.Time) error {
+	remind, err := canRemindUser(user, now)
+	if err != nil || !remind {
+		return err
+	}
+
+	reminders, err := s.dueReminders(ctx, user.UserID, now)
+	if err != nil {
+		return err
+	}
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	channels, err := s.cfg.db.GetAllDeliverableNotificationChannelsByUserID(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+
+	msg := notify.Message{
+		Subject:   fmt.Sprintf("%d of your plants need care", len(reminders)),
+		Body:      "The following plants are due for water, or for their soil to be checked.",
+		Reminders: reminders,
+	}
+	if len(reminders) == 1 {
+		msg.Subject = "1 of your plants needs care"
+	}
+
+	sent := 0
+	for _, channel := range channels {
+		notifier, ok := s.cfg.notifiers[channel.ChannelType]
+		if !ok {
+			s.cfg.sl.WarnContext(ctx, "Unknown notification channel type", "channel id", channel.ID, "channel type", channel.ChannelType)
+			continue
+		}
+
+		msg.To = channel.Target
+		err := notifier.Notify(ctx, msg)
+		if err != nil {
+			s.cfg.sl.WarnContext(ctx, "Could not send reminder through channel", "user id", user.UserID, "channel id", channel.ID, "error", err)
+			continue
+		}
+		sent++
+	}
+
+	// retry on the next scan if nothing could be delivered
+	if sent == 0 {
+		return nil
+	}
+
+	s.cfg.sl.DebugContext(ctx, "Sent plant care reminder", "user id", user.UserID, "plants", len(reminders), "channels", sent)
+	remindedParams := database.SetUserLastRemindedAtParams{
+		UserID:         user.UserID,
+		LastRemindedAt: sql.NullTime{Time: now, Valid: true},
+	}
+	return s.cfg.db.SetUserLastRemindedAt(ctx, remindedParams)
+}
+
+// returns true when the user can be reminded at now, which in their timezone
+// is outside of their quiet hours and on a different day than their last reminder
+func canRemindUser(user database.GetAllUsersWithNotificationChannelsRow, now time.Time) (bool, error) {
+	timezone := "UTC"
+	if user.Timezone.Valid {
+		timezone = user.Timezone.String
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return false, err
+	}
+	localNow := now.In(location)
+
+	// one reminder per day
+	if user.LastRemindedAt.Valid {
+		lastLocal := user.LastRemindedAt.Time.In(location)
+		if lastLocal.Year() == localNow.Year() && lastLocal.YearDay() == localNow.YearDay() {
+			return false, nil
+		}
+	}
+
+	if user.QuietHoursStart.Valid && user.QuietHoursEnd.Valid {
+		if inQuietHours(localNow.Hour(), int(user.QuietHoursStart.Int32), int(user.QuietHoursEnd.Int32)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// lists the plants of a user that need care today or earlier
+func (s *reminderScheduler) dueReminders(ctx context.Context, userID uuid.UUID, now time.Time) ([]notify.Reminder, error) {
+	scheduleRecords, err := s.cfg.db.GetUsersPlantsWateringSchedule(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := make([]notify.Reminder, 0)
+	for _, record := range scheduleRecords {
+		schedule := computeWateringSchedule(wateringScheduleInputFromRecord(record), now)
+		if schedule.DaysUntilDue == nil || *schedule.DaysUntilDue > 0 {
+			continue
+		}
+
+		reminders = append(reminders, notify.Reminder{
+			PlantID:          record.UsersPlantID.String(),
+			PlantName:        record.PlantName.String,
+			PlantSpeciesName: record.SpeciesName,
+			Status:           schedule.Status,
+			DueAt:            *schedule.NextDueAt,
+		})
+	}
+
+	return reminders, nil
+}
+
+// returns true when hour falls within the quiet hours,
+// which may wrap around midnight (e.g. 22 until 7)
+func inQuietHours(hour, start, end int) bool {
+	if start == end {
+		return false
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+func TestInQuietHours(t *testing.T) {
+	tests := []struct {
+		hour, start, end int
+		want             bool
+	}{
+		// within a day
+		{12, 9, 17, true},
+		{9, 9, 17, true},
+		{17, 9, 17, false},
+		{8, 9, 17, false},
+		// wrapping around midnight
+		{22, 22, 7, true},
+		{23, 22, 7, true},
+		{0, 22, 7, true},
+		{6, 22, 7, true},
+		{7, 22, 7, false},
+		{12, 22, 7, false},
+		{21, 22, 7, false},
+		// the same start and end is no quiet hours at all
+		{3, 3, 3, false},
+	}
+
+	for _, test := range tests {
+		got := inQuietHours(test.hour, test.start, test.end)
+		if got != test.want {
+			t.Errorf("inQuietHours(%d, %d, %d) = %v, want %v", test.hour, test.start, test.end, got, test.want)
+		}
+	}
+}
+
+func TestCanRemindUser(t *testing.T) {
+	// 23:30 in New York, the next day in UTC
+	now := time.Date(2025, 7, 2, 3, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		user database.GetAllUsersWithNotificationChannelsRow
+		want bool
+	}{
+		{
+			name: "never reminded",
+			user: database.GetAllUsersWithNotificationChannelsRow{},
+			want: true,
+		},
+		{
+			name: "reminded earlier the same day",
+			user: database.GetAllUsersWithNotificationChannelsRow{
+				LastRemindedAt: sql.NullTime{Time: now.Add(-time.Hour * 3), Valid: true},
+			},
+			want: false,
+		},
+		{
+			name: "reminded the day before",
+			user: database.GetAllUsersWithNotificationChannelsRow{
+				LastRemindedAt: sql.NullTime{Time: now.Add(-time.Hour * 4), Valid: true},
+			},
+			want: true,
+		},
+		{
+			name: "reminded the same day in the user's timezone, the day before in utc",
+			user: database.GetAllUsersWithNotificationChannelsRow{
+				Timezone:       sql.NullString{String: "America/New_York", Valid: true},
+				LastRemindedAt: sql.NullTime{Time: now.Add(-time.Hour * 10), Valid: true},
+			},
+			want: false,
+		},
+		{
+			name: "reminded the day before in the user's timezone, the same day in utc",
+			user: database.GetAllUsersWithNotificationChannelsRow{
+				Timezone:       sql.NullString{String: "America/Sao_Paulo", Valid: true},
+				LastRemindedAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+			},
+			want: true,
+		},
+		{
+			name: "in quiet hours that wrap midnight in the user's timezone",
+			user: database.GetAllUsersWithNotificationChannelsRow{
+				Timezone:        sql.NullString{String: "America/New_York", Valid: true},
+				QuietHoursStart: sql.NullInt32{Int32: 22, Valid: true},
+				QuietHoursEnd:   sql.NullInt32{Int32: 7, Valid: true},
+			},
+			want: false,
+		},
+		{
+			name: "quiet hours that are only quiet in utc",
+			user: database.GetAllUsersWithNotificationChannelsRow{
+				Timezone:        sql.NullString{String: "Asia/Tokyo", Valid: true},
+				QuietHoursStart: sql.NullInt32{Int32: 22, Valid: true},
+				QuietHoursEnd:   sql.NullInt32{Int32: 7, Valid: true},
+			},
+			want: true,
+		},
+		{
+			name: "only a start of quiet hours",
+			user: database.GetAllUsersWithNotificationChannelsRow{
+				QuietHoursStart: sql.NullInt32{Int32: 0, Valid: true},
+			},
+			want: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := canRemindUser(test.user, now)
+		if err != nil {
+			t.Errorf("%s: canRemindUser returned %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: canRemindUser = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCanRemindUserUnknownTimezone(t *testing.T) {
+	user := database.GetAllUsersWithNotificationChannelsRow{
+		Timezone: sql.NullString{String: "Mars/Olympus_Mons", Valid: true},
+	}
+
+	_, err := canRemindUser(user, time.Now())
+	if err == nil {
+		t.Error("canRemindUser with an unknown timezone returned no error")
+	}
+}
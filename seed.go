@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// seedFS bundles the example catalog into the binary, it is a catalog archive
+// with fixed ids so seeding the same database again does not create duplicates.
+// records that were created through the api are matched by name instead, see matchSeedCatalog
+//
+//go:embed seed/catalog.json
+var seedFS embed.FS
+
+const seedCatalogPath = "seed/catalog.json"
+
+// === Seed Command ===
+
+// runSeed loads the example plant catalog into the database
+// usage: plantae seed [-overwrite] [-dry-run]
+func runSeed(cfg *apiConfig, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	overwrite := flags.Bool("overwrite", false, "reset seeded records that were changed back to the bundled data")
+	dryRun := flags.Bool("dry-run", false, "report what would be seeded without committing")
+	err := parseCommandFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	archive, err := loadSeedCatalog()
+	if err != nil {
+		return err
+	}
+
+	conflict := catalogConflictSkip
+	if *overwrite {
+		conflict = catalogConflictOverwrite
+	}
+
+	report, err := seedCatalog(context.Background(), cfg, archive, conflict, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Seeded example catalog (dry run: %t, conflict: %s)\n", report.DryRun, report.Conflict)
+	printSeedTable("plant types", report.PlantTypes)
+	printSeedTable("light needs", report.LightNeeds)
+	printSeedTable("water needs", report.WaterNeeds)
+	printSeedTable("plant species", report.PlantSpecies)
+	printSeedTable("plant names", report.PlantNames)
+	return nil
+}
+
+// reads and checks the bundled catalog archive
+func loadSeedCatalog() (CatalogArchive, error) {
+	var archive CatalogArchive
+
+	data, err := seedFS.ReadFile(seedCatalogPath)
+	if err != nil {
+		return archive, err
+	}
+
+	err = json.Unmarshal(data, &archive)
+	if err != nil {
+		return archive, fmt.Errorf("could not decode bundled catalog: %w", err)
+	}
+
+	err = archive.validate()
+	if err != nil {
+		return archive, fmt.Errorf("bundled catalog is invalid: %w", err)
+	}
+
+	return archive, nil
+}
+
+// imports the archive in a single transaction, existing records with the same ids or names are
+// skipped or overwritten depending on conflict
+func seedCatalog(ctx context.Context, cfg *apiConfig, archive CatalogArchive, conflict string, dryRun bool) (CatalogImportReport, error) {
+	tx, err := cfg.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return CatalogImportReport{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = matchSeedCatalog(ctx, qtx, &archive)
+	if err != nil {
+		return CatalogImportReport{}, fmt.Errorf("could not match example catalog to existing records: %w", err)
+	}
+
+	report, err := importCatalogArchive(ctx, qtx, archive, conflict)
+	if err != nil {
+		if catalogImportRejected(err) {
+			return report, fmt.Errorf("example catalog conflicts with records already in the database: %w", err)
+		}
+		return report, err
+	}
+
+	report.DryRun = dryRun
+	if dryRun {
+		return report, nil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// gives the records of the archive the ids of existing records with the same natural key,
+// the name of a type, light need, water need, or species, and the language and name of a common name.
+// the import then skips or overwrites them like records with the same id, instead of inserting duplicates
+// or failing on the unique plant type names. links between the records follow their new ids
+func matchSeedCatalog(ctx context.Context, q *database.Queries, archive *CatalogArchive) error {
+	// archive id to the id of the existing record
+	matched := make(map[uuid.UUID]uuid.UUID)
+	match := func(record *CatalogArchiveRecord, existingID uuid.UUID, err error) error {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		matched[record.ID] = existingID
+		record.ID = existingID
+		return nil
+	}
+	relink := func(id *uuid.UUID) {
+		if id == nil {
+			return
+		}
+		if existingID, ok := matched[*id]; ok {
+			*id = existingID
+		}
+	}
+
+	for i := range archive.PlantTypes {
+		record := &archive.PlantTypes[i]
+		existingID, err := q.GetPlantTypeIDByName(ctx, record.Name)
+		err = match(&record.CatalogArchiveRecord, existingID, err)
+		if err != nil {
+			return fmt.Errorf("matching plant type %q: %w", record.Name, err)
+		}
+	}
+
+	for i := range archive.LightNeeds {
+		record := &archive.LightNeeds[i]
+		existingID, err := q.GetLightNeedIDByName(ctx, record.Name)
+		err = match(&record.CatalogArchiveRecord, existingID, err)
+		if err != nil {
+			return fmt.Errorf("matching light need %q: %w", record.Name, err)
+		}
+	}
+
+	for i := range archive.WaterNeeds {
+		record := &archive.WaterNeeds[i]
+		existingID, err := q.GetWaterNeedIDByPlantType(ctx, record.PlantType)
+		err = match(&record.CatalogArchiveRecord, existingID, err)
+		if err != nil {
+			return fmt.Errorf("matching water need %q: %w", record.PlantType, err)
+		}
+	}
+
+	for i := range archive.PlantSpecies {
+		record := &archive.PlantSpecies[i]
+		relink(record.PlantTypeID)
+		relink(record.LightNeedsID)
+		relink(record.WaterNeedsID)
+		existingID, err := q.GetPlantSpeciesIDBySpeciesName(ctx, record.SpeciesName)
+		err = match(&record.CatalogArchiveRecord, existingID, err)
+		if err != nil {
+			return fmt.Errorf("matching plant species %q: %w", record.SpeciesName, err)
+		}
+	}
+
+	for i := range archive.PlantNames {
+		record := &archive.PlantNames[i]
+		relink(&record.PlantID)
+		existingID, err := q.GetPlantNameIDByCommonName(ctx, database.GetPlantNameIDByCommonNameParams{
+			PlantID:    record.PlantID,
+			LangCode:   ptrNullString(record.LangCode),
+			CommonName: ptrNullString(record.CommonName),
+		})
+		err = match(&record.CatalogArchiveRecord, existingID, err)
+		if err != nil {
+			return fmt.Errorf("matching plant name %q: %w", record.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func printSeedTable(table string, tableReport CatalogImportTableReport) {
+	fmt.Printf("  %-14s inserted: %d, updated: %d, skipped: %d\n", table, tableReport.Inserted, tableReport.Updated, tableReport.Skipped)
+}
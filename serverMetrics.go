@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/nicholasss/plantae/internal/database"
+	"github.com/nicholasss/plantae/internal/metrics"
+)
+
+// how long a scrape waits for the domain counts
+const metricsDatabaseTimeout = time.Second * 2
+
+// serverMetrics are the metrics served on /metrics
+type serverMetrics struct {
+	registry *metrics.Registry
+
+	// http
+	requests         *metrics.CounterVec
+	requestDuration  *metrics.HistogramVec
+	requestsInFlight *metrics.Gauge
+
+	// database pool, read from sql.DBStats
+	dbMaxOpen      *metrics.Gauge
+	dbOpen         *metrics.Gauge
+	dbInUse        *metrics.Gauge
+	dbIdle         *metrics.Gauge
+	dbWaitCount    *metrics.Gauge
+	dbWaitDuration *metrics.Gauge
+
+	// domain
+	users        *metrics.Gauge
+	plantSpecies *metrics.Gauge
+	userPlants   *metrics.Gauge
+}
+
+func newServerMetrics(db *sql.DB, q *database.Queries, sl *slog.Logger) *serverMetrics {
+	registry := metrics.NewRegistry()
+	m := &serverMetrics{
+		registry: registry,
+
+		requests:         registry.NewCounterVec("plantae_http_requests_total", "HTTP requests handled, by route pattern and status code.", "method", "route", "code"),
+		requestDuration:  registry.NewHistogramVec("plantae_http_request_duration_seconds", "Time taken to handle HTTP requests, by route pattern.", metrics.DefaultBuckets, "method", "route"),
+		requestsInFlight: registry.NewGauge("plantae_http_requests_in_flight", "HTTP requests currently being handled."),
+
+		dbMaxOpen:      registry.NewGauge("plantae_db_max_open_connections", "Maximum number of open connections to the database, 0 is unlimited."),
+		dbOpen:         registry.NewGauge("plantae_db_open_connections", "Established connections to the database, in use and idle."),
+		dbInUse:        registry.NewGauge("plantae_db_in_use_connections", "Connections to the database currently in use."),
+		dbIdle:         registry.NewGauge("plantae_db_idle_connections", "Idle connections to the database."),
+		dbWaitCount:    registry.NewCounter("plantae_db_wait_count_total", "Times a query waited for a free database connection."),
+		dbWaitDuration: registry.NewCounter("plantae_db_wait_duration_seconds_total", "Total time spent waiting for a free database connection."),
+
+		users:        registry.NewGauge("plantae_users", "Registered users that are not deleted."),
+		plantSpecies: registry.NewGauge("plantae_plant_species", "Plant species in the catalog that are not deleted."),
+		userPlants:   registry.NewGauge("plantae_user_plants", "Plants tracked by users that are not deleted."),
+	}
+
+	registry.OnScrape(func(ctx context.Context) {
+		stats := db.Stats()
+		m.dbMaxOpen.Set(float64(stats.MaxOpenConnections))
+		m.dbOpen.Set(float64(stats.OpenConnections))
+		m.dbInUse.Set(float64(stats.InUse))
+		m.dbIdle.Set(float64(stats.Idle))
+		m.dbWaitCount.Set(float64(stats.WaitCount))
+		m.dbWaitDuration.Set(stats.WaitDuration.Seconds())
+	})
+
+	registry.OnScrape(func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, metricsDatabaseTimeout)
+		defer cancel()
+
+		// the previous counts are kept when the database does not answer
+		counts, err := q.GetMetricsCounts(ctx)
+		if err != nil {
+			sl.Warn("Could not get counts for metrics", "error", err)
+			return
+		}
+		m.users.Set(float64(counts.Users))
+		m.plantSpecies.Set(float64(counts.PlantSpecies))
+		m.userPlants.Set(float64(counts.UserPlants))
+	})
+
+	return m
+}
@@ -0,0 +1,232 @@
+-- name: ExportPlantTypes :many
+select
+  id, created_at, updated_at,
+  created_by, updated_by,
+  name, description,
+  max_temperature_celsius, min_temperature_celsius,
+  max_humidity_percent, min_humidity_percent,
+  soil_organic_mix, soil_grit_mix, soil_drainage_mix
+from plant_types
+where deleted_at is null
+order by created_at, id;
+
+-- name: ExportLightNeeds :many
+select
+  id, created_at, updated_at,
+  created_by, updated_by,
+  name, description
+from light_needs
+where deleted_at is null
+order by created_at, id;
+
+-- name: ExportWaterNeeds :many
+select
+  id, created_at, updated_at,
+  created_by, updated_by,
+  plant_type, description,
+  dry_soil_mm, dry_soil_days
+from water_needs
+where deleted_at is null
+order by created_at, id;
+
+-- name: ExportPlantSpecies :many
+select
+  id, created_at, updated_at,
+  created_by, updated_by,
+  species_name,
+  human_poison_toxic, pet_poison_toxic,
+  human_edible, pet_edible,
+  plant_type_id, light_needs_id, water_needs_id
+from plant_species
+where deleted_at is null
+order by created_at, id;
+
+-- name: ExportPlantNames :many
+select
+  id, created_at, updated_at,
+  created_by, updated_by,
+  plant_id, lang_code, common_name
+from plant_names
+where deleted_at is null
+order by created_at, id;
+
+-- name: GetPlantTypeIDByName :one
+select id from plant_types
+-- names are unique even for deleted types, so deleted types are matched as well
+where name = $1;
+
+-- name: GetLightNeedIDByName :one
+select id from light_needs
+where
+  name = $1 and
+  deleted_at is null
+order by created_at, id
+limit 1;
+
+-- name: GetWaterNeedIDByPlantType :one
+select id from water_needs
+where
+  plant_type = $1 and
+  deleted_at is null
+order by created_at, id
+limit 1;
+
+-- name: GetPlantSpeciesIDBySpeciesName :one
+select id from plant_species
+where
+  lower(species_name) = lower(sqlc.arg('species_name')::text) and
+  deleted_at is null
+order by created_at, id
+limit 1;
+
+-- name: GetPlantNameIDByCommonName :one
+select id from plant_names
+where
+  plant_id = $1 and
+  lang_code = $2 and
+  common_name = $3 and
+  deleted_at is null
+order by created_at, id
+limit 1;
+
+-- name: ImportPlantType :one
+insert into plant_types (
+  id, created_at, updated_at,
+  created_by, updated_by,
+  name, description,
+  max_temperature_celsius, min_temperature_celsius,
+  max_humidity_percent, min_humidity_percent,
+  soil_organic_mix, soil_grit_mix, soil_drainage_mix
+) values (
+  sqlc.arg('id'), sqlc.arg('created_at'), sqlc.arg('updated_at'),
+  sqlc.arg('created_by'), sqlc.arg('updated_by'),
+  sqlc.arg('name'), sqlc.arg('description'),
+  sqlc.arg('max_temperature_celsius'), sqlc.arg('min_temperature_celsius'),
+  sqlc.arg('max_humidity_percent'), sqlc.arg('min_humidity_percent'),
+  sqlc.arg('soil_organic_mix'), sqlc.arg('soil_grit_mix'), sqlc.arg('soil_drainage_mix')
+)
+-- rows with an existing id are only replaced when overwriting, restoring them if they were deleted
+on conflict (id) do update set
+  created_at = excluded.created_at,
+  updated_at = excluded.updated_at,
+  deleted_at = null,
+  created_by = excluded.created_by,
+  updated_by = excluded.updated_by,
+  deleted_by = null,
+  name = excluded.name,
+  description = excluded.description,
+  max_temperature_celsius = excluded.max_temperature_celsius,
+  min_temperature_celsius = excluded.min_temperature_celsius,
+  max_humidity_percent = excluded.max_humidity_percent,
+  min_humidity_percent = excluded.min_humidity_percent,
+  soil_organic_mix = excluded.soil_organic_mix,
+  soil_grit_mix = excluded.soil_grit_mix,
+  soil_drainage_mix = excluded.soil_drainage_mix
+where sqlc.arg('overwrite')::boolean
+-- xmax is only zero for freshly inserted rows
+returning (xmax = 0)::boolean as inserted;
+
+-- name: ImportLightNeed :one
+insert into light_needs (
+  id, created_at, updated_at,
+  created_by, updated_by,
+  name, description
+) values (
+  sqlc.arg('id'), sqlc.arg('created_at'), sqlc.arg('updated_at'),
+  sqlc.arg('created_by'), sqlc.arg('updated_by'),
+  sqlc.arg('name'), sqlc.arg('description')
+)
+on conflict (id) do update set
+  created_at = excluded.created_at,
+  updated_at = excluded.updated_at,
+  deleted_at = null,
+  created_by = excluded.created_by,
+  updated_by = excluded.updated_by,
+  deleted_by = null,
+  name = excluded.name,
+  description = excluded.description
+where sqlc.arg('overwrite')::boolean
+returning (xmax = 0)::boolean as inserted;
+
+-- name: ImportWaterNeed :one
+insert into water_needs (
+  id, created_at, updated_at,
+  created_by, updated_by,
+  plant_type, description,
+  dry_soil_mm, dry_soil_days
+) values (
+  sqlc.arg('id'), sqlc.arg('created_at'), sqlc.arg('updated_at'),
+  sqlc.arg('created_by'), sqlc.arg('updated_by'),
+  sqlc.arg('plant_type'), sqlc.arg('description'),
+  sqlc.arg('dry_soil_mm'), sqlc.arg('dry_soil_days')
+)
+on conflict (id) do update set
+  created_at = excluded.created_at,
+  updated_at = excluded.updated_at,
+  deleted_at = null,
+  created_by = excluded.created_by,
+  updated_by = excluded.updated_by,
+  deleted_by = null,
+  plant_type = excluded.plant_type,
+  description = excluded.description,
+  dry_soil_mm = excluded.dry_soil_mm,
+  dry_soil_days = excluded.dry_soil_days
+where sqlc.arg('overwrite')::boolean
+returning (xmax = 0)::boolean as inserted;
+
+-- name: ImportPlantSpecies :one
+insert into plant_species (
+  id, created_at, updated_at,
+  created_by, updated_by,
+  species_name,
+  human_poison_toxic, pet_poison_toxic,
+  human_edible, pet_edible,
+  plant_type_id, light_needs_id, water_needs_id
+) values (
+  sqlc.arg('id'), sqlc.arg('created_at'), sqlc.arg('updated_at'),
+  sqlc.arg('created_by'), sqlc.arg('updated_by'),
+  sqlc.arg('species_name'),
+  sqlc.arg('human_poison_toxic'), sqlc.arg('pet_poison_toxic'),
+  sqlc.arg('human_edible'), sqlc.arg('pet_edible'),
+  sqlc.arg('plant_type_id'), sqlc.arg('light_needs_id'), sqlc.arg('water_needs_id')
+)
+on conflict (id) do update set
+  created_at = excluded.created_at,
+  updated_at = excluded.updated_at,
+  deleted_at = null,
+  created_by = excluded.created_by,
+  updated_by = excluded.updated_by,
+  deleted_by = null,
+  species_name = excluded.species_name,
+  human_poison_toxic = excluded.human_poison_toxic,
+  pet_poison_toxic = excluded.pet_poison_toxic,
+  human_edible = excluded.human_edible,
+  pet_edible = excluded.pet_edible,
+  plant_type_id = excluded.plant_type_id,
+  light_needs_id = excluded.light_needs_id,
+  water_needs_id = excluded.water_needs_id
+where sqlc.arg('overwrite')::boolean
+returning (xmax = 0)::boolean as inserted;
+
+-- name: ImportPlantName :one
+insert into plant_names (
+  id, created_at, updated_at,
+  created_by, updated_by,
+  plant_id, lang_code, common_name
+) values (
+  sqlc.arg('id'), sqlc.arg('created_at'), sqlc.arg('updated_at'),
+  sqlc.arg('created_by'), sqlc.arg('updated_by'),
+  sqlc.arg('plant_id'), sqlc.arg('lang_code'), sqlc.arg('common_name')
+)
+on conflict (id) do update set
+  created_at = excluded.created_at,
+  updated_at = excluded.updated_at,
+  deleted_at = null,
+  created_by = excluded.created_by,
+  updated_by = excluded.updated_by,
+  deleted_by = null,
+  plant_id = excluded.plant_id,
+  lang_code = excluded.lang_code,
+  common_name = excluded.common_name
+where sqlc.arg('overwrite')::boolean
+returning (xmax = 0)::boolean as inserted;
@@ -0,0 +1,17 @@
+-- name: CreateEmailVerificationToken :exec
+insert into email_verification_tokens (
+  token_hash, created_at, expires_at, user_id
+) values (
+  $1, now(), $2, $3
+);
+
+-- name: UseEmailVerificationToken :one
+delete from email_verification_tokens
+where
+  token_hash = $1 and
+  expires_at > now()
+returning user_id;
+
+-- name: DeleteEmailVerificationTokensByUserID :exec
+delete from email_verification_tokens
+where user_id = $1;
@@ -32,6 +32,15 @@ from light_needs
   where deleted_at is null
   order by created_at desc;
 
+-- name: GetLightNeedByID :one
+select
+  id,
+  name,
+  description
+from light_needs
+  where id = $1
+  and deleted_at is null;
+
 -- name: UpdateLightNeedsByID :exec
 update light_needs
   set updated_at = now(),
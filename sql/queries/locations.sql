@@ -0,0 +1,66 @@
+-- name: CreateLocation :one
+insert into locations (
+  id,
+  created_at, updated_at,
+  created_by, updated_by,
+  user_id,
+  name, light_exposure,
+  min_temperature_celsius, max_temperature_celsius,
+  min_humidity_percent, max_humidity_percent
+) values (
+  gen_random_uuid(),
+  now(), now(),
+  $1, $1,
+  $1,
+  $2, $3,
+  $4, $5,
+  $6, $7
+) returning
+  id, name, light_exposure,
+  min_temperature_celsius, max_temperature_celsius,
+  min_humidity_percent, max_humidity_percent;
+
+-- name: GetAllLocationsByUserID :many
+select
+  id, name, light_exposure,
+  min_temperature_celsius, max_temperature_celsius,
+  min_humidity_percent, max_humidity_percent
+from locations
+  where user_id = $1
+  and deleted_at is null
+  order by name asc;
+
+-- name: GetLocationByID :one
+select
+  id, name, light_exposure,
+  min_temperature_celsius, max_temperature_celsius,
+  min_humidity_percent, max_humidity_percent
+from locations
+  where id = $1
+  and user_id = $2
+  and deleted_at is null;
+
+-- name: UpdateLocationByID :execrows
+update locations
+set updated_at = now(),
+  updated_by = $2,
+  name = $3,
+  light_exposure = $4,
+  min_temperature_celsius = $5,
+  max_temperature_celsius = $6,
+  min_humidity_percent = $7,
+  max_humidity_percent = $8
+where id = $1
+  and user_id = $2
+  and deleted_at is null;
+
+-- name: DeleteLocationByID :execrows
+update locations
+set
+  deleted_at = now(),
+  deleted_by = $2,
+  updated_at = now(),
+  updated_by = $3
+where id = $1
+  and user_id = $3
+  and deleted_at is null;
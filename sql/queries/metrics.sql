@@ -0,0 +1,5 @@
+-- name: GetMetricsCounts :one
+select
+  (select count(*) from users where deleted_at is null) as users,
+  (select count(*) from plant_species where deleted_at is null) as plant_species,
+  (select count(*) from users_plants where deleted_at is null) as user_plants;
@@ -0,0 +1,117 @@
+-- name: GetNotificationSettingsByUserID :one
+select
+  user_id,
+  quiet_hours_start, quiet_hours_end,
+  timezone, last_reminded_at
+from notification_settings
+  where user_id = $1
+  and deleted_at is null
+  limit 1;
+
+-- name: UpsertNotificationSettings :one
+insert into notification_settings (
+  user_id,
+  created_at, updated_at,
+  created_by, updated_by,
+  quiet_hours_start, quiet_hours_end,
+  timezone
+) values (
+  $1,
+  now(), now(),
+  $1, $1,
+  $2, $3,
+  $4
+) on conflict (user_id) do update
+set updated_at = now(),
+  updated_by = excluded.updated_by,
+  quiet_hours_start = excluded.quiet_hours_start,
+  quiet_hours_end = excluded.quiet_hours_end,
+  timezone = excluded.timezone
+returning
+  user_id,
+  quiet_hours_start, quiet_hours_end,
+  timezone;
+
+-- name: SetUserLastRemindedAt :exec
+insert into notification_settings (
+  user_id,
+  created_at, updated_at,
+  created_by, updated_by,
+  last_reminded_at
+) values (
+  $1,
+  now(), now(),
+  $1, $1,
+  $2
+) on conflict (user_id) do update
+set last_reminded_at = excluded.last_reminded_at;
+
+-- name: GetAllUsersWithNotificationChannels :many
+select distinct
+  nc.user_id,
+  ns.quiet_hours_start, ns.quiet_hours_end,
+  ns.timezone, ns.last_reminded_at
+from
+  notification_channels as nc
+join
+  users as u on nc.user_id = u.id and u.deleted_at is null
+left join
+  notification_settings as ns on nc.user_id = ns.user_id and ns.deleted_at is null
+where
+  nc.enabled = true and
+  nc.deleted_at is null and
+  (nc.channel_type <> 'email' or (u.email_verified_at is not null and lower(nc.target) = lower(u.email)));
+
+-- name: CreateNotificationChannel :one
+insert into notification_channels (
+  id,
+  created_at, updated_at,
+  created_by, updated_by,
+  user_id,
+  channel_type, target, enabled
+) values (
+  gen_random_uuid(),
+  now(), now(),
+  $1, $1,
+  $1,
+  $2, $3, true
+) returning id, channel_type, target, enabled;
+
+-- name: GetAllNotificationChannelsByUserID :many
+select
+  id, channel_type, target, enabled
+from notification_channels
+  where user_id = $1
+  and deleted_at is null
+  order by created_at asc;
+
+-- name: GetAllDeliverableNotificationChannelsByUserID :many
+select
+  nc.id, nc.channel_type, nc.target
+from notification_channels as nc
+join users as u on nc.user_id = u.id
+  where nc.user_id = $1
+  and nc.enabled = true
+  and nc.deleted_at is null
+  and (nc.channel_type <> 'email' or (u.email_verified_at is not null and lower(nc.target) = lower(u.email)))
+  order by nc.created_at asc;
+
+-- name: UpdateNotificationChannelEnabledByID :execrows
+update notification_channels
+set updated_at = now(),
+  updated_by = $2,
+  enabled = $3
+where id = $1
+  and user_id = $2
+  and deleted_at is null;
+
+-- name: DeleteNotificationChannelByID :execrows
+update notification_channels
+set
+  deleted_at = now(),
+  deleted_by = $2,
+  updated_at = now(),
+  updated_by = $3
+where id = $1
+  and user_id = $3
+  and deleted_at is null;
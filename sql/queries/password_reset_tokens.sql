@@ -0,0 +1,25 @@
+-- name: CreatePasswordResetToken :exec
+insert into password_reset_tokens (
+  token_hash, created_at, expires_at, user_id
+) values (
+  $1, now(), $2, $3
+);
+
+-- name: UsePasswordResetToken :one
+delete from password_reset_tokens
+where
+  token_hash = $1 and
+  expires_at > now()
+returning user_id;
+
+-- name: DeletePasswordResetTokensByUserID :exec
+delete from password_reset_tokens
+where user_id = $1;
+
+-- name: HasRecentPasswordResetToken :one
+select exists (
+  select 1 from password_reset_tokens
+  where
+    user_id = $1 and
+    created_at > $2
+) as recent;
@@ -0,0 +1,57 @@
+-- name: CreatePlantCareEvent :one
+insert into plant_care_events (
+  id,
+  created_at, updated_at,
+  created_by, updated_by,
+  users_plant_id, user_id,
+  event_type, occurred_at, notes
+) values (
+  gen_random_uuid(),
+  now(), now(),
+  $1, $1,
+  $2, $1,
+  $3, $4, $5
+) returning
+  id, users_plant_id, user_id,
+  event_type, occurred_at, notes;
+
+-- name: GetAllPlantCareEventsOrderedByOccurred :many
+select
+  id, users_plant_id, user_id,
+  event_type, occurred_at, notes
+from plant_care_events
+  where users_plant_id = $1
+  and deleted_at is null
+  order by occurred_at desc, created_at desc;
+
+-- name: GetPlantCareEventByID :one
+select
+  id, users_plant_id, user_id,
+  event_type, occurred_at, notes
+from plant_care_events
+  where id = $1
+  and users_plant_id = $2
+  and deleted_at is null
+  limit 1;
+
+-- name: UpdatePlantCareEventByID :exec
+update plant_care_events
+set updated_at = now(),
+  updated_by = $3,
+  event_type = $4,
+  occurred_at = $5,
+  notes = $6
+where id = $1
+  and users_plant_id = $2
+  and deleted_at is null;
+
+-- name: DeletePlantCareEventByID :exec
+update plant_care_events
+set
+  deleted_at = now(),
+  deleted_by = $3,
+  updated_at = now(),
+  updated_by = $3
+where id = $1
+  and users_plant_id = $2
+  and deleted_at is null;
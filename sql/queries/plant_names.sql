@@ -32,18 +32,31 @@ select
 	id,
   plant_id,
   lang_code,
-  common_name
+  common_name,
+  created_at, updated_at
 from plant_names
   where deleted_at is null
-  order by created_at desc;
+  and (sqlc.narg('lang_code')::text is null or lang_code ilike sqlc.narg('lang_code')::text)
+  and (
+    sqlc.narg('cursor_time')::timestamptz is null or
+    (created_at, id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+  )
+  order by created_at desc, id desc
+  limit sqlc.arg('row_limit');
 
--- name: GetAllPlantNamesForLanguageOrderedByCreated :many
+-- name: GetAllPlantNamesOrderedByUpdated :many
 select 
 	id,
   plant_id,
   lang_code,
-  common_name
+  common_name,
+  created_at, updated_at
 from plant_names
-  where lang_code ilike $1
-  and deleted_at is null
-  order by created_at desc;
+  where deleted_at is null
+  and (sqlc.narg('lang_code')::text is null or lang_code ilike sqlc.narg('lang_code')::text)
+  and (
+    sqlc.narg('cursor_time')::timestamptz is null or
+    (updated_at, id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+  )
+  order by updated_at desc, id desc
+  limit sqlc.arg('row_limit');
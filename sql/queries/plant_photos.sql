@@ -0,0 +1,50 @@
+-- name: CreatePlantPhoto :one
+insert into plant_photos (
+  id,
+  created_at, updated_at,
+  created_by, updated_by,
+  users_plant_id, user_id,
+  content_type, size_bytes,
+  width, height,
+  blob_key, thumbnail_key
+) values (
+  $1,
+  now(), now(),
+  $2, $2,
+  $3, $2,
+  $4, $5,
+  $6, $7,
+  $8, $9
+) returning
+  id, users_plant_id, content_type, size_bytes,
+  width, height, blob_key, thumbnail_key, created_at;
+
+-- name: GetAllPlantPhotosByUsersPlantID :many
+select
+  id, users_plant_id, content_type, size_bytes,
+  width, height, blob_key, thumbnail_key, created_at
+from plant_photos
+  where users_plant_id = $1
+  and deleted_at is null
+  order by created_at desc;
+
+-- name: GetPlantPhotoByID :one
+select
+  id, users_plant_id, content_type, size_bytes,
+  width, height, blob_key, thumbnail_key, created_at
+from plant_photos
+  where id = $1
+  and users_plant_id = $2
+  and deleted_at is null
+  limit 1;
+
+-- name: DeletePlantPhotoByID :execrows
+update plant_photos
+set
+  deleted_at = now(),
+  deleted_by = $3,
+  updated_at = now(),
+  updated_by = $3
+where id = $1
+  and users_plant_id = $2
+  and deleted_at is null;
@@ -11,17 +11,6 @@ insert into plant_species (
 -- name: ResetPlantSpeciesTable :exec
 delete from plant_species;
 
--- name: GetPlantSpeciesByName :one
-select 
-	id, created_at, updated_at,
-	created_by, updated_by, species_name,
-	human_poison_toxic, pet_poison_toxic,
-	human_edible, pet_edible
-from plant_species
-	where species_name like $1
-  and deleted_at is null
-  limit 1;
-
 -- name: GetPlantSpeciesByID :one
 select 
 	id, created_at, updated_at,
@@ -41,7 +30,12 @@ select
 	human_edible, pet_edible
 from plant_species
   where deleted_at is null
-  order by updated_at desc;
+  and (
+    sqlc.narg('cursor_time')::timestamptz is null or
+    (updated_at, id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+  )
+  order by updated_at desc, id desc
+  limit sqlc.arg('row_limit');
 
 -- name: GetAllPlantSpeciesOrderedByCreated :many
 select 
@@ -51,7 +45,12 @@ select
 	human_edible, pet_edible
 from plant_species
   where deleted_at is null
-  order by created_at desc;
+  and (
+    sqlc.narg('cursor_time')::timestamptz is null or
+    (created_at, id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+  )
+  order by created_at desc, id desc
+  limit sqlc.arg('row_limit');
 
 -- name: UpdatePlantSpeciesPropertiesByID :exec
 update plant_species
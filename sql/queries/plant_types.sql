@@ -56,4 +56,27 @@ select
   soil_organic_mix, soil_grit_mix, soil_drainage_mix
 from plant_types
   where deleted_at is null
-  order by created_at desc;
+  and (
+    sqlc.narg('cursor_time')::timestamptz is null or
+    (created_at, id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+  )
+  order by created_at desc, id desc
+  limit sqlc.arg('row_limit');
+
+-- name: GetAllPlantTypesOrderedByUpdated :many
+select 
+	id,
+  created_at, updated_at,
+	created_by, updated_by,
+  name, description,
+  max_temperature_celsius, min_temperature_celsius,
+  max_humidity_percent, min_humidity_percent,
+  soil_organic_mix, soil_grit_mix, soil_drainage_mix
+from plant_types
+  where deleted_at is null
+  and (
+    sqlc.narg('cursor_time')::timestamptz is null or
+    (updated_at, id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+  )
+  order by updated_at desc, id desc
+  limit sqlc.arg('row_limit');
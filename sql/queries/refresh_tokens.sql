@@ -1,28 +1,30 @@
 -- name: CreateRefreshToken :one
 insert into refresh_tokens (
-  refresh_token,
+  token_hash,
   created_at, updated_at,
   created_by, updated_by,
-  expires_at, user_id
+  expires_at, user_id,
+  family_id,
+  user_agent, ip_address, last_used_at
 ) values (
   $1,
   now(), now(),
   $2, $2,
-  $3, $2
+  $3, $2,
+  $4,
+  $5, $6, now()
 ) returning
-  refresh_token,
+  token_hash,
   created_at, updated_at,
   created_by, updated_by,
-  expires_at, user_id;
+  expires_at, user_id,
+  family_id;
 
--- name: GetUserFromRefreshToken :one
+-- name: GetRefreshTokenByHash :one
 select * from refresh_tokens
 where
-  refresh_token = $1 and
-  deleted_by is null and
-  revoked_at is null
-order by created_at desc
-limit 1;
+  token_hash = $1 and
+  deleted_by is null;
 
 -- name: GetValidRefreshTokenFromUserID :one
 select * from refresh_tokens
@@ -33,6 +35,86 @@ where
 order by created_at desc
 limit 1;
 
+-- name: RotateRefreshToken :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $2,
+  revoked_at = now(),
+  revoked_by = $2,
+  replaced_by = $3
+where
+  token_hash = $1 and
+  revoked_at is null;
+
+-- name: RevokeRefreshTokenFamily :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $2,
+  revoked_at = now(),
+  revoked_by = $2
+where
+  family_id = $1 and
+  revoked_at is null;
+
+-- name: RevokeRefreshTokenFamilyByUserID :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $2,
+  revoked_at = now(),
+  revoked_by = $2
+where
+  family_id = $1 and
+  user_id = $2 and
+  revoked_at is null;
+
+-- name: RevokeOtherRefreshTokenFamilies :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $1,
+  revoked_at = now(),
+  revoked_by = $1
+where
+  user_id = $1 and
+  family_id <> $2 and
+  revoked_at is null;
+
+-- name: GetAllActiveSessionsByUserID :many
+select
+  t.family_id,
+  f.started_at,
+  coalesce(t.last_used_at, t.created_at) as last_used_at,
+  t.user_agent,
+  t.ip_address,
+  t.expires_at
+from refresh_tokens t
+join (
+  select family_id, min(created_at) as started_at
+  from refresh_tokens
+  where user_id = $1
+  group by family_id
+) f on f.family_id = t.family_id
+where
+  t.user_id = $1 and
+  t.deleted_by is null and
+  t.revoked_at is null and
+  t.expires_at > now()
+order by coalesce(t.last_used_at, t.created_at) desc;
+
+-- name: IsRefreshTokenFamilyActive :one
+select exists (
+  select 1 from refresh_tokens
+  where
+    family_id = $1 and
+    user_id = $2 and
+    deleted_by is null and
+    revoked_at is null and
+    expires_at > now()
+) as active;
+
 -- name: RevokeRefreshTokenWithToken :one
 update refresh_tokens
 set
@@ -40,5 +122,25 @@ set
   updated_by = $2,
   revoked_at = now(),
   revoked_by = $2
-where refresh_token = $1
+where
+  token_hash = $1 and
+  revoked_at is null
 returning user_id;
+
+-- name: RevokeAllRefreshTokensByUserID :execrows
+update refresh_tokens
+set
+  updated_at = now(),
+  updated_by = $2,
+  revoked_at = now(),
+  revoked_by = $2
+where
+  user_id = $1 and
+  revoked_at is null;
+
+-- name: PurgeExpiredRefreshTokens :execrows
+delete from refresh_tokens
+where
+  expires_at < now() or
+  -- revoked without a replacement, such as on logout, so reuse detection never needs it
+  (revoked_at is not null and replaced_by is null);
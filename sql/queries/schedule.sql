@@ -0,0 +1,37 @@
+-- name: GetUsersPlantsWateringSchedule :many
+with last_watering as (
+  select
+    pce.users_plant_id,
+    max(pce.occurred_at)::timestamptz as last_watered_at
+  from plant_care_events as pce
+  join users_plants as up on pce.users_plant_id = up.id
+  where
+    up.user_id = $1 and
+    pce.event_type = 'watered' and
+    pce.deleted_at is null
+  group by pce.users_plant_id
+)
+select
+  up.id as users_plant_id,
+  up.name as plant_name,
+  up.adoption_date,
+  up.created_at,
+  ps.id as plant_species_id,
+  ps.species_name,
+  wn.plant_type as water_need_type,
+  wn.dry_soil_mm as water_need_dry_soil_mm,
+  wn.dry_soil_days as water_need_dry_soil_days,
+  up.water_interval_days,
+  lw.last_watered_at
+from
+  users_plants as up
+join
+  plant_species as ps on up.plant_id = ps.id
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+left join
+  last_watering as lw on up.id = lw.users_plant_id
+where
+  up.user_id = $1 and
+  up.deleted_at is null
+order by up.created_at desc;
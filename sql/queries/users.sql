@@ -21,6 +21,21 @@ set
 where
   id = $1;
 
+-- name: SetUserEmailVerifiedByID :exec
+update users
+set
+  email_verified_at = coalesce(email_verified_at, now()),
+  updated_at = now(),
+  updated_by = $1
+where
+  id = $1;
+
+-- name: IsUserEmailVerifiedByID :one
+select (email_verified_at is not null) as email_verified
+from users
+  where id = $1
+  and deleted_at is null;
+
 -- name: PromoteUserToAdminByID :exec
 update users
 set
@@ -45,7 +60,7 @@ select
   created_by, updated_by,
   lang_code_pref, join_date, is_admin, email
 from users
-  where email like $1
+  where lower(email) = lower($1)
   and deleted_at is null
   limit 1;
 
@@ -61,7 +76,7 @@ from users
 
 -- name: GetUserByEmailWithPassword :one
 select * from users
-  where email like $1
+  where lower(email) = lower($1)
   and deleted_at is null
   limit 1;
 
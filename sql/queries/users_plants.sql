@@ -27,60 +27,127 @@ from
 join
   plant_species as ps on iup.plant_id = ps.id;
 
--- name: UpdateUsersPlantByID :exec
+-- name: UpdateUsersPlantByID :execrows
 update users_plants
 set updated_at = now(),
   updated_by = $2,
   adoption_date = $3,
   name = $4
 where id = $1
+  and user_id = $2
   and deleted_at is null;
 
 -- name: GetAllUsersPlantsOrderedByUpdated :many
 with users_plant as (
   select
-    id, plant_id, adoption_date, name, updated_at
+    id, plant_id, adoption_date, name, created_at, updated_at,
+    water_interval_days, light_needs_id, fertilizer_interval_days,
+    location_id
   from users_plants
   where
     deleted_at is null and
-    user_id = $1
+    user_id = sqlc.arg('user_id') and
+    (sqlc.narg('location_id')::uuid is null or location_id = sqlc.narg('location_id')::uuid)
 )
 select
   up.id as users_plant_id,
   up.adoption_date,
   up.name as plant_name,
+  up.created_at,
   up.updated_at,
   ps.id as plant_species_id,
-  ps.species_name
+  ps.species_name,
+  up.water_interval_days,
+  up.light_needs_id,
+  up.fertilizer_interval_days,
+  wn.dry_soil_days as species_dry_soil_days,
+  wn.dry_soil_mm as species_dry_soil_mm,
+  ps.light_needs_id as species_light_needs_id,
+  ln.name as light_need_name,
+  up.location_id,
+  loc.name as location_name,
+  loc.min_temperature_celsius as location_min_temperature_celsius,
+  loc.max_temperature_celsius as location_max_temperature_celsius,
+  loc.min_humidity_percent as location_min_humidity_percent,
+  loc.max_humidity_percent as location_max_humidity_percent,
+  pt.min_temperature_celsius as plant_type_min_temperature_celsius,
+  pt.max_temperature_celsius as plant_type_max_temperature_celsius,
+  pt.min_humidity_percent as plant_type_min_humidity_percent,
+  pt.max_humidity_percent as plant_type_max_humidity_percent
 from
   users_plant as up
 join
   plant_species as ps on up.plant_id = ps.id
-order by up.updated_at desc;
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+left join
+  light_needs as ln on coalesce(up.light_needs_id, ps.light_needs_id) = ln.id and ln.deleted_at is null
+left join
+  locations as loc on up.location_id = loc.id and loc.deleted_at is null
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
+where
+  sqlc.narg('cursor_time')::timestamptz is null or
+  (up.updated_at, up.id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+order by up.updated_at desc, up.id desc
+limit sqlc.arg('row_limit');
 
 -- name: GetAllUsersPlantsOrderedByCreated :many
 with users_plant as (
-  select 
-    id, plant_id, adoption_date, name, created_at
+  select
+    id, plant_id, adoption_date, name, created_at, updated_at,
+    water_interval_days, light_needs_id, fertilizer_interval_days,
+    location_id
   from users_plants
   where
     deleted_at is null and
-    user_id = $1
+    user_id = sqlc.arg('user_id') and
+    (sqlc.narg('location_id')::uuid is null or location_id = sqlc.narg('location_id')::uuid)
 )
 select
   up.id as users_plant_id,
   up.adoption_date,
   up.name as plant_name,
   up.created_at,
+  up.updated_at,
   ps.id as plant_species_id,
-  ps.species_name
+  ps.species_name,
+  up.water_interval_days,
+  up.light_needs_id,
+  up.fertilizer_interval_days,
+  wn.dry_soil_days as species_dry_soil_days,
+  wn.dry_soil_mm as species_dry_soil_mm,
+  ps.light_needs_id as species_light_needs_id,
+  ln.name as light_need_name,
+  up.location_id,
+  loc.name as location_name,
+  loc.min_temperature_celsius as location_min_temperature_celsius,
+  loc.max_temperature_celsius as location_max_temperature_celsius,
+  loc.min_humidity_percent as location_min_humidity_percent,
+  loc.max_humidity_percent as location_max_humidity_percent,
+  pt.min_temperature_celsius as plant_type_min_temperature_celsius,
+  pt.max_temperature_celsius as plant_type_max_temperature_celsius,
+  pt.min_humidity_percent as plant_type_min_humidity_percent,
+  pt.max_humidity_percent as plant_type_max_humidity_percent
 from
   users_plant as up
 join
   plant_species as ps on up.plant_id = ps.id
-order by up.created_at desc;
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+left join
+  light_needs as ln on coalesce(up.light_needs_id, ps.light_needs_id) = ln.id and ln.deleted_at is null
+left join
+  locations as loc on up.location_id = loc.id and loc.deleted_at is null
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
+where
+  sqlc.narg('cursor_time')::timestamptz is null or
+  (up.created_at, up.id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+order by up.created_at desc, up.id desc
+limit sqlc.arg('row_limit');
 
--- name: DeleteUsersPlantByID :exec
+-- name: DeleteUsersPlantByID :execrows
 update users_plants
 set
   deleted_at = now(),
@@ -88,12 +155,15 @@ set
   updated_at = now(),
   updated_by = $2
 where id = $1
+  and user_id = $2
   and deleted_at is null;
 
 -- name: GetUsersPlantByID :one
 with users_plant as (
   select 
-    id, plant_id, adoption_date, name, created_at
+    id, plant_id, adoption_date, name, created_at,
+    water_interval_days, light_needs_id, fertilizer_interval_days,
+    location_id
   from users_plants
   where
     deleted_at is null and
@@ -106,10 +176,63 @@ select
   up.name as plant_name,
   up.created_at,
   ps.id as plant_species_id,
-  ps.species_name
+  ps.species_name,
+  up.water_interval_days,
+  up.light_needs_id,
+  up.fertilizer_interval_days,
+  wn.dry_soil_days as species_dry_soil_days,
+  wn.dry_soil_mm as species_dry_soil_mm,
+  ps.light_needs_id as species_light_needs_id,
+  ln.name as light_need_name,
+  up.location_id,
+  loc.name as location_name,
+  loc.min_temperature_celsius as location_min_temperature_celsius,
+  loc.max_temperature_celsius as location_max_temperature_celsius,
+  loc.min_humidity_percent as location_min_humidity_percent,
+  loc.max_humidity_percent as location_max_humidity_percent,
+  pt.min_temperature_celsius as plant_type_min_temperature_celsius,
+  pt.max_temperature_celsius as plant_type_max_temperature_celsius,
+  pt.min_humidity_percent as plant_type_min_humidity_percent,
+  pt.max_humidity_percent as plant_type_max_humidity_percent
 from
   users_plant as up
 join
   plant_species as ps on up.plant_id = ps.id
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+left join
+  light_needs as ln on coalesce(up.light_needs_id, ps.light_needs_id) = ln.id and ln.deleted_at is null
+left join
+  locations as loc on up.location_id = loc.id and loc.deleted_at is null
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
 order by up.created_at desc
 limit 1;
+
+-- name: UpdateUsersPlantCareByID :execrows
+update users_plants
+set updated_at = now(),
+  updated_by = $2,
+  water_interval_days = $3,
+  light_needs_id = $4,
+  fertilizer_interval_days = $5
+where id = $1
+  and user_id = $2
+  and deleted_at is null;
+
+-- name: UnassignUsersPlantsFromLocation :exec
+update users_plants
+set updated_at = now(),
+  updated_by = $2,
+  location_id = null
+where location_id = $1
+  and deleted_at is null;
+
+-- name: UpdateUsersPlantLocationByID :execrows
+update users_plants
+set updated_at = now(),
+  updated_by = $2,
+  location_id = $3
+where id = $1
+  and user_id = $2
+  and deleted_at is null;
@@ -1,3 +1,82 @@
+-- name: GetAllViewPlantsOrderedByCreated :many
+select
+  ps.id as plant_species_id,
+  ps.species_name as plant_species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  nullif(max(pn.lang_code), '') as lang_code,
+  nullif(string_agg(pn.common_name, ', '), '') as common_names,
+  pt.name as plant_type_name,
+  pt.description as plant_type_description,
+  ln.name as light_need_name,
+  ln.description as light_need_description,
+  wn.plant_type as water_need_type,
+  wn.description as water_need_description,
+  wn.dry_soil_mm as water_need_dry_soil_mm,
+  wn.dry_soil_days as water_need_dry_soil_days,
+  ps.created_at,
+  ps.updated_at
+from
+  plant_species as ps
+left join
+  plant_names as pn on ps.id = pn.plant_id
+left join
+  plant_types as pt on ps.plant_type_id = pt.id
+left join
+  light_needs as ln on ps.light_needs_id = ln.id
+left join
+  water_needs as wn on ps.water_needs_id = wn.id
+where
+  (pn.lang_code = sqlc.arg('lang_code') or pn.lang_code is null) and
+  ps.deleted_at is null and
+  pn.deleted_at is null and
+  ln.deleted_at is null and
+  wn.deleted_at is null and
+  -- optional catalog filters, a null filter matches every plant
+  (sqlc.narg('pet_safe')::boolean is null or ps.pet_poison_toxic = not sqlc.narg('pet_safe')::boolean) and
+  (sqlc.narg('human_safe')::boolean is null or ps.human_poison_toxic = not sqlc.narg('human_safe')::boolean) and
+  (sqlc.narg('pet_edible')::boolean is null or ps.pet_edible = sqlc.narg('pet_edible')::boolean) and
+  (sqlc.narg('human_edible')::boolean is null or ps.human_edible = sqlc.narg('human_edible')::boolean) and
+  (sqlc.narg('plant_type')::text is null or lower(pt.name) = lower(sqlc.narg('plant_type')::text)) and
+  (sqlc.narg('light_need')::text is null or lower(ln.name) = lower(sqlc.narg('light_need')::text)) and
+  (
+    sqlc.narg('water_model')::text is null or
+    (sqlc.narg('water_model')::text = 'days' and wn.dry_soil_days is not null) or
+    (sqlc.narg('water_model')::text = 'mm' and wn.dry_soil_mm is not null)
+  ) and
+  -- the plant type's range overlaps the requested range when each starts before the other ends
+  (sqlc.narg('min_temperature_celsius')::integer is null or pt.max_temperature_celsius >= sqlc.narg('min_temperature_celsius')::integer) and
+  (sqlc.narg('max_temperature_celsius')::integer is null or pt.min_temperature_celsius <= sqlc.narg('max_temperature_celsius')::integer) and
+  (sqlc.narg('min_humidity_percent')::integer is null or pt.max_humidity_percent >= sqlc.narg('min_humidity_percent')::integer) and
+  (sqlc.narg('max_humidity_percent')::integer is null or pt.min_humidity_percent <= sqlc.narg('max_humidity_percent')::integer) and
+  (
+    sqlc.narg('cursor_time')::timestamptz is null or
+    (ps.created_at, ps.id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+  )
+group by
+  ps.id,
+  ps.species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  pt.name,
+  pt.description,
+  ln.name,
+  ln.description,
+  wn.plant_type,
+  wn.description,
+  wn.dry_soil_mm,
+  wn.dry_soil_days,
+  ps.created_at,
+  ps.updated_at
+order by
+  ps.created_at desc,
+  ps.id desc
+limit sqlc.arg('row_limit');
+
 -- name: GetAllViewPlantsOrderedByUpdated :many
 select
   ps.id as plant_species_id,
@@ -15,7 +94,9 @@ select
   wn.plant_type as water_need_type,
   wn.description as water_need_description,
   wn.dry_soil_mm as water_need_dry_soil_mm,
-  wn.dry_soil_days as water_need_dry_soil_days
+  wn.dry_soil_days as water_need_dry_soil_days,
+  ps.created_at,
+  ps.updated_at
 from
   plant_species as ps
 left join
@@ -27,11 +108,182 @@ left join
 left join
   water_needs as wn on ps.water_needs_id = wn.id
 where
-  (pn.lang_code = $1 or pn.lang_code is null) and
+  (pn.lang_code = sqlc.arg('lang_code') or pn.lang_code is null) and
   ps.deleted_at is null and
   pn.deleted_at is null and
   ln.deleted_at is null and
-  wn.deleted_at is null
+  wn.deleted_at is null and
+  -- optional catalog filters, a null filter matches every plant
+  (sqlc.narg('pet_safe')::boolean is null or ps.pet_poison_toxic = not sqlc.narg('pet_safe')::boolean) and
+  (sqlc.narg('human_safe')::boolean is null or ps.human_poison_toxic = not sqlc.narg('human_safe')::boolean) and
+  (sqlc.narg('pet_edible')::boolean is null or ps.pet_edible = sqlc.narg('pet_edible')::boolean) and
+  (sqlc.narg('human_edible')::boolean is null or ps.human_edible = sqlc.narg('human_edible')::boolean) and
+  (sqlc.narg('plant_type')::text is null or lower(pt.name) = lower(sqlc.narg('plant_type')::text)) and
+  (sqlc.narg('light_need')::text is null or lower(ln.name) = lower(sqlc.narg('light_need')::text)) and
+  (
+    sqlc.narg('water_model')::text is null or
+    (sqlc.narg('water_model')::text = 'days' and wn.dry_soil_days is not null) or
+    (sqlc.narg('water_model')::text = 'mm' and wn.dry_soil_mm is not null)
+  ) and
+  -- the plant type's range overlaps the requested range when each starts before the other ends
+  (sqlc.narg('min_temperature_celsius')::integer is null or pt.max_temperature_celsius >= sqlc.narg('min_temperature_celsius')::integer) and
+  (sqlc.narg('max_temperature_celsius')::integer is null or pt.min_temperature_celsius <= sqlc.narg('max_temperature_celsius')::integer) and
+  (sqlc.narg('min_humidity_percent')::integer is null or pt.max_humidity_percent >= sqlc.narg('min_humidity_percent')::integer) and
+  (sqlc.narg('max_humidity_percent')::integer is null or pt.min_humidity_percent <= sqlc.narg('max_humidity_percent')::integer) and
+  (
+    sqlc.narg('cursor_time')::timestamptz is null or
+    (ps.updated_at, ps.id) < (sqlc.narg('cursor_time')::timestamptz, sqlc.narg('cursor_id')::uuid)
+  )
+group by
+  ps.id,
+  ps.species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  pt.name,
+  pt.description,
+  ln.name,
+  ln.description,
+  wn.plant_type,
+  wn.description,
+  wn.dry_soil_mm,
+  wn.dry_soil_days,
+  ps.created_at,
+  ps.updated_at
+order by
+  ps.updated_at desc,
+  ps.id desc
+limit sqlc.arg('row_limit');
+
+-- name: GetUsersPlantSpeciesDetailsByID :one
+select
+  ps.id as plant_species_id,
+  ps.species_name as plant_species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  u.lang_code_pref as lang_code,
+  (
+    select
+      string_agg(pn.common_name, ', ')
+    from plant_names as pn
+    where
+      pn.plant_id = ps.id and
+      pn.lang_code = u.lang_code_pref and
+      pn.deleted_at is null
+  ) as common_names,
+  pt.name as plant_type_name,
+  pt.description as plant_type_description,
+  pt.max_temperature_celsius,
+  pt.min_temperature_celsius,
+  pt.max_humidity_percent,
+  pt.min_humidity_percent,
+  pt.soil_organic_mix,
+  pt.soil_grit_mix,
+  pt.soil_drainage_mix,
+  ln.name as light_need_name,
+  ln.description as light_need_description,
+  wn.plant_type as water_need_type,
+  wn.description as water_need_description,
+  wn.dry_soil_mm as water_need_dry_soil_mm,
+  wn.dry_soil_days as water_need_dry_soil_days
+from
+  users_plants as up
+join
+  users as u on up.user_id = u.id
+join
+  plant_species as ps on up.plant_id = ps.id
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
+left join
+  light_needs as ln on ps.light_needs_id = ln.id and ln.deleted_at is null
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+where
+  up.user_id = $1 and
+  up.id = $2 and
+  up.deleted_at is null;
+
+-- name: SearchViewPlants :many
+with name_matches as (
+  -- species names and the common names in the requested language are both searched,
+  -- each table is matched with the expressions of its indexes from 013_plant_search.sql.
+  -- prefix matches use ilike, which the trigram indexes support, with the wildcards in the query escaped
+  select
+    ps.id as plant_id,
+    ps.species_name as matched_name
+  from plant_species as ps
+  where
+    ps.deleted_at is null and (
+      ps.species_name ilike replace(replace(replace(sqlc.arg('query')::text, '\', '\\'), '%', '\%'), '_', '\_') || '%' or
+      to_tsvector('simple', ps.species_name) @@ plainto_tsquery('simple', sqlc.arg('query')::text) or
+      sqlc.arg('query')::text <% ps.species_name
+    )
+  union all
+  select
+    pn.plant_id,
+    pn.common_name as matched_name
+  from plant_names as pn
+  where
+    pn.lang_code = sqlc.arg('lang_code')::text and
+    pn.common_name is not null and
+    pn.deleted_at is null and (
+      pn.common_name ilike replace(replace(replace(sqlc.arg('query')::text, '\', '\\'), '%', '\%'), '_', '\_') || '%' or
+      to_tsvector('simple', coalesce(pn.common_name, '')) @@ plainto_tsquery('simple', sqlc.arg('query')::text) or
+      sqlc.arg('query')::text <% pn.common_name
+    )
+),
+-- exact matches rank first, then prefix matches, then full text matches, then fuzzy matches
+ranked_matches as (
+  select
+    plant_id,
+    min(
+      case
+        when lower(matched_name) = lower(sqlc.arg('query')::text) then 0
+        when starts_with(lower(matched_name), lower(sqlc.arg('query')::text)) then 1
+        when to_tsvector('simple', matched_name) @@ plainto_tsquery('simple', sqlc.arg('query')::text) then 2
+        else 3
+      end
+    ) as match_rank,
+    max(word_similarity(sqlc.arg('query')::text, matched_name)) as match_similarity
+  from name_matches
+  group by plant_id
+)
+select
+  ps.id as plant_species_id,
+  ps.species_name as plant_species_name,
+  ps.human_poison_toxic,
+  ps.pet_poison_toxic,
+  ps.human_edible,
+  ps.pet_edible,
+  nullif(max(pn.lang_code), '') as lang_code,
+  nullif(string_agg(pn.common_name, ', '), '') as common_names,
+  pt.name as plant_type_name,
+  pt.description as plant_type_description,
+  ln.name as light_need_name,
+  ln.description as light_need_description,
+  wn.plant_type as water_need_type,
+  wn.description as water_need_description,
+  wn.dry_soil_mm as water_need_dry_soil_mm,
+  wn.dry_soil_days as water_need_dry_soil_days,
+  ps.created_at,
+  ps.updated_at
+from
+  ranked_matches as rm
+join
+  plant_species as ps on rm.plant_id = ps.id
+left join
+  plant_names as pn on ps.id = pn.plant_id and pn.lang_code = sqlc.arg('lang_code')::text and pn.deleted_at is null
+left join
+  plant_types as pt on ps.plant_type_id = pt.id and pt.deleted_at is null
+left join
+  light_needs as ln on ps.light_needs_id = ln.id and ln.deleted_at is null
+left join
+  water_needs as wn on ps.water_needs_id = wn.id and wn.deleted_at is null
+where
+  ps.deleted_at is null
 group by
   ps.id,
   ps.species_name,
@@ -46,6 +298,13 @@ group by
   wn.plant_type,
   wn.description,
   wn.dry_soil_mm,
-  wn.dry_soil_days
+  wn.dry_soil_days,
+  ps.created_at,
+  ps.updated_at,
+  rm.match_rank,
+  rm.match_similarity
 order by
-  ps.updated_at desc;
+  rm.match_rank asc,
+  rm.match_similarity desc,
+  ps.species_name asc
+limit sqlc.arg('row_limit');
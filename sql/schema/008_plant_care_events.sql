@@ -0,0 +1,36 @@
+-- +goose Up
+create table plant_care_events (
+  id uuid primary key,
+  created_at timestamp with time zone not null,
+  updated_at timestamp with time zone not null,
+  deleted_at timestamp with time zone,
+  --
+  created_by uuid not null,
+  updated_by uuid not null,
+  deleted_by uuid,
+  --
+  -- foreign keys
+  users_plant_id uuid not null,
+  user_id uuid not null,
+  --
+  -- table data
+  -- event type is one of 'watered', 'fertilized', 'misted', 'repotted'
+  event_type text not null,
+  occurred_at timestamp with time zone not null,
+  notes text
+);
+
+alter table plant_care_events
+  add constraint fk_users_plants
+  foreign key (users_plant_id)
+  references users_plants(id)
+  on delete cascade;
+
+alter table plant_care_events
+  add constraint fk_users
+  foreign key (user_id)
+  references users(id)
+  on delete cascade;
+
+-- +goose Down
+drop table plant_care_events;
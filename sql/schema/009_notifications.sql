@@ -0,0 +1,57 @@
+-- +goose Up
+create table notification_settings (
+  user_id uuid primary key,
+  created_at timestamp with time zone not null,
+  updated_at timestamp with time zone not null,
+  deleted_at timestamp with time zone,
+  --
+  created_by uuid not null,
+  updated_by uuid not null,
+  deleted_by uuid,
+  --
+  -- table data
+  -- quiet hours are whole hours (0-23) in the users timezone
+  -- reminders are held back from start until end
+  quiet_hours_start integer,
+  quiet_hours_end integer,
+  timezone text not null default 'UTC',
+  last_reminded_at timestamp with time zone,
+  --
+  -- table foreign key
+  constraint fk_users
+  foreign key (user_id)
+  references users(id)
+  on delete cascade
+);
+
+create table notification_channels (
+  id uuid primary key,
+  created_at timestamp with time zone not null,
+  updated_at timestamp with time zone not null,
+  deleted_at timestamp with time zone,
+  --
+  created_by uuid not null,
+  updated_by uuid not null,
+  deleted_by uuid,
+  --
+  -- foreign keys
+  user_id uuid not null,
+  --
+  -- table data
+  -- channel type is one of 'email', 'webhook', 'log'
+  -- target is the email address or webhook url
+  channel_type text not null,
+  target text not null,
+  enabled boolean not null
+);
+
+alter table notification_channels
+  add constraint fk_users
+  foreign key (user_id)
+  references users(id)
+  on delete cascade;
+
+-- +goose Down
+drop table notification_channels;
+
+drop table notification_settings;
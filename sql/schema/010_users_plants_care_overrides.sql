@@ -0,0 +1,21 @@
+-- +goose Up
+-- per plant overrides of the species care defaults
+-- null means the species default (water_needs / light_needs) applies
+alter table users_plants
+  add column water_interval_days integer,
+  add column light_needs_id uuid,
+  add column fertilizer_interval_days integer;
+
+alter table users_plants
+  add constraint fk_light_needs
+  foreign key (light_needs_id)
+  references light_needs(id);
+
+-- +goose Down
+alter table users_plants
+  drop constraint fk_light_needs;
+
+alter table users_plants
+  drop column fertilizer_interval_days,
+  drop column light_needs_id,
+  drop column water_interval_days;
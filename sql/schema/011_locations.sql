@@ -0,0 +1,47 @@
+-- +goose Up
+create table locations (
+  id uuid primary key,
+  created_at timestamp with time zone not null,
+  updated_at timestamp with time zone not null,
+  deleted_at timestamp with time zone,
+  --
+  created_by uuid not null,
+  updated_by uuid not null,
+  deleted_by uuid,
+  --
+  -- foreign keys
+  user_id uuid not null,
+  --
+  -- table data
+  name text not null,
+  light_exposure text,
+  -- typical environment of the location
+  min_temperature_celsius integer,
+  max_temperature_celsius integer,
+  min_humidity_percent integer,
+  max_humidity_percent integer
+);
+
+alter table locations
+  add constraint fk_users
+  foreign key (user_id)
+  references users(id)
+  on delete cascade;
+
+alter table users_plants
+  add column location_id uuid;
+
+alter table users_plants
+  add constraint fk_locations
+  foreign key (location_id)
+  references locations(id)
+  on delete set null;
+
+-- +goose Down
+alter table users_plants
+  drop constraint fk_locations;
+
+alter table users_plants
+  drop column location_id;
+
+drop table locations;
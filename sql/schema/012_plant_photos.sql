@@ -0,0 +1,39 @@
+-- +goose Up
+create table plant_photos (
+  id uuid primary key,
+  created_at timestamp with time zone not null,
+  updated_at timestamp with time zone not null,
+  deleted_at timestamp with time zone,
+  --
+  created_by uuid not null,
+  updated_by uuid not null,
+  deleted_by uuid,
+  --
+  -- foreign keys
+  users_plant_id uuid not null,
+  user_id uuid not null,
+  --
+  -- table data
+  content_type text not null,
+  size_bytes bigint not null,
+  width integer not null,
+  height integer not null,
+  -- keys of the image and its thumbnail in the blob store
+  blob_key text not null,
+  thumbnail_key text not null
+);
+
+alter table plant_photos
+  add constraint fk_users_plants
+  foreign key (users_plant_id)
+  references users_plants(id)
+  on delete cascade;
+
+alter table plant_photos
+  add constraint fk_users
+  foreign key (user_id)
+  references users(id)
+  on delete cascade;
+
+-- +goose Down
+drop table plant_photos;
@@ -0,0 +1,28 @@
+-- +goose Up
+create extension if not exists pg_trgm;
+
+-- trigram indexes for fuzzy matching of misspelled names
+create index plant_species_species_name_trgm_idx
+  on plant_species
+  using gin (species_name gin_trgm_ops);
+
+create index plant_names_common_name_trgm_idx
+  on plant_names
+  using gin (common_name gin_trgm_ops);
+
+-- full text indexes for matching names word by word
+create index plant_species_species_name_fts_idx
+  on plant_species
+  using gin (to_tsvector('simple', species_name));
+
+create index plant_names_common_name_fts_idx
+  on plant_names
+  using gin (to_tsvector('simple', coalesce(common_name, '')));
+
+-- +goose Down
+drop index plant_names_common_name_fts_idx;
+drop index plant_species_species_name_fts_idx;
+drop index plant_names_common_name_trgm_idx;
+drop index plant_species_species_name_trgm_idx;
+
+drop extension if exists pg_trgm;
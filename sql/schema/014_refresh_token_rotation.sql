@@ -0,0 +1,33 @@
+-- +goose Up
+-- tokens are stored as the hex encoded sha-256 of the token,
+-- hashing the existing ones keeps them valid
+alter table refresh_tokens rename column refresh_token to token_hash;
+update refresh_tokens
+set token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
+
+-- every login starts a family of tokens, each refresh replaces the token with the next in the family
+alter table refresh_tokens
+  add column family_id uuid,
+  add column replaced_by text;
+update refresh_tokens
+set family_id = gen_random_uuid();
+alter table refresh_tokens
+  alter column family_id set not null;
+
+create index refresh_tokens_family_id_idx
+  on refresh_tokens (family_id);
+
+create index refresh_tokens_expires_at_idx
+  on refresh_tokens (expires_at);
+
+-- +goose Down
+-- hashed tokens can not be turned back into tokens, so users log in again
+delete from refresh_tokens;
+
+drop index refresh_tokens_expires_at_idx;
+drop index refresh_tokens_family_id_idx;
+
+alter table refresh_tokens
+  drop column replaced_by,
+  drop column family_id;
+alter table refresh_tokens rename column token_hash to refresh_token;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- each token family is a session, its newest token records the device that last used it
+alter table refresh_tokens
+  add column user_agent text,
+  add column ip_address text,
+  add column last_used_at timestamp with time zone;
+
+-- +goose Down
+alter table refresh_tokens
+  drop column last_used_at,
+  drop column ip_address,
+  drop column user_agent;
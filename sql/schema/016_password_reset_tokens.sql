@@ -0,0 +1,23 @@
+-- +goose Up
+-- tokens are stored as the hex encoded sha-256 of the token, like refresh tokens.
+-- a user has at most one reset token, it is deleted once it is used or replaced by a newer one
+create table password_reset_tokens (
+  token_hash text primary key,
+  created_at timestamp with time zone not null,
+  expires_at timestamp with time zone not null,
+  --
+  -- foreign keys
+  user_id uuid not null
+);
+
+alter table password_reset_tokens
+  add constraint fk_users
+  foreign key (user_id)
+  references users(id)
+  on delete cascade;
+
+create index password_reset_tokens_user_id_idx
+  on password_reset_tokens (user_id);
+
+-- +goose Down
+drop table password_reset_tokens;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- emails are stored trimmed and in lower case, and matched exactly.
+-- this fails when two accounts share an address in different cases, they have to be merged by hand first
+update users
+set email = lower(trim(email))
+where email <> lower(trim(email));
+
+create unique index users_email_unique_idx
+  on users (lower(email))
+  where deleted_at is null;
+
+-- accounts from before verification start out unverified, they can request a verification email
+alter table users
+  add column email_verified_at timestamp with time zone;
+
+-- tokens are stored as the hex encoded sha-256 of the token, like password reset tokens.
+-- a user has at most one verification token, it is deleted once it is used or replaced by a newer one
+create table email_verification_tokens (
+  token_hash text primary key,
+  created_at timestamp with time zone not null,
+  expires_at timestamp with time zone not null,
+  --
+  -- foreign keys
+  user_id uuid not null
+);
+
+alter table email_verification_tokens
+  add constraint fk_users
+  foreign key (user_id)
+  references users(id)
+  on delete cascade;
+
+create index email_verification_tokens_user_id_idx
+  on email_verification_tokens (user_id);
+
+-- +goose Down
+drop table email_verification_tokens;
+
+alter table users
+  drop column email_verified_at;
+
+drop index users_email_unique_idx;
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nicholasss/plantae/internal/database"
 )
 
+// largest catalog archive accepted by the import endpoint
+const catalogImportMaxBytes = 64 << 20
+
 // === request response types ===
 
 // AdminStatusRequest is for promotion or demotion requests of a user, from an super-admin.
@@ -29,7 +35,7 @@ type AdminStatusResponse struct {
 func (cfg *apiConfig) resetPlantTypesHandler(w http.ResponseWriter, r *http.Request) {
 	// super-admin pre-authenticated before the handler is used
 	if platformProduction(cfg) {
-		cfg.sl.Debug("Unable to reset plant_types table due to wrong platform", "platform", cfg.platform)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset plant_types table due to wrong platform", "platform", cfg.platform)
 		respondWithError(nil, http.StatusForbidden, w, cfg.sl)
 		return
 	}
@@ -37,12 +43,12 @@ func (cfg *apiConfig) resetPlantTypesHandler(w http.ResponseWriter, r *http.Requ
 	// drop records from plant types table
 	err := cfg.db.ResetPlantTypesTable(r.Context())
 	if err != nil {
-		cfg.sl.Debug("Unable to reset plant_types table", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset plant_types table", "error", err)
 		respondWithError(nil, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Info("Reset plant_types table successfully")
+	cfg.sl.InfoContext(r.Context(), "Reset plant_types table successfully")
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -54,7 +60,7 @@ func (cfg *apiConfig) resetPlantTypesHandler(w http.ResponseWriter, r *http.Requ
 func (cfg *apiConfig) resetLightNeedsHandler(w http.ResponseWriter, r *http.Request) {
 	// super-admin pre-authenticated before the handler is used
 	if platformProduction(cfg) {
-		cfg.sl.Debug("Unable to reset light_needs table due to wrong platform", "platform", cfg.platform)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset light_needs table due to wrong platform", "platform", cfg.platform)
 		respondWithError(nil, http.StatusForbidden, w, cfg.sl)
 		return
 	}
@@ -62,12 +68,12 @@ func (cfg *apiConfig) resetLightNeedsHandler(w http.ResponseWriter, r *http.Requ
 	// drop records from db
 	err := cfg.db.ResetLightNeedsTable(r.Context())
 	if err != nil {
-		cfg.sl.Debug("Unable to reset light_needs table", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset light_needs table", "error", err)
 		respondWithError(nil, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Info("Reset light_needs table successfully")
+	cfg.sl.InfoContext(r.Context(), "Reset light_needs table successfully")
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -79,7 +85,7 @@ func (cfg *apiConfig) resetLightNeedsHandler(w http.ResponseWriter, r *http.Requ
 func (cfg *apiConfig) resetWaterNeedsHandler(w http.ResponseWriter, r *http.Request) {
 	// super-admin pre-authenticated before the handler is used
 	if platformProduction(cfg) {
-		cfg.sl.Debug("Unable to reset water_needs table due to wrong platform", "platform", cfg.platform)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset water_needs table due to wrong platform", "platform", cfg.platform)
 		respondWithError(nil, http.StatusForbidden, w, cfg.sl)
 		return
 	}
@@ -87,12 +93,12 @@ func (cfg *apiConfig) resetWaterNeedsHandler(w http.ResponseWriter, r *http.Requ
 	// drop records from db
 	err := cfg.db.ResetWaterNeedsTable(r.Context())
 	if err != nil {
-		cfg.sl.Debug("Unable to reset water_needs table", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset water_needs table", "error", err)
 		respondWithError(nil, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Info("Reset water_needs table successfully")
+	cfg.sl.InfoContext(r.Context(), "Reset water_needs table successfully")
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -104,7 +110,7 @@ func (cfg *apiConfig) resetWaterNeedsHandler(w http.ResponseWriter, r *http.Requ
 func (cfg *apiConfig) resetPlantSpeciesHandler(w http.ResponseWriter, r *http.Request) {
 	// super-admin pre-authenticated before the handler is used
 	if platformProduction(cfg) {
-		cfg.sl.Debug("Unable to reset plant_species table due to wrong platform", "platform", cfg.platform)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset plant_species table due to wrong platform", "platform", cfg.platform)
 		respondWithError(nil, http.StatusForbidden, w, cfg.sl)
 		return
 	}
@@ -112,12 +118,12 @@ func (cfg *apiConfig) resetPlantSpeciesHandler(w http.ResponseWriter, r *http.Re
 	// drop records from db
 	err := cfg.db.ResetPlantSpeciesTable(r.Context())
 	if err != nil {
-		cfg.sl.Debug("Unable to reset plant_species table", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset plant_species table", "error", err)
 		respondWithError(nil, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Info("Reset plant_species table successfully")
+	cfg.sl.InfoContext(r.Context(), "Reset plant_species table successfully")
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -130,19 +136,19 @@ func (cfg *apiConfig) resetPlantNamesHandler(w http.ResponseWriter, r *http.Requ
 	// super-admin pre-authenticated before the handler is used
 	// ensure development platform
 	if platformProduction(cfg) {
-		cfg.sl.Debug("Unable to reset plant_names table due to wrong platform", "platform", cfg.platform)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset plant_names table due to wrong platform", "platform", cfg.platform)
 		respondWithError(nil, http.StatusForbidden, w, cfg.sl)
 		return
 	}
 
 	err := cfg.db.ResetPlantNamesTable(r.Context())
 	if err != nil {
-		cfg.sl.Debug("Unable to reset plant_names table", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset plant_names table", "error", err)
 		respondWithError(nil, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Info("Reset plant_names table successfully")
+	cfg.sl.InfoContext(r.Context(), "Reset plant_names table successfully")
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -155,7 +161,7 @@ func (cfg *apiConfig) resetUsersHandler(w http.ResponseWriter, r *http.Request)
 	// super-admin pre-authenticated before the handler is used
 	// ensure development platform
 	if platformProduction(cfg) {
-		cfg.sl.Debug("Unable to reset users table due to wrong platform", "platform", cfg.platform)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset users table due to wrong platform", "platform", cfg.platform)
 		respondWithError(nil, http.StatusForbidden, w, cfg.sl)
 		return
 	}
@@ -163,12 +169,12 @@ func (cfg *apiConfig) resetUsersHandler(w http.ResponseWriter, r *http.Request)
 	// drop records from db
 	err := cfg.db.ResetUsersTable(r.Context())
 	if err != nil {
-		cfg.sl.Debug("Unable to reset users table", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to reset users table", "error", err)
 		respondWithError(nil, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	cfg.sl.Info("Reset users table successfully")
+	cfg.sl.InfoContext(r.Context(), "Reset users table successfully")
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -212,7 +218,7 @@ func (cfg *apiConfig) promoteUserToAdminHandler(w http.ResponseWriter, r *http.R
 		IsAdmin: true,
 	}
 
-	cfg.sl.Info("Successfully promoted user to admin", "user id", userRecord.ID)
+	cfg.sl.InfoContext(r.Context(), "Successfully promoted user to admin", "user id", userRecord.ID)
 	respondWithJSON(http.StatusOK, adminResponse, w, cfg.sl)
 }
 
@@ -256,6 +262,125 @@ func (cfg *apiConfig) demoteUserToAdminHandler(w http.ResponseWriter, r *http.Re
 		IsAdmin: false,
 	}
 
-	cfg.sl.Info("Successfully demoted user from admin", "user id", userRecord.ID)
+	cfg.sl.InfoContext(r.Context(), "Successfully demoted user from admin", "user id", userRecord.ID)
 	respondWithJSON(http.StatusOK, adminResponse, w, cfg.sl)
 }
+
+// === Catalog Backup Handlers ===
+
+// exports the universal plant data as a versioned json archive
+// GET /api/v1/super-admin/export
+// 200 OK makes sense in context
+func (cfg *apiConfig) exportCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	// super-admin pre-authenticated before the handler is used
+	// read from a single snapshot, so links between tables stay consistent
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to begin export transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+
+	exportedAt := time.Now().UTC()
+	filename := "plantae-catalog-" + exportedAt.Format("20060102T150405Z") + ".json"
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	// each table is sent as soon as it is read, instead of buffering the whole archive
+	controller := http.NewResponseController(w)
+	flush := func() error {
+		err := controller.Flush()
+		if errors.Is(err, http.ErrNotSupported) {
+			return nil
+		}
+		return err
+	}
+
+	records, err := writeCatalogArchive(r.Context(), cfg.db.WithTx(tx), w, flush, exportedAt)
+	if err != nil {
+		// the status is already sent, the client sees the archive cut short
+		cfg.sl.WarnContext(r.Context(), "Unable to export catalog", "error", err, "records written", records)
+		return
+	}
+
+	cfg.sl.InfoContext(r.Context(), "Exported catalog successfully", "records", records)
+}
+
+// restores the universal plant data from an archive created by the export endpoint
+// POST /api/v1/super-admin/import?conflict=fail|skip|overwrite&dryRun=true
+// the whole archive is imported in one transaction, a dry run rolls it back after reporting
+// 200 OK makes sense in context
+func (cfg *apiConfig) importCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	// super-admin pre-authenticated before the handler is used
+	conflict := r.URL.Query().Get("conflict")
+	if conflict == "" {
+		conflict = catalogConflictFail
+	}
+	if conflict != catalogConflictFail && conflict != catalogConflictSkip && conflict != catalogConflictOverwrite {
+		cfg.sl.DebugContext(r.Context(), "Unknown conflict mode was requested", "conflict", conflict)
+		respondWithError(errors.New("conflict must be either 'fail', 'skip', or 'overwrite'"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dryRun"); dryRunStr != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Invalid dry run was requested", "dry run", dryRunStr)
+			respondWithError(errors.New("dryRun must be either 'true' or 'false'"), http.StatusBadRequest, w, cfg.sl)
+			return
+		}
+	}
+
+	var archive CatalogArchive
+	r.Body = http.MaxBytesReader(w, r.Body, catalogImportMaxBytes)
+	err := json.NewDecoder(r.Body).Decode(&archive)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode catalog archive", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	err = archive.validate()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Invalid catalog archive", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), nil)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to begin import transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+
+	report, err := importCatalogArchive(r.Context(), cfg.db.WithTx(tx), archive, conflict)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to import catalog", "error", err, "conflict", conflict)
+		if catalogImportRejected(err) {
+			respondWithError(err, http.StatusConflict, w, cfg.sl)
+			return
+		}
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	report.DryRun = dryRun
+	if !dryRun {
+		err = tx.Commit()
+		if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Unable to commit import transaction", "error", err)
+			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+			return
+		}
+	}
+
+	cfg.sl.InfoContext(r.Context(), "Imported catalog successfully", "dry run", dryRun, "conflict", conflict)
+	respondWithJSON(http.StatusOK, report, w, cfg.sl)
+}
@@ -17,11 +17,17 @@ fi
 # source .env variables
 source .env
 
+# verification and password reset emails are read back from a local smtp server with an http api, such as mailpit
+MAIL_API_URL="${MAIL_API_URL:-http://localhost:8025}"
+
 # run user tests with admin token for testing
 hurl \
   --variable craig_email=craig@gmail.com \
+  --variable craig_email_mixed_case=Craig@Gmail.com \
   --variable craig_password=@ssword472 \
+  --variable craig_new_password=n3w@ssword472 \
   --variable craig_lang_code=en \
+  --variable mail_api_url=$MAIL_API_URL \
   --secret super_admin_token=$SUPER_ADMIN_TOKEN \
   --jobs 1 \
   --test \
@@ -133,6 +139,7 @@ hurl \
   --variable lisa_email=lisa@gmail.com \
   --variable lisa_password=Growl1ng! \
   --variable lisa_lang_code=en \
+  --variable mail_api_url=$MAIL_API_URL \
   --variable 1_species_name="Pilea peperomioides" \
   --variable 1_human_poison_toxic=false \
   --variable 1_pet_poison_toxic=false \
@@ -151,6 +158,10 @@ hurl \
   --variable 2_plant_new_name="mini tree" \
   --variable 2_plant_adoption="2020-11-30T00:00:00-05:00" \
   --variable 2_plant_new_adoption="2021-11-30T00:00:00-05:00" \
+  --variable 1_event_watered_at="2021-02-01T09:00:00-05:00" \
+  --variable 1_event_fertilized_at="2021-02-02T09:00:00-05:00" \
+  --variable 1_event_notes="soil was bone dry" \
+  --variable 1_location_name="South window" \
   --secret super_admin_token=$SUPER_ADMIN_TOKEN \
   --jobs 1 \
   --test \
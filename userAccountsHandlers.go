@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -42,6 +44,7 @@ type UserLoginResponse struct {
 	LangCodePref          string    `json:"langCodePref"`
 	JoinDate              time.Time `json:"joinDate"`
 	IsAdmin               bool      `json:"isAdmin"`
+	EmailVerified         bool      `json:"emailVerified"`
 	AccessToken           string    `json:"token"`
 	AccessTokenExpiresAt  time.Time `json:"tokenExpiresAt"`
 	RefreshToken          string    `json:"refreshToken"`
@@ -50,9 +53,11 @@ type UserLoginResponse struct {
 
 // AuthRefreshResponse is for encoding user access token responses.
 type AuthRefreshResponse struct {
-	ID                   uuid.UUID `json:"id"`
-	AccessToken          string    `json:"token"`
-	AccessTokenExpiresAt time.Time `json:"tokenExpiresAt"`
+	ID                    uuid.UUID `json:"id"`
+	AccessToken           string    `json:"token"`
+	AccessTokenExpiresAt  time.Time `json:"tokenExpiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
 }
 
 // AuthRevokeRequest is for decoding user refresh token requests.
@@ -68,24 +73,30 @@ func (cfg *apiConfig) registerUserHandler(w http.ResponseWriter, r *http.Request
 	decoder := json.NewDecoder(r.Body)
 	err := decoder.Decode(&createUserRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
 	// check request params
 	if createUserRequest.Email == "" {
-		cfg.sl.Debug("Request body missing email")
+		cfg.sl.DebugContext(r.Context(), "Request body missing email")
 		respondWithError(nil, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
+	createUserRequest.Email, err = normalizeEmail(createUserRequest.Email)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Request body has invalid email", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
 	if createUserRequest.RawPassword == "" {
-		cfg.sl.Debug("Request body missing password")
+		cfg.sl.DebugContext(r.Context(), "Request body missing password")
 		respondWithError(nil, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	if createUserRequest.LangCodePref == "" {
-		cfg.sl.Debug("Request body missing language preference")
+		cfg.sl.DebugContext(r.Context(), "Request body missing language preference")
 		respondWithError(nil, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -93,17 +104,21 @@ func (cfg *apiConfig) registerUserHandler(w http.ResponseWriter, r *http.Request
 	// language preference check
 	userRequestedLangName, ok := LangCodes[createUserRequest.LangCodePref]
 	if !ok {
-		cfg.sl.Debug("Requested language code was not found", "lang code", createUserRequest.LangCodePref)
+		cfg.sl.DebugContext(r.Context(), "Requested language code was not found", "lang code", createUserRequest.LangCodePref)
 		respondWithError(errors.New("language code requested does not exist"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
-	cfg.sl.Debug("User is registering with language", "lang code", createUserRequest.LangCodePref, "lang name", userRequestedLangName)
+	cfg.sl.DebugContext(r.Context(), "User is registering with language", "lang code", createUserRequest.LangCodePref, "lang name", userRequestedLangName)
 
 	// hash password
 	hashedPassword, err := auth.HashPassword(createUserRequest.RawPassword, cfg.sl)
 	createUserRequest.RawPassword = "" // GC collection
-	if err != nil {
-		cfg.sl.Debug("Error hashing user's password", "error", err)
+	if errors.Is(err, auth.ErrPasswordTooLong) {
+		cfg.sl.DebugContext(r.Context(), "Password is too long", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Error hashing user's password", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -111,7 +126,7 @@ func (cfg *apiConfig) registerUserHandler(w http.ResponseWriter, r *http.Request
 	// user uuid generation
 	newUserUUID, err := uuid.NewUUID()
 	if err != nil {
-		cfg.sl.Debug("Unable to generate a new uuid for user", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to generate a new uuid for user", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -124,12 +139,22 @@ func (cfg *apiConfig) registerUserHandler(w http.ResponseWriter, r *http.Request
 		HashedPassword: hashedPassword,
 	}
 	userRecord, err := cfg.db.CreateUser(r.Context(), createUserParams)
-	if err != nil {
-		cfg.sl.Debug("Could not create user in database", "error", err)
+	if isUniqueViolation(err) {
+		cfg.sl.DebugContext(r.Context(), "Email is already registered")
+		respondWithError(errors.New("email is already registered"), http.StatusConflict, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not create user in database", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
+	// the account is created either way, the user can request another verification email
+	err = cfg.sendEmailVerification(r.Context(), userRecord.ID, userRecord.Email)
+	if err != nil {
+		cfg.sl.WarnContext(r.Context(), "Could not send verification email", "error", err, "user id", userRecord.ID)
+	}
+
 	createUserResponse := CreateUserResponse{
 		ID:           userRecord.ID,
 		Email:        userRecord.Email,
@@ -139,7 +164,7 @@ func (cfg *apiConfig) registerUserHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	// return the userRecord without password
-	cfg.sl.Debug("User successfully registered", "user id", userRecord.ID)
+	cfg.sl.DebugContext(r.Context(), "User successfully registered", "user id", userRecord.ID)
 	respondWithJSON(http.StatusCreated, createUserResponse, w, cfg.sl)
 }
 
@@ -147,26 +172,31 @@ func (cfg *apiConfig) registerUserHandler(w http.ResponseWriter, r *http.Request
 // POST /api/v1/auth/login
 // POST /login is an exception
 // -- it typically responds with HTTP 200 and response
-// TODO: check for prexisting token, if exists then revoke it and replace
+// each login starts a new refresh token family, so logging in on one device
+// does not log out the others
 func (cfg *apiConfig) loginHandler(w http.ResponseWriter, r *http.Request) {
 	var userLoginRequest UserLoginRequest
 	err := json.NewDecoder(r.Body).Decode(&userLoginRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
 	// ensure login items arent empty
 	if userLoginRequest.Email == "" || userLoginRequest.RawPassword == "" {
-		cfg.sl.Debug("Request body missing email or password")
+		cfg.sl.DebugContext(r.Context(), "Request body missing email or password")
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	userRecord, err := cfg.db.GetUserByEmailWithPassword(r.Context(), userLoginRequest.Email)
-	if err != nil {
-		cfg.sl.Debug("Unable to retreive user record with email", "error", err)
+	userRecord, err := cfg.db.GetUserByEmailWithPassword(r.Context(), strings.TrimSpace(userLoginRequest.Email))
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "User's login attempt failed due to unknown email")
+		respondWithError(err, http.StatusForbidden, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to retreive user record with email", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -174,54 +204,50 @@ func (cfg *apiConfig) loginHandler(w http.ResponseWriter, r *http.Request) {
 	// hash & check password
 	err = auth.CheckPasswordHash(userLoginRequest.RawPassword, userRecord.HashedPassword, cfg.sl)
 	if err != nil {
-		cfg.sl.Debug("User's login attempt failed due to mis-matching passwords", "error", err)
+		cfg.sl.DebugContext(r.Context(), "User's login attempt failed due to mis-matching passwords", "error", err)
 		respondWithError(err, http.StatusForbidden, w, cfg.sl)
 		return
 	}
 	// password checked, removing from memory
 	userLoginRequest.RawPassword = ""
 
-	// check email
-	userLoginRequest.Email = strings.ToLower(userLoginRequest.Email)
-	userRecord.Email = strings.ToLower(userRecord.Email)
-	if userRecord.Email != userLoginRequest.Email {
-		cfg.sl.Debug("User's login attempt failed due to mis-matching email", "request email", userLoginRequest.Email, "account email", userRecord)
-		cfg.sl.Warn("User record details may be mis-matching queried values")
-		respondWithError(err, http.StatusForbidden, w, cfg.sl)
-		return
-	}
-
 	// user logged in, generate tokens
-	cfg.sl.Debug("User logged in, generating new tokens")
+	cfg.sl.DebugContext(r.Context(), "User logged in, generating new tokens")
 
 	// refresh token
 	userRefreshToken, err := auth.MakeRefreshToken(cfg.sl)
 	if err != nil {
-		cfg.sl.Debug("Unable to create new refresh token for user", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to create new refresh token for user", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
-	// store userRefreshToken in database
+	// store the hash of userRefreshToken in database,
+	// its family is the session that the access tokens are issued for
+	sessionID := uuid.New()
+	userAgent, ipAddress := sessionDevice(r)
 	refreshTokenExpiresAt := time.Now().Add(cfg.refreshTokenDuration)
 	createRefreshToken := database.CreateRefreshTokenParams{
-		RefreshToken: userRefreshToken,
-		CreatedBy:    userRecord.ID,
-		ExpiresAt:    refreshTokenExpiresAt,
+		TokenHash: auth.HashRefreshToken(userRefreshToken),
+		CreatedBy: userRecord.ID,
+		ExpiresAt: refreshTokenExpiresAt,
+		FamilyID:  sessionID,
+		UserAgent: userAgent,
+		IpAddress: ipAddress,
 	}
 
 	_, err = cfg.db.CreateRefreshToken(r.Context(), createRefreshToken)
 	if err != nil {
-		cfg.sl.Warn("Unable to put a user's new refresh token into database", "error", err)
+		cfg.sl.WarnContext(r.Context(), "Unable to put a user's new refresh token into database", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
 	// access token
 	accessTokenExpiresAt := time.Now().Add(cfg.accessTokenDuration)
-	userAccessToken, err := auth.MakeJWT(userRecord.ID, cfg.JWTSecret, cfg.accessTokenDuration, cfg.sl)
+	userAccessToken, err := auth.MakeJWT(userRecord.ID, sessionID, cfg.JWTSecret, cfg.accessTokenDuration, cfg.sl)
 	if err != nil {
-		cfg.sl.Debug("Unable to create a new access token for user's login", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Unable to create a new access token for user's login", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -232,6 +258,7 @@ func (cfg *apiConfig) loginHandler(w http.ResponseWriter, r *http.Request) {
 		LangCodePref:          userRecord.LangCodePref,
 		JoinDate:              userRecord.JoinDate,
 		IsAdmin:               userRecord.IsAdmin,
+		EmailVerified:         userRecord.EmailVerifiedAt.Valid,
 		AccessToken:           userAccessToken,
 		AccessTokenExpiresAt:  accessTokenExpiresAt,
 		RefreshToken:          userRefreshToken,
@@ -239,73 +266,150 @@ func (cfg *apiConfig) loginHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if platformNotProduction(cfg) {
-		cfg.sl.Debug("Listing user info at login", "user id", userLoginResponse.ID, "user access token", userLoginResponse.AccessToken, "user refresh token", userLoginResponse.RefreshToken)
+		cfg.sl.DebugContext(r.Context(), "Listing user info at login", "user id", userLoginResponse.ID, "user access token", userLoginResponse.AccessToken, "user refresh token", userLoginResponse.RefreshToken)
 	}
 
-	cfg.sl.Debug("User successfully logged in", "user id", userRecord.ID)
+	cfg.sl.DebugContext(r.Context(), "User successfully logged in", "user id", userRecord.ID)
 	respondWithJSON(http.StatusOK, userLoginResponse, w, cfg.sl)
 }
 
 // accepts refresh token as authentication
-// responds with a new access token if authorized
+// responds with a new access token and a new refresh token if authorized,
+// the provided refresh token is revoked and replaced by the new one
 // POST /api/v1/auth/refresh
 // POST /refresh is about changing state, not creating a new record on the server
 func (cfg *apiConfig) refreshTokenHandler(w http.ResponseWriter, r *http.Request) {
 	providedRefreshToken, err := auth.GetBearerToken(r.Header, cfg.sl)
 	if err != nil {
-		cfg.sl.Debug("Could not get refresh token from headers", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get refresh token from headers", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	refreshTokenRecord, err := cfg.db.GetUserFromRefreshToken(r.Context(), providedRefreshToken)
-	if err != nil {
-		cfg.sl.Debug("Could not get refresh token record from database", "error", err)
-		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+	refreshTokenRecord, err := cfg.db.GetRefreshTokenByHash(r.Context(), auth.HashRefreshToken(providedRefreshToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Refresh token does not exist")
+		respondWithError(errors.New("invalid refresh token"), http.StatusUnauthorized, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get refresh token record from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
 	if refreshTokenRecord.RevokedAt.Valid {
-		// is marked as revoked
-		if time.Now().After(refreshTokenRecord.RevokedAt.Time) {
-			cfg.sl.Debug("Refresh token was revoked for user", "user id", refreshTokenRecord.UserID)
-			respondWithError(err, http.StatusUnauthorized, w, cfg.sl)
-			return
+		// a token that was already replaced is being used again, so it may have been stolen
+		if refreshTokenRecord.ReplacedBy.Valid {
+			cfg.revokeReusedRefreshToken(r.Context(), refreshTokenRecord.UserID, refreshTokenRecord.FamilyID)
 		}
 
-		// has been marked as revoked but in the future
-		// these tokens should not be accepted
-		// this may present a bug
-		// one place to check is the POST /refresh handler
-		cfg.sl.Warn("Refresh token is marked as revoked but is still valid.")
-		respondWithError(errors.New("valid token will be revoked in the future"), http.StatusInternalServerError, w, cfg.sl)
+		cfg.sl.DebugContext(r.Context(), "Refresh token was revoked for user", "user id", refreshTokenRecord.UserID)
+		respondWithError(errors.New("refresh token was revoked"), http.StatusUnauthorized, w, cfg.sl)
 		return
 	}
 
 	if time.Now().UTC().After(refreshTokenRecord.ExpiresAt) {
-		cfg.sl.Debug("Refresh token has expired")
-		respondWithError(errors.New("bad request"), http.StatusBadRequest, w, cfg.sl)
+		cfg.sl.DebugContext(r.Context(), "Refresh token has expired")
+		respondWithError(errors.New("refresh token has expired"), http.StatusUnauthorized, w, cfg.sl)
+		return
+	}
+
+	newRefreshToken, err := auth.MakeRefreshToken(cfg.sl)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to create new refresh token for user", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	newRefreshTokenHash := auth.HashRefreshToken(newRefreshToken)
+
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), nil)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not begin transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	// revoke the provided token first, a concurrent refresh with the same token
+	// waits on the row and then finds it already revoked
+	rotateParams := database.RotateRefreshTokenParams{
+		TokenHash:  refreshTokenRecord.TokenHash,
+		UpdatedBy:  refreshTokenRecord.UserID,
+		ReplacedBy: sql.NullString{String: newRefreshTokenHash, Valid: true},
+	}
+	rowsRotated, err := qtx.RotateRefreshToken(r.Context(), rotateParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not revoke refresh token in database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsRotated == 0 {
+		tx.Rollback()
+		cfg.revokeReusedRefreshToken(r.Context(), refreshTokenRecord.UserID, refreshTokenRecord.FamilyID)
+		respondWithError(errors.New("refresh token was revoked"), http.StatusUnauthorized, w, cfg.sl)
+		return
+	}
+
+	userAgent, ipAddress := sessionDevice(r)
+	refreshTokenExpiresAt := time.Now().Add(cfg.refreshTokenDuration)
+	createRefreshToken := database.CreateRefreshTokenParams{
+		TokenHash: newRefreshTokenHash,
+		CreatedBy: refreshTokenRecord.UserID,
+		ExpiresAt: refreshTokenExpiresAt,
+		FamilyID:  refreshTokenRecord.FamilyID,
+		UserAgent: userAgent,
+		IpAddress: ipAddress,
+	}
+	_, err = qtx.CreateRefreshToken(r.Context(), createRefreshToken)
+	if err != nil {
+		cfg.sl.WarnContext(r.Context(), "Unable to put a user's new refresh token into database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not commit refresh token rotation", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
 	accessTokenExpiresAt := time.Now().UTC().Add(cfg.accessTokenDuration)
-	newAccessToken, err := auth.MakeJWT(refreshTokenRecord.UserID, cfg.JWTSecret, cfg.accessTokenDuration, cfg.sl)
+	newAccessToken, err := auth.MakeJWT(refreshTokenRecord.UserID, refreshTokenRecord.FamilyID, cfg.JWTSecret, cfg.accessTokenDuration, cfg.sl)
 	if err != nil {
-		cfg.sl.Debug("Could not create a new access token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not create a new access token", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
 
 	refreshResponse := &AuthRefreshResponse{
-		ID:                   refreshTokenRecord.UserID,
-		AccessToken:          newAccessToken,
-		AccessTokenExpiresAt: accessTokenExpiresAt,
+		ID:                    refreshTokenRecord.UserID,
+		AccessToken:           newAccessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          newRefreshToken,
+		RefreshTokenExpiresAt: refreshTokenExpiresAt,
 	}
 
-	cfg.sl.Debug("User successfully refreshed their access token", "user id", refreshTokenRecord.UserID)
+	cfg.sl.DebugContext(r.Context(), "User successfully refreshed their tokens", "user id", refreshTokenRecord.UserID)
 	respondWithJSON(http.StatusOK, refreshResponse, w, cfg.sl)
 }
 
+// revokes every token in the family of a refresh token that was used after it was replaced.
+// both the thief and the user are logged out once their access tokens expire.
+func (cfg *apiConfig) revokeReusedRefreshToken(ctx context.Context, userID, familyID uuid.UUID) {
+	cfg.sl.WarnContext(ctx, "Replaced refresh token was used again, revoking its family", "user id", userID, "family id", familyID)
+
+	revokeParams := database.RevokeRefreshTokenFamilyParams{
+		FamilyID:  familyID,
+		UpdatedBy: userID,
+	}
+	_, err := cfg.db.RevokeRefreshTokenFamily(ctx, revokeParams)
+	if err != nil {
+		cfg.sl.ErrorContext(ctx, "Could not revoke refresh token family", "error", err, "family id", familyID)
+	}
+}
+
 // accepts refresh token as authentication
 // responds with 204 No Content if successfully revoked
 // POST /api/v1/auth/revoke
@@ -314,7 +418,7 @@ func (cfg *apiConfig) refreshTokenHandler(w http.ResponseWriter, r *http.Request
 func (cfg *apiConfig) revokeRefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
 	providedRefreshToken, err := auth.GetBearerToken(r.Header, cfg.sl)
 	if err != nil {
-		cfg.sl.Debug("Could not get refresh token from headers", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get refresh token from headers", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -322,24 +426,24 @@ func (cfg *apiConfig) revokeRefreshTokenHandler(w http.ResponseWriter, r *http.R
 	var revokeRequest AuthRevokeRequest
 	err = json.NewDecoder(r.Body).Decode(&revokeRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	defer r.Body.Close()
 
 	revokeRefreshTokenParams := database.RevokeRefreshTokenWithTokenParams{
-		RefreshToken: providedRefreshToken,
-		UpdatedBy:    revokeRequest.ID,
+		TokenHash: auth.HashRefreshToken(providedRefreshToken),
+		UpdatedBy: revokeRequest.ID,
 	}
 	revokeRecordUserID, err := cfg.db.RevokeRefreshTokenWithToken(r.Context(), revokeRefreshTokenParams)
 	if err != nil {
-		cfg.sl.Debug("Could not revoke refresh token in database", "error", err, "refresh token id", revokeRequest.ID)
+		cfg.sl.DebugContext(r.Context(), "Could not revoke refresh token in database", "error", err, "refresh token id", revokeRequest.ID)
 		respondWithError(err, http.StatusUnauthorized, w, cfg.sl)
 		return
 	}
 
 	// token was revoked
-	cfg.sl.Debug("User successfully revoked their refresh token", "user	id", revokeRecordUserID)
+	cfg.sl.DebugContext(r.Context(), "User successfully revoked their refresh token", "user	id", revokeRecordUserID)
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/auth"
+	"github.com/nicholasss/plantae/internal/database"
+	"github.com/nicholasss/plantae/internal/notify"
+)
+
+// === request response types ===
+
+// AuthVerifyEmailRequest is for decoding the token of an email verification.
+type AuthVerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// === email verification handlers ===
+
+// requires access token in auth header
+// emails a new verification token, replacing the previous one
+// POST /api/v1/my/email/verification
+func (cfg *apiConfig) usersEmailVerificationRequestHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	emailVerified, err := cfg.db.IsUserEmailVerifiedByID(r.Context(), requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get user's email verification from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if emailVerified {
+		cfg.sl.DebugContext(r.Context(), "User's email is already verified")
+		respondWithError(errors.New("email is already verified"), http.StatusConflict, w, cfg.sl)
+		return
+	}
+
+	userRecord, err := cfg.db.GetUserByIDWithoutPassword(r.Context(), requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get user record from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = cfg.sendEmailVerification(r.Context(), requestUserID, userRecord.Email)
+	if err != nil {
+		cfg.sl.WarnContext(r.Context(), "Could not send verification email", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User requested a verification email", "user id", requestUserID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// confirms the email of an account with the token from the verification email, the token can only be used once
+// POST /api/v1/auth/verify-email
+func (cfg *apiConfig) verifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var verifyRequest AuthVerifyEmailRequest
+	err := json.NewDecoder(r.Body).Decode(&verifyRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	if verifyRequest.Token == "" {
+		cfg.sl.DebugContext(r.Context(), "Request body missing token")
+		respondWithError(errors.New("token is required"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), nil)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not begin transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	verifyUserID, err := qtx.UseEmailVerificationToken(r.Context(), auth.HashRefreshToken(verifyRequest.Token))
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Email verification token does not exist or has expired")
+		respondWithError(errors.New("invalid or expired email verification token"), http.StatusBadRequest, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not use email verification token", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = qtx.SetUserEmailVerifiedByID(r.Context(), verifyUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not mark user's email as verified", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not commit email verification", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.InfoContext(r.Context(), "User verified their email", "user id", verifyUserID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// stores a new verification token for the user, replacing the previous one, and emails it to them
+func (cfg *apiConfig) sendEmailVerification(ctx context.Context, userID uuid.UUID, email string) error {
+	// verification tokens are made and hashed the same way as refresh tokens
+	verifyToken, err := auth.MakeRefreshToken(cfg.sl)
+	if err != nil {
+		return err
+	}
+
+	tx, err := cfg.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.DeleteEmailVerificationTokensByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	createParams := database.CreateEmailVerificationTokenParams{
+		TokenHash: auth.HashRefreshToken(verifyToken),
+		ExpiresAt: time.Now().Add(cfg.emailVerifyTokenDuration),
+		UserID:    userID,
+	}
+	err = qtx.CreateEmailVerificationToken(ctx, createParams)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	if platformNotProduction(cfg) {
+		cfg.sl.DebugContext(ctx, "Listing email verification token", "user id", userID, "verification token", verifyToken)
+	}
+
+	return cfg.mailer.Send(ctx, emailVerificationMail(email, verifyToken, cfg.emailVerifyTokenDuration))
+}
+
+// builds the email that carries an email verification token
+func emailVerificationMail(email, verifyToken string, expiresIn time.Duration) notify.Mail {
+	var body strings.Builder
+	body.WriteString("Welcome to Plantae, please confirm that this is your email address.\r\n\r\n")
+	fmt.Fprintf(&body, "Use this token to verify it, it can be used once within %d hours:\r\n\r\n", int(expiresIn.Hours()))
+	fmt.Fprintf(&body, "%s\r\n\r\n", verifyToken)
+	body.WriteString("Once it is verified, you can add an email notification channel to get plant care reminders at this address.\r\n")
+
+	return notify.Mail{
+		To:      email,
+		Subject: "Verify your Plantae email",
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// === request response types ===
+
+// UserLocationRequest is for decoding location create and update requests.
+type UserLocationRequest struct {
+	Name                  string  `json:"name"`
+	LightExposure         *string `json:"lightExposure"`
+	MinTemperatureCelsius *int32  `json:"minTemperatureCelsius"`
+	MaxTemperatureCelsius *int32  `json:"maxTemperatureCelsius"`
+	MinHumidityPercent    *int32  `json:"minHumidityPercent"`
+	MaxHumidityPercent    *int32  `json:"maxHumidityPercent"`
+}
+
+// UserLocationResponse is for encoding a single location.
+type UserLocationResponse struct {
+	ID                    uuid.UUID `json:"id"`
+	Name                  string    `json:"name"`
+	LightExposure         *string   `json:"lightExposure,omitempty"`
+	MinTemperatureCelsius *int32    `json:"minTemperatureCelsius,omitempty"`
+	MaxTemperatureCelsius *int32    `json:"maxTemperatureCelsius,omitempty"`
+	MinHumidityPercent    *int32    `json:"minHumidityPercent,omitempty"`
+	MaxHumidityPercent    *int32    `json:"maxHumidityPercent,omitempty"`
+}
+
+// UserPlantLocationRequest is for assigning a users plant to a location, null unassigns it.
+type UserPlantLocationRequest struct {
+	LocationID *uuid.UUID `json:"locationID"`
+}
+
+// UserPlantLocationResponse is the location a users plant is kept in.
+type UserPlantLocationResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// environmentRange is a typical temperature and humidity range, either end may be unknown
+type environmentRange struct {
+	MinTemperatureCelsius sql.NullInt32
+	MaxTemperatureCelsius sql.NullInt32
+	MinHumidityPercent    sql.NullInt32
+	MaxHumidityPercent    sql.NullInt32
+}
+
+// checks the request body of location create and update requests
+func (locationRequest UserLocationRequest) validate() error {
+	if locationRequest.Name == "" {
+		return errors.New("no name property provided")
+	}
+
+	minT, maxT := locationRequest.MinTemperatureCelsius, locationRequest.MaxTemperatureCelsius
+	if minT != nil && maxT != nil && *minT > *maxT {
+		return errors.New("min temperature is above max temperature")
+	}
+
+	minH, maxH := locationRequest.MinHumidityPercent, locationRequest.MaxHumidityPercent
+	for _, humidity := range []*int32{minH, maxH} {
+		if humidity != nil && (*humidity < 0 || *humidity > 100) {
+			return errors.New("humidity must be between 0 and 100 percent")
+		}
+	}
+	if minH != nil && maxH != nil && *minH > *maxH {
+		return errors.New("min humidity is above max humidity")
+	}
+
+	return nil
+}
+
+// converts the optional properties of the request into database types
+func (locationRequest UserLocationRequest) toNullable() (sql.NullString, environmentRange) {
+	lightExposure := sql.NullString{}
+	if locationRequest.LightExposure != nil {
+		lightExposure.Valid = true
+		lightExposure.String = *locationRequest.LightExposure
+	}
+
+	toNullInt32 := func(value *int32) sql.NullInt32 {
+		if value == nil {
+			return sql.NullInt32{}
+		}
+		return sql.NullInt32{Int32: *value, Valid: true}
+	}
+
+	envRange := environmentRange{
+		MinTemperatureCelsius: toNullInt32(locationRequest.MinTemperatureCelsius),
+		MaxTemperatureCelsius: toNullInt32(locationRequest.MaxTemperatureCelsius),
+		MinHumidityPercent:    toNullInt32(locationRequest.MinHumidityPercent),
+		MaxHumidityPercent:    toNullInt32(locationRequest.MaxHumidityPercent),
+	}
+
+	return lightExposure, envRange
+}
+
+// converts a location record into the response type
+func newUserLocationResponse(record database.GetAllLocationsByUserIDRow) UserLocationResponse {
+	locationResponse := UserLocationResponse{
+		ID:   record.ID,
+		Name: record.Name,
+	}
+
+	if record.LightExposure.Valid {
+		locationResponse.LightExposure = &record.LightExposure.String
+	}
+	if record.MinTemperatureCelsius.Valid {
+		locationResponse.MinTemperatureCelsius = &record.MinTemperatureCelsius.Int32
+	}
+	if record.MaxTemperatureCelsius.Valid {
+		locationResponse.MaxTemperatureCelsius = &record.MaxTemperatureCelsius.Int32
+	}
+	if record.MinHumidityPercent.Valid {
+		locationResponse.MinHumidityPercent = &record.MinHumidityPercent.Int32
+	}
+	if record.MaxHumidityPercent.Valid {
+		locationResponse.MaxHumidityPercent = &record.MaxHumidityPercent.Int32
+	}
+
+	return locationResponse
+}
+
+// lists where the range of a location falls outside of the range its plant type tolerates
+// ends that are unknown on either side are not compared
+func environmentWarnings(location, plantType environmentRange) []string {
+	warnings := make([]string, 0)
+
+	if location.MinTemperatureCelsius.Valid && plantType.MinTemperatureCelsius.Valid &&
+		location.MinTemperatureCelsius.Int32 < plantType.MinTemperatureCelsius.Int32 {
+		warnings = append(warnings, fmt.Sprintf("location gets as cold as %d°C, below the plant type minimum of %d°C",
+			location.MinTemperatureCelsius.Int32, plantType.MinTemperatureCelsius.Int32))
+	}
+	if location.MaxTemperatureCelsius.Valid && plantType.MaxTemperatureCelsius.Valid &&
+		location.MaxTemperatureCelsius.Int32 > plantType.MaxTemperatureCelsius.Int32 {
+		warnings = append(warnings, fmt.Sprintf("location gets as hot as %d°C, above the plant type maximum of %d°C",
+			location.MaxTemperatureCelsius.Int32, plantType.MaxTemperatureCelsius.Int32))
+	}
+	if location.MinHumidityPercent.Valid && plantType.MinHumidityPercent.Valid &&
+		location.MinHumidityPercent.Int32 < plantType.MinHumidityPercent.Int32 {
+		warnings = append(warnings, fmt.Sprintf("location gets as dry as %d%% humidity, below the plant type minimum of %d%%",
+			location.MinHumidityPercent.Int32, plantType.MinHumidityPercent.Int32))
+	}
+	if location.MaxHumidityPercent.Valid && plantType.MaxHumidityPercent.Valid &&
+		location.MaxHumidityPercent.Int32 > plantType.MaxHumidityPercent.Int32 {
+		warnings = append(warnings, fmt.Sprintf("location gets as humid as %d%% humidity, above the plant type maximum of %d%%",
+			location.MaxHumidityPercent.Int32, plantType.MaxHumidityPercent.Int32))
+	}
+
+	return warnings
+}
+
+// resolves the location of a users plant, and warns when it does not suit the plant type
+func usersPlantLocation(locationID uuid.NullUUID, locationName sql.NullString, location, plantType environmentRange) (*UserPlantLocationResponse, []string) {
+	if !locationID.Valid || !locationName.Valid {
+		return nil, nil
+	}
+
+	locationResponse := &UserPlantLocationResponse{
+		ID:   locationID.UUID,
+		Name: locationName.String,
+	}
+	return locationResponse, environmentWarnings(location, plantType)
+}
+
+// === handler functions ===
+
+// GET /api/v1/my/locations
+func (cfg *apiConfig) usersLocationsListHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	locationRecords, err := cfg.db.GetAllLocationsByUserID(r.Context(), requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get locations from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	locationResponses := make([]UserLocationResponse, 0)
+	for _, record := range locationRecords {
+		locationResponses = append(locationResponses, newUserLocationResponse(record))
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully listed their locations", "user id", requestUserID)
+	respondWithJSON(http.StatusOK, locationResponses, w, cfg.sl)
+}
+
+// POST /api/v1/my/locations
+func (cfg *apiConfig) usersLocationsCreateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	var createRequest UserLocationRequest
+	err := json.NewDecoder(r.Body).Decode(&createRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	// check request body
+	err = createRequest.validate()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Request body has invalid location", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	lightExposure, envRange := createRequest.toNullable()
+	createParams := database.CreateLocationParams{
+		CreatedBy:             requestUserID,
+		Name:                  createRequest.Name,
+		LightExposure:         lightExposure,
+		MinTemperatureCelsius: envRange.MinTemperatureCelsius,
+		MaxTemperatureCelsius: envRange.MaxTemperatureCelsius,
+		MinHumidityPercent:    envRange.MinHumidityPercent,
+		MaxHumidityPercent:    envRange.MaxHumidityPercent,
+	}
+	locationRecord, err := cfg.db.CreateLocation(r.Context(), createParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not create location", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	locationResponse := newUserLocationResponse(database.GetAllLocationsByUserIDRow(locationRecord))
+
+	cfg.sl.DebugContext(r.Context(), "User successfully created a location", "user id", requestUserID, "location id", locationRecord.ID)
+	respondWithJSON(http.StatusCreated, locationResponse, w, cfg.sl)
+}
+
+// PUT /api/v1/my/locations/{locationID}
+// replaces all properties of the location
+func (cfg *apiConfig) usersLocationsUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	locationIDStr := r.PathValue("locationID")
+	locationID, err := uuid.Parse(locationIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse location id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	var updateRequest UserLocationRequest
+	err = json.NewDecoder(r.Body).Decode(&updateRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	// check request body
+	err = updateRequest.validate()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Request body has invalid location", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	lightExposure, envRange := updateRequest.toNullable()
+	updateParams := database.UpdateLocationByIDParams{
+		ID:                    locationID,
+		UpdatedBy:             requestUserID,
+		Name:                  updateRequest.Name,
+		LightExposure:         lightExposure,
+		MinTemperatureCelsius: envRange.MinTemperatureCelsius,
+		MaxTemperatureCelsius: envRange.MaxTemperatureCelsius,
+		MinHumidityPercent:    envRange.MinHumidityPercent,
+		MaxHumidityPercent:    envRange.MaxHumidityPercent,
+	}
+	rowsUpdated, err := cfg.db.UpdateLocationByID(r.Context(), updateParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not update location", "error", err, "location id", locationID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsUpdated == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot update non existent location", "location id", locationID)
+		respondWithError(errors.New("location does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully updated location", "user id", requestUserID, "location id", locationID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /api/v1/my/locations/{locationID}
+// plants in the location are left unassigned
+func (cfg *apiConfig) usersLocationsDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	locationIDStr := r.PathValue("locationID")
+	locationID, err := uuid.Parse(locationIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse location id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// the location is only deleted together with unassigning its plants
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), nil)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not begin transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	deleteParams := database.DeleteLocationByIDParams{
+		ID:        locationID,
+		DeletedBy: uuid.NullUUID{UUID: requestUserID, Valid: true},
+		UpdatedBy: requestUserID,
+	}
+	rowsDeleted, err := qtx.DeleteLocationByID(r.Context(), deleteParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not delete location", "error", err, "location id", locationID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsDeleted == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot delete non existent location", "location id", locationID)
+		respondWithError(errors.New("location does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
+
+	unassignParams := database.UnassignUsersPlantsFromLocationParams{
+		LocationID: uuid.NullUUID{UUID: locationID, Valid: true},
+		UpdatedBy:  requestUserID,
+	}
+	err = qtx.UnassignUsersPlantsFromLocation(r.Context(), unassignParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not unassign users plants from deleted location", "error", err, "location id", locationID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not commit location deletion", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully deleted location", "user id", requestUserID, "location id", locationID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PUT /api/v1/my/plants/{plantID}/location
+// assigns a users plant to one of the users locations
+func (cfg *apiConfig) usersPlantsLocationUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse users plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	var locationRequest UserPlantLocationRequest
+	err = json.NewDecoder(r.Body).Decode(&locationRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	newLocationID := uuid.NullUUID{}
+	if locationRequest.LocationID != nil {
+		// the location has to belong to the user as well
+		getParams := database.GetLocationByIDParams{
+			ID:     *locationRequest.LocationID,
+			UserID: requestUserID,
+		}
+		_, err = cfg.db.GetLocationByID(r.Context(), getParams)
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.sl.DebugContext(r.Context(), "Request body has non existent location", "location id", *locationRequest.LocationID)
+			respondWithError(errors.New("location does not exist"), http.StatusBadRequest, w, cfg.sl)
+			return
+		} else if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Unable to check for location in database", "error", err)
+			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+			return
+		}
+
+		newLocationID.Valid = true
+		newLocationID.UUID = *locationRequest.LocationID
+	}
+
+	updateParams := database.UpdateUsersPlantLocationByIDParams{
+		ID:         plantID,
+		UpdatedBy:  requestUserID,
+		LocationID: newLocationID,
+	}
+	rowsUpdated, err := cfg.db.UpdateUsersPlantLocationByID(r.Context(), updateParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not update users plant location", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsUpdated == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot update location of non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully updated users plant location", "user id", requestUserID, "users plant id", plantID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/database"
+	"github.com/nicholasss/plantae/internal/notify"
+)
+
+// There are only three kinds of notification channels:
+// - email, target is an email address
+// - webhook, target is an http(s) url that receives a JSON POST
+// - log, target is ignored and reminders are written to the server log
+const (
+	notifyChannelEmail   = "email"
+	notifyChannelWebhook = "webhook"
+	notifyChannelLog     = "log"
+)
+
+// === request response types ===
+
+// UserNotificationSettingsRequest is for decoding notification settings update requests.
+type UserNotificationSettingsRequest struct {
+	QuietHoursStart *int32 `json:"quietHoursStart"`
+	QuietHoursEnd   *int32 `json:"quietHoursEnd"`
+	Timezone        string `json:"timezone"`
+}
+
+// UserNotificationChannelRequest is for decoding notification channel create requests.
+type UserNotificationChannelRequest struct {
+	ChannelType string `json:"channelType"`
+	Target      string `json:"target"`
+}
+
+// UserNotificationChannelUpdateRequest is for decoding notification channel update requests.
+type UserNotificationChannelUpdateRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// UserNotificationChannelResponse is for encoding a single notification channel.
+type UserNotificationChannelResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ChannelType string    `json:"channelType"`
+	Target      string    `json:"target,omitempty"`
+	Enabled     bool      `json:"enabled"`
+}
+
+// UserNotificationSettingsResponse is for encoding notification settings and channels.
+type UserNotificationSettingsResponse struct {
+	QuietHoursStart *int32                            `json:"quietHoursStart,omitempty"`
+	QuietHoursEnd   *int32                            `json:"quietHoursEnd,omitempty"`
+	Timezone        string                            `json:"timezone"`
+	LastRemindedAt  *time.Time                        `json:"lastRemindedAt,omitempty"`
+	Channels        []UserNotificationChannelResponse `json:"channels"`
+}
+
+// checks the channel target for the given channel type
+func validateNotificationTarget(ctx context.Context, channelType, target string) error {
+	switch channelType {
+	case notifyChannelEmail:
+		address, err := mail.ParseAddress(target)
+		if err != nil || address.Address != target {
+			return errors.New("invalid email address")
+		}
+	case notifyChannelWebhook:
+		return notify.ValidateWebhookURL(ctx, target)
+	case notifyChannelLog:
+		return nil
+	default:
+		return errors.New("invalid channel type")
+	}
+
+	return nil
+}
+
+// === handler functions ===
+
+// GET /api/v1/my/notifications
+func (cfg *apiConfig) usersNotificationsViewHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	// users that have never saved settings get the defaults
+	settingsResponse := UserNotificationSettingsResponse{Timezone: "UTC"}
+
+	settingsRecord, err := cfg.db.GetNotificationSettingsByUserID(r.Context(), requestUserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Could not get notification settings from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	} else if err == nil {
+		if settingsRecord.QuietHoursStart.Valid {
+			settingsResponse.QuietHoursStart = &settingsRecord.QuietHoursStart.Int32
+		}
+		if settingsRecord.QuietHoursEnd.Valid {
+			settingsResponse.QuietHoursEnd = &settingsRecord.QuietHoursEnd.Int32
+		}
+		if settingsRecord.LastRemindedAt.Valid {
+			settingsResponse.LastRemindedAt = &settingsRecord.LastRemindedAt.Time
+		}
+		settingsResponse.Timezone = settingsRecord.Timezone
+	}
+
+	channelRecords, err := cfg.db.GetAllNotificationChannelsByUserID(r.Context(), requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get notification channels from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	settingsResponse.Channels = make([]UserNotificationChannelResponse, 0)
+	for _, record := range channelRecords {
+		settingsResponse.Channels = append(settingsResponse.Channels, UserNotificationChannelResponse{
+			ID:          record.ID,
+			ChannelType: record.ChannelType,
+			Target:      record.Target,
+			Enabled:     record.Enabled,
+		})
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully viewed their notification settings", "user id", requestUserID)
+	respondWithJSON(http.StatusOK, settingsResponse, w, cfg.sl)
+}
+
+// PUT /api/v1/my/notifications
+// replaces quiet hours and timezone
+func (cfg *apiConfig) usersNotificationsUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	var updateRequest UserNotificationSettingsRequest
+	err := json.NewDecoder(r.Body).Decode(&updateRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	// check request body
+	if (updateRequest.QuietHoursStart == nil) != (updateRequest.QuietHoursEnd == nil) {
+		cfg.sl.DebugContext(r.Context(), "Request body has only one of quiet hours start and end")
+		respondWithError(errors.New("quiet hours need both a start and end"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	if updateRequest.QuietHoursStart != nil {
+		if *updateRequest.QuietHoursStart < 0 || *updateRequest.QuietHoursStart > 23 ||
+			*updateRequest.QuietHoursEnd < 0 || *updateRequest.QuietHoursEnd > 23 {
+			cfg.sl.DebugContext(r.Context(), "Request body has quiet hours out of range")
+			respondWithError(errors.New("quiet hours must be between 0 and 23"), http.StatusBadRequest, w, cfg.sl)
+			return
+		}
+	}
+	if updateRequest.Timezone == "" {
+		updateRequest.Timezone = "UTC"
+	}
+	_, err = time.LoadLocation(updateRequest.Timezone)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Request body has unknown timezone", "timezone", updateRequest.Timezone)
+		respondWithError(errors.New("unknown timezone"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	quietHoursStart := sql.NullInt32{}
+	quietHoursEnd := sql.NullInt32{}
+	if updateRequest.QuietHoursStart != nil {
+		quietHoursStart.Valid = true
+		quietHoursStart.Int32 = *updateRequest.QuietHoursStart
+		quietHoursEnd.Valid = true
+		quietHoursEnd.Int32 = *updateRequest.QuietHoursEnd
+	}
+
+	upsertParams := database.UpsertNotificationSettingsParams{
+		UserID:          requestUserID,
+		QuietHoursStart: quietHoursStart,
+		QuietHoursEnd:   quietHoursEnd,
+		Timezone:        updateRequest.Timezone,
+	}
+	_, err = cfg.db.UpsertNotificationSettings(r.Context(), upsertParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not update notification settings", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully updated their notification settings", "user id", requestUserID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/v1/my/notifications/channels
+func (cfg *apiConfig) usersNotificationChannelsCreateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	var createRequest UserNotificationChannelRequest
+	err := json.NewDecoder(r.Body).Decode(&createRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	// check request body
+	createRequest.ChannelType = strings.ToLower(createRequest.ChannelType)
+	err = validateNotificationTarget(r.Context(), createRequest.ChannelType, createRequest.Target)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Request body has invalid channel", "channel type", createRequest.ChannelType, "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// reminders are only emailed to the address of the account, once the user confirmed they own it
+	if createRequest.ChannelType == notifyChannelEmail {
+		userRecord, err := cfg.db.GetUserByIDWithoutPassword(r.Context(), requestUserID)
+		if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Could not get user record from database", "error", err)
+			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+			return
+		}
+
+		// emails are stored normalized
+		target, err := normalizeEmail(createRequest.Target)
+		if err != nil || target != userRecord.Email {
+			cfg.sl.DebugContext(r.Context(), "User is creating an email channel for another address")
+			respondWithError(errors.New("email channels must use the email of the account"), http.StatusForbidden, w, cfg.sl)
+			return
+		}
+
+		emailVerified, err := cfg.db.IsUserEmailVerifiedByID(r.Context(), requestUserID)
+		if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Could not get user's email verification from database", "error", err)
+			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+			return
+		}
+		if !emailVerified {
+			cfg.sl.DebugContext(r.Context(), "User with unverified email is creating an email channel")
+			respondWithError(errors.New("verify your email before adding email channels"), http.StatusForbidden, w, cfg.sl)
+			return
+		}
+		createRequest.Target = target
+	}
+
+	createParams := database.CreateNotificationChannelParams{
+		CreatedBy:   requestUserID,
+		ChannelType: createRequest.ChannelType,
+		Target:      createRequest.Target,
+	}
+	channelRecord, err := cfg.db.CreateNotificationChannel(r.Context(), createParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not create notification channel", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	channelResponse := UserNotificationChannelResponse{
+		ID:          channelRecord.ID,
+		ChannelType: channelRecord.ChannelType,
+		Target:      channelRecord.Target,
+		Enabled:     channelRecord.Enabled,
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully created a notification channel", "user id", requestUserID, "channel id", channelRecord.ID, "channel type", channelRecord.ChannelType)
+	respondWithJSON(http.StatusCreated, channelResponse, w, cfg.sl)
+}
+
+// PUT /api/v1/my/notifications/channels/{channelID}
+// enables or disables a channel
+func (cfg *apiConfig) usersNotificationChannelsUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	channelIDStr := r.PathValue("channelID")
+	channelID, err := uuid.Parse(channelIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse channel id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	var updateRequest UserNotificationChannelUpdateRequest
+	err = json.NewDecoder(r.Body).Decode(&updateRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	if updateRequest.Enabled == nil {
+		cfg.sl.DebugContext(r.Context(), "No updates provided in request")
+		respondWithError(errors.New("no updates provided"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	updateParams := database.UpdateNotificationChannelEnabledByIDParams{
+		ID:        channelID,
+		UpdatedBy: requestUserID,
+		Enabled:   *updateRequest.Enabled,
+	}
+	rowsUpdated, err := cfg.db.UpdateNotificationChannelEnabledByID(r.Context(), updateParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not update notification channel", "error", err, "channel id", channelID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsUpdated == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot update non existent notification channel", "channel id", channelID)
+		respondWithError(errors.New("notification channel does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully updated notification channel", "user id", requestUserID, "channel id", channelID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /api/v1/my/notifications/channels/{channelID}
+func (cfg *apiConfig) usersNotificationChannelsDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	channelIDStr := r.PathValue("channelID")
+	channelID, err := uuid.Parse(channelIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse channel id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	deleteParams := database.DeleteNotificationChannelByIDParams{
+		ID:        channelID,
+		DeletedBy: uuid.NullUUID{UUID: requestUserID, Valid: true},
+		UpdatedBy: requestUserID,
+	}
+	rowsDeleted, err := cfg.db.DeleteNotificationChannelByID(r.Context(), deleteParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not delete notification channel", "error", err, "channel id", channelID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsDeleted == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot delete non existent notification channel", "channel id", channelID)
+		respondWithError(errors.New("notification channel does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully deleted notification channel", "user id", requestUserID, "channel id", channelID)
+	w.WriteHeader(http.StatusNoContent)
+}
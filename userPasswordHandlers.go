@@ -0,0 +1,345 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nicholasss/plantae/internal/auth"
+	"github.com/nicholasss/plantae/internal/database"
+	"github.com/nicholasss/plantae/internal/notify"
+)
+
+// === request response types ===
+
+// UserChangePasswordRequest is for decoding password change requests.
+type UserChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// AuthForgotPasswordRequest is for decoding password reset requests.
+type AuthForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// AuthResetPasswordRequest is for decoding the new password of a password reset.
+type AuthResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+// === password handlers ===
+
+// requires access token in auth header
+// changes the password after checking the current one, every other session is revoked
+// POST /api/v1/my/password
+func (cfg *apiConfig) usersPasswordChangeHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+	requestSessionID := sessionIDFromContext(r.Context())
+
+	var changeRequest UserChangePasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&changeRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	if changeRequest.CurrentPassword == "" || changeRequest.NewPassword == "" {
+		cfg.sl.DebugContext(r.Context(), "Request body missing current or new password")
+		respondWithError(errors.New("current and new password are required"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	userRecord, err := cfg.db.GetUserByIDWithPassword(r.Context(), requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get user record from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = auth.CheckPasswordHash(changeRequest.CurrentPassword, userRecord.HashedPassword, cfg.sl)
+	changeRequest.CurrentPassword = "" // GC collection
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "User's password change failed due to mis-matching current password")
+		respondWithError(errors.New("current password is incorrect"), http.StatusForbidden, w, cfg.sl)
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(changeRequest.NewPassword, cfg.sl)
+	changeRequest.NewPassword = "" // GC collection
+	if errors.Is(err, auth.ErrPasswordTooLong) {
+		cfg.sl.DebugContext(r.Context(), "New password is too long", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Error hashing user's password", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), nil)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not begin transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	updateParams := database.UpdateUserPasswordByIDParams{
+		ID:             requestUserID,
+		HashedPassword: hashedPassword,
+		UpdatedBy:      requestUserID,
+	}
+	err = qtx.UpdateUserPasswordByID(r.Context(), updateParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not update user's password in database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// access tokens issued without a session have no session to keep, so every session is revoked
+	revokeParams := database.RevokeOtherRefreshTokenFamiliesParams{
+		UpdatedBy: requestUserID,
+		FamilyID:  requestSessionID,
+	}
+	_, err = qtx.RevokeOtherRefreshTokenFamilies(r.Context(), revokeParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not revoke other sessions", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// a reset requested before the change would otherwise undo it
+	err = qtx.DeletePasswordResetTokensByUserID(r.Context(), requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not delete password reset tokens", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not commit password change", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.InfoContext(r.Context(), "User changed their password", "user id", requestUserID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// emails a single use password reset token to the account with the email, if there is one.
+// another token is not sent until the cooldown since the last one has passed.
+// responds with 202 either way, so the response does not tell whether an account exists
+// POST /api/v1/auth/forgot-password
+func (cfg *apiConfig) forgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var forgotRequest AuthForgotPasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&forgotRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	if forgotRequest.Email == "" {
+		cfg.sl.DebugContext(r.Context(), "Request body missing email")
+		respondWithError(errors.New("email is required"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	userRecord, err := cfg.db.GetUserByEmailWithoutPassword(r.Context(), strings.TrimSpace(forgotRequest.Email))
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Password reset requested for unknown email")
+		w.WriteHeader(http.StatusAccepted)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get user record from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	recentParams := database.HasRecentPasswordResetTokenParams{
+		UserID:    userRecord.ID,
+		CreatedAt: time.Now().Add(-cfg.passwordResetCooldown),
+	}
+	recent, err := cfg.db.HasRecentPasswordResetToken(r.Context(), recentParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not check for recent password reset tokens", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if recent {
+		cfg.sl.DebugContext(r.Context(), "Password reset requested again within the cooldown", "user id", userRecord.ID)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// reset tokens are made and hashed the same way as refresh tokens
+	resetToken, err := auth.MakeRefreshToken(cfg.sl)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to create new password reset token", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), nil)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not begin transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	// only the newest reset token of a user is valid
+	err = qtx.DeletePasswordResetTokensByUserID(r.Context(), userRecord.ID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not delete password reset tokens", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	createParams := database.CreatePasswordResetTokenParams{
+		TokenHash: auth.HashRefreshToken(resetToken),
+		ExpiresAt: time.Now().Add(cfg.passwordResetTokenDuration),
+		UserID:    userRecord.ID,
+	}
+	err = qtx.CreatePasswordResetToken(r.Context(), createParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not create password reset token in database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not commit password reset token", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	if platformNotProduction(cfg) {
+		cfg.sl.DebugContext(r.Context(), "Listing password reset token", "user id", userRecord.ID, "reset token", resetToken)
+	}
+
+	// sent in the background, waiting for the mail server would tell that the account exists
+	cfg.mails.enqueue(r.Context(), passwordResetMail(userRecord.Email, resetToken, cfg.passwordResetTokenDuration))
+
+	cfg.sl.DebugContext(r.Context(), "Password reset token queued", "user id", userRecord.ID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// sets a new password with a token from the password reset email, the token can only be used once.
+// every session of the user is revoked
+// POST /api/v1/auth/reset-password
+func (cfg *apiConfig) resetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var resetRequest AuthResetPasswordRequest
+	err := json.NewDecoder(r.Body).Decode(&resetRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	if resetRequest.Token == "" || resetRequest.NewPassword == "" {
+		cfg.sl.DebugContext(r.Context(), "Request body missing token or new password")
+		respondWithError(errors.New("token and new password are required"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// hashed before the token is used, so a password that is too long does not use up the token
+	hashedPassword, err := auth.HashPassword(resetRequest.NewPassword, cfg.sl)
+	resetRequest.NewPassword = "" // GC collection
+	if errors.Is(err, auth.ErrPasswordTooLong) {
+		cfg.sl.DebugContext(r.Context(), "New password is too long", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Error hashing user's password", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	tx, err := cfg.sqlDB.BeginTx(r.Context(), nil)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not begin transaction", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	resetUserID, err := qtx.UsePasswordResetToken(r.Context(), auth.HashRefreshToken(resetRequest.Token))
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Password reset token does not exist or has expired")
+		respondWithError(errors.New("invalid or expired password reset token"), http.StatusBadRequest, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not use password reset token", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	updateParams := database.UpdateUserPasswordByIDParams{
+		ID:             resetUserID,
+		HashedPassword: hashedPassword,
+		UpdatedBy:      resetUserID,
+	}
+	err = qtx.UpdateUserPasswordByID(r.Context(), updateParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not update user's password in database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// the token was emailed to the user, so they own the address
+	err = qtx.SetUserEmailVerifiedByID(r.Context(), resetUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not mark user's email as verified", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	revokeParams := database.RevokeAllRefreshTokensByUserIDParams{
+		UserID:    resetUserID,
+		UpdatedBy: resetUserID,
+	}
+	_, err = qtx.RevokeAllRefreshTokensByUserID(r.Context(), revokeParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not revoke user's sessions", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not commit password reset", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.InfoContext(r.Context(), "User reset their password", "user id", resetUserID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// builds the email that carries a password reset token
+func passwordResetMail(email, resetToken string, expiresIn time.Duration) notify.Mail {
+	var body strings.Builder
+	body.WriteString("A password reset was requested for your Plantae account.\r\n\r\n")
+	fmt.Fprintf(&body, "Use this token to choose a new password, it can be used once within %d minutes:\r\n\r\n", int(expiresIn.Minutes()))
+	fmt.Fprintf(&body, "%s\r\n\r\n", resetToken)
+	body.WriteString("If you did not ask for this, you can ignore this email, your password has not changed.\r\n")
+
+	return notify.Mail{
+		To:      email,
+		Subject: "Reset your Plantae password",
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// There are only four kinds of care events that can be recorded:
+// - watered
+// - fertilized
+// - misted
+// - repotted
+var careEventTypes = map[string]bool{
+	"watered":    true,
+	"fertilized": true,
+	"misted":     true,
+	"repotted":   true,
+}
+
+// === request response types ===
+
+// UserPlantEventRequest is for decoding care event create and update requests.
+type UserPlantEventRequest struct {
+	EventType  string     `json:"eventType"`
+	OccurredAt *time.Time `json:"occurredAt"`
+	Notes      *string    `json:"notes"`
+}
+
+// UserPlantEventResponse is for encoding care event responses.
+type UserPlantEventResponse struct {
+	ID           uuid.UUID `json:"id"`
+	UsersPlantID uuid.UUID `json:"plantID"`
+	UserID       uuid.UUID `json:"userID"`
+	EventType    string    `json:"eventType"`
+	OccurredAt   time.Time `json:"occurredAt"`
+	Notes        *string   `json:"notes,omitempty"`
+}
+
+// === handler functions ===
+
+// POST /api/v1/my/plants/{plantID}/events
+func (cfg *apiConfig) usersPlantEventsCreateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	var createRequest UserPlantEventRequest
+	err = json.NewDecoder(r.Body).Decode(&createRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	// check request body
+	createRequest.EventType = strings.ToLower(createRequest.EventType)
+	if !careEventTypes[createRequest.EventType] {
+		cfg.sl.DebugContext(r.Context(), "Request body has invalid event type", "event type", createRequest.EventType)
+		respondWithError(errors.New("invalid event type"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// check for plant existing and belonging to user
+	_, err = cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot record event for non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// convert to database params
+	occurredAt := time.Now().UTC()
+	if createRequest.OccurredAt != nil {
+		occurredAt = *createRequest.OccurredAt
+	}
+	notes := sql.NullString{}
+	if createRequest.Notes != nil {
+		notes.Valid = true
+		notes.String = *createRequest.Notes
+	}
+
+	createParams := database.CreatePlantCareEventParams{
+		CreatedBy:    requestUserID,
+		UsersPlantID: plantID,
+		EventType:    createRequest.EventType,
+		OccurredAt:   occurredAt,
+		Notes:        notes,
+	}
+	eventRecord, err := cfg.db.CreatePlantCareEvent(r.Context(), createParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not create care event record", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	var notesP *string
+	if eventRecord.Notes.Valid {
+		notesP = &eventRecord.Notes.String
+	}
+
+	createResponse := UserPlantEventResponse{
+		ID:           eventRecord.ID,
+		UsersPlantID: eventRecord.UsersPlantID,
+		UserID:       eventRecord.UserID,
+		EventType:    eventRecord.EventType,
+		OccurredAt:   eventRecord.OccurredAt,
+		Notes:        notesP,
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully recorded a care event", "user id", requestUserID, "users plant id", plantID, "event id", eventRecord.ID, "event type", eventRecord.EventType)
+	respondWithJSON(http.StatusCreated, createResponse, w, cfg.sl)
+}
+
+// GET /api/v1/my/plants/{plantID}/events
+// returns the care history of a plant, newest first
+func (cfg *apiConfig) usersPlantEventsListHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// check for plant existing and belonging to user
+	_, err = cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot list events for non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	eventRecords, err := cfg.db.GetAllPlantCareEventsOrderedByOccurred(r.Context(), plantID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get care events from database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// convert from database type to response type
+	eventResponses := make([]UserPlantEventResponse, 0)
+	for _, record := range eventRecords {
+		var notes *string
+		if record.Notes.Valid {
+			notes = &record.Notes.String
+		}
+
+		response := UserPlantEventResponse{
+			ID:           record.ID,
+			UsersPlantID: record.UsersPlantID,
+			UserID:       record.UserID,
+			EventType:    record.EventType,
+			OccurredAt:   record.OccurredAt,
+			Notes:        notes,
+		}
+		eventResponses = append(eventResponses, response)
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully listed care events", "user id", requestUserID, "users plant id", plantID)
+	respondWithJSON(http.StatusOK, eventResponses, w, cfg.sl)
+}
+
+// PUT /api/v1/my/plants/{plantID}/events/{eventID}
+func (cfg *apiConfig) usersPlantEventsUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	eventIDStr := r.PathValue("eventID")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse event id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	var updateRequest UserPlantEventRequest
+	err = json.NewDecoder(r.Body).Decode(&updateRequest)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer r.Body.Close()
+
+	// check for plant existing and belonging to user
+	_, err = cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot update event for non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// fetch existing event so that omitted properties are kept
+	getParams := database.GetPlantCareEventByIDParams{
+		ID:           eventID,
+		UsersPlantID: plantID,
+	}
+	eventRecord, err := cfg.db.GetPlantCareEventByID(r.Context(), getParams)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot update non existent care event", "event id", eventID)
+		respondWithError(errors.New("care event does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "event id", eventID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	if updateRequest.EventType == "" && updateRequest.OccurredAt == nil && updateRequest.Notes == nil {
+		cfg.sl.DebugContext(r.Context(), "No updates provided in request")
+		respondWithError(errors.New("no updates provided"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	newEventType := eventRecord.EventType
+	if updateRequest.EventType != "" {
+		newEventType = strings.ToLower(updateRequest.EventType)
+		if !careEventTypes[newEventType] {
+			cfg.sl.DebugContext(r.Context(), "Request body has invalid event type", "event type", newEventType)
+			respondWithError(errors.New("invalid event type"), http.StatusBadRequest, w, cfg.sl)
+			return
+		}
+	}
+	newOccurredAt := eventRecord.OccurredAt
+	if updateRequest.OccurredAt != nil {
+		newOccurredAt = *updateRequest.OccurredAt
+	}
+	newNotes := eventRecord.Notes
+	if updateRequest.Notes != nil {
+		newNotes = sql.NullString{String: *updateRequest.Notes, Valid: true}
+	}
+
+	updateParams := database.UpdatePlantCareEventByIDParams{
+		ID:           eventID,
+		UsersPlantID: plantID,
+		UpdatedBy:    requestUserID,
+		EventType:    newEventType,
+		OccurredAt:   newOccurredAt,
+		Notes:        newNotes,
+	}
+	err = cfg.db.UpdatePlantCareEventByID(r.Context(), updateParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not update care event record", "error", err, "event id", eventID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully updated care event", "user id", requestUserID, "users plant id", plantID, "event id", eventID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /api/v1/my/plants/{plantID}/events/{eventID}
+func (cfg *apiConfig) usersPlantEventsDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	eventIDStr := r.PathValue("eventID")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse event id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// check for plant existing and belonging to user
+	_, err = cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot delete event for non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// check for event existing
+	getParams := database.GetPlantCareEventByIDParams{
+		ID:           eventID,
+		UsersPlantID: plantID,
+	}
+	_, err = cfg.db.GetPlantCareEventByID(r.Context(), getParams)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot delete non existent care event", "event id", eventID)
+		respondWithError(errors.New("care event does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "event id", eventID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	deleteParams := database.DeletePlantCareEventByIDParams{
+		ID:           eventID,
+		UsersPlantID: plantID,
+		DeletedBy:    uuid.NullUUID{UUID: requestUserID, Valid: true},
+	}
+	err = cfg.db.DeletePlantCareEventByID(r.Context(), deleteParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not delete care event record", "error", err, "event id", eventID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully deleted care event", "user id", requestUserID, "users plant id", plantID, "event id", eventID)
+	w.WriteHeader(http.StatusNoContent)
+}
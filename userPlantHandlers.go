@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -8,7 +9,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/nicholasss/plantae/internal/auth"
 	"github.com/nicholasss/plantae/internal/database"
 )
 
@@ -29,11 +29,34 @@ type UserCreatePlantResponse struct {
 }
 
 type UserViewPlantResponse struct {
-	UsersPlantID     uuid.UUID  `json:"id"`
-	PlantSpeciesID   uuid.UUID  `json:"plantSpeciesID"`
-	PlantSpeciesName string     `json:"plantSpeciesName"`
-	AdoptionDate     *time.Time `json:"adoptionDate,omitempty"`
-	Name             *string    `json:"plantName,omitempty"`
+	UsersPlantID     uuid.UUID                  `json:"id"`
+	PlantSpeciesID   uuid.UUID                  `json:"plantSpeciesID"`
+	PlantSpeciesName string                     `json:"plantSpeciesName"`
+	AdoptionDate     *time.Time                 `json:"adoptionDate,omitempty"`
+	Name             *string                    `json:"plantName,omitempty"`
+	CareOverrides    UserPlantCareOverrides     `json:"careOverrides"`
+	Care             UserPlantCareResponse      `json:"care"`
+	Location         *UserPlantLocationResponse `json:"location,omitempty"`
+	Warnings         []string                   `json:"warnings,omitempty"`
+}
+
+// UserViewPlantDetailsResponse is a single users plant with the full care info of its species.
+type UserViewPlantDetailsResponse struct {
+	UserViewPlantResponse
+	Species UserViewPlantSpeciesResponse `json:"species"`
+}
+
+// UserViewPlantSpeciesResponse is the catalog entry of a species, plus the environment ranges of its plant type.
+// common names are in the users preferred language.
+type UserViewPlantSpeciesResponse struct {
+	UserViewAllPlantInfoResponse
+	MaxTemperatureCelsius *int32  `json:"maxTemperatureCelsius,omitempty"`
+	MinTemperatureCelsius *int32  `json:"minTemperatureCelsius,omitempty"`
+	MaxHumidityPercent    *int32  `json:"maxHumidityPercent,omitempty"`
+	MinHumidityPercent    *int32  `json:"minHumidityPercent,omitempty"`
+	SoilOrganicMix        *string `json:"soilOrganicMix,omitempty"`
+	SoilGritMix           *string `json:"soilGritMix,omitempty"`
+	SoilDrainageMix       *string `json:"soilDrainageMix,omitempty"`
 }
 
 type UserUpdatePlantRequest struct {
@@ -41,37 +64,112 @@ type UserUpdatePlantRequest struct {
 	Name         *string    `json:"plantName"`
 }
 
-// performs authentication flow for normal users
-func (cfg *apiConfig) userTokenAuthFlow(header http.Header, _ http.ResponseWriter) (uuid.UUID, error) {
-	accessTokenProvided, err := auth.GetBearerToken(header, cfg.sl)
-	if err != nil {
-		cfg.sl.Debug("Could not get token from headers", "error", err)
-		return uuid.Nil, nil
+// UserPlantCareOverrides are the per plant care overrides, used for both requests and responses.
+// null properties fall back to the species default.
+type UserPlantCareOverrides struct {
+	WaterIntervalDays      *int32     `json:"waterIntervalDays"`
+	LightNeedsID           *uuid.UUID `json:"lightNeedsID"`
+	FertilizerIntervalDays *int32     `json:"fertilizerIntervalDays"`
+}
+
+// UserPlantCareResponse is the effective care of a users plant,
+// the override if there is one, otherwise the species default.
+type UserPlantCareResponse struct {
+	WaterSource            string     `json:"waterSource"`
+	WaterIntervalDays      *int32     `json:"waterIntervalDays,omitempty"`
+	WaterDrySoilMM         *int32     `json:"waterDrySoilMM,omitempty"`
+	LightSource            string     `json:"lightSource"`
+	LightNeedsID           *uuid.UUID `json:"lightNeedsID,omitempty"`
+	LightName              *string    `json:"lightName,omitempty"`
+	FertilizerSource       string     `json:"fertilizerSource"`
+	FertilizerIntervalDays *int32     `json:"fertilizerIntervalDays,omitempty"`
+}
+
+// where an effective care value came from
+const (
+	careSourceOverride = "override"
+	careSourceSpecies  = "species"
+	careSourceNone     = "none"
+)
+
+// usersPlantCareRecord holds the care columns shared by the users plant queries
+type usersPlantCareRecord struct {
+	WaterIntervalDays      sql.NullInt32
+	LightNeedsID           uuid.NullUUID
+	FertilizerIntervalDays sql.NullInt32
+	SpeciesDrySoilDays     sql.NullInt32
+	SpeciesDrySoilMm       sql.NullInt32
+	SpeciesLightNeedsID    uuid.NullUUID
+	LightNeedName          sql.NullString
+}
+
+// resolves the overrides and effective care of a users plant
+func (record usersPlantCareRecord) toResponse() (UserPlantCareOverrides, UserPlantCareResponse) {
+	overrides := UserPlantCareOverrides{}
+	care := UserPlantCareResponse{
+		WaterSource:      careSourceNone,
+		LightSource:      careSourceNone,
+		FertilizerSource: careSourceNone,
 	}
 
-	userID, err := auth.ValidateJWT(accessTokenProvided, cfg.JWTSecret, cfg.sl)
-	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
-		return uuid.Nil, nil
+	// an interval override replaces the species water need entirely
+	if record.WaterIntervalDays.Valid {
+		overrides.WaterIntervalDays = &record.WaterIntervalDays.Int32
+		care.WaterSource = careSourceOverride
+		care.WaterIntervalDays = &record.WaterIntervalDays.Int32
+	} else if record.SpeciesDrySoilDays.Valid || record.SpeciesDrySoilMm.Valid {
+		care.WaterSource = careSourceSpecies
+		if record.SpeciesDrySoilDays.Valid {
+			care.WaterIntervalDays = &record.SpeciesDrySoilDays.Int32
+		}
+		if record.SpeciesDrySoilMm.Valid {
+			care.WaterDrySoilMM = &record.SpeciesDrySoilMm.Int32
+		}
 	}
-	return userID, nil
+
+	// the light need name is already joined on the effective light need
+	if record.LightNeedsID.Valid {
+		overrides.LightNeedsID = &record.LightNeedsID.UUID
+		care.LightSource = careSourceOverride
+		care.LightNeedsID = &record.LightNeedsID.UUID
+	} else if record.SpeciesLightNeedsID.Valid {
+		care.LightSource = careSourceSpecies
+		care.LightNeedsID = &record.SpeciesLightNeedsID.UUID
+	}
+	if record.LightNeedName.Valid {
+		care.LightName = &record.LightNeedName.String
+	}
+
+	// species have no fertilizer cadence, so it can only be an override
+	if record.FertilizerIntervalDays.Valid {
+		overrides.FertilizerIntervalDays = &record.FertilizerIntervalDays.Int32
+		care.FertilizerSource = careSourceOverride
+		care.FertilizerIntervalDays = &record.FertilizerIntervalDays.Int32
+	}
+
+	return overrides, care
+}
+
+// checks that the users plant exists and belongs to the requesting user
+// returns sql.ErrNoRows when the plant is missing or owned by someone else
+func (cfg *apiConfig) getOwnedUsersPlant(ctx context.Context, userID, plantID uuid.UUID) (database.GetUsersPlantByIDRow, error) {
+	getParams := database.GetUsersPlantByIDParams{
+		Column1: uuid.NullUUID{UUID: userID, Valid: true},
+		Column2: uuid.NullUUID{UUID: plantID, Valid: true},
+	}
+	return cfg.db.GetUsersPlantByID(ctx, getParams)
 }
 
 // requires access token in auth header
 // creates a user_plant
 func (cfg *apiConfig) usersPlantsCreateHandler(w http.ResponseWriter, r *http.Request) {
-	requestUserID, err := cfg.userTokenAuthFlow(r.Header, w)
-	if err != nil {
-		cfg.sl.Warn("Could not complete user token auth flow during user request")
-		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
-		return
-	}
+	requestUserID := userIDFromContext(r.Context())
 
 	// decode request body
 	var createRequest UserCreatePlantRequest
-	err = json.NewDecoder(r.Body).Decode(&createRequest)
+	err := json.NewDecoder(r.Body).Decode(&createRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode request body")
+		cfg.sl.DebugContext(r.Context(), "Could not decode request body")
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -79,7 +177,7 @@ func (cfg *apiConfig) usersPlantsCreateHandler(w http.ResponseWriter, r *http.Re
 
 	// check request body
 	if createRequest.PlantSpeciesID == uuid.Nil {
-		cfg.sl.Debug("Request missing plant species id property")
+		cfg.sl.DebugContext(r.Context(), "Request missing plant species id property")
 		respondWithError(errors.New("no name property provided"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -112,7 +210,7 @@ func (cfg *apiConfig) usersPlantsCreateHandler(w http.ResponseWriter, r *http.Re
 	// perform database update
 	userPlantRecord, err := cfg.db.CreateUsersPlants(r.Context(), createParams)
 	if err != nil {
-		cfg.sl.Debug("Could not create user's plant record", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not create user's plant record", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
@@ -127,40 +225,70 @@ func (cfg *apiConfig) usersPlantsCreateHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	// perform response
-	cfg.sl.Debug("User successfully created a new users plant", "user id", requestUserID, "users plant id", createResponse.UsersPlantID, "plant species id", createRequest.PlantSpeciesID, "plant species name", userPlantRecord.PlantSpeciesName)
+	cfg.sl.DebugContext(r.Context(), "User successfully created a new users plant", "user id", requestUserID, "users plant id", createResponse.UsersPlantID, "plant species id", createRequest.PlantSpeciesID, "plant species name", userPlantRecord.PlantSpeciesName)
 	respondWithJSON(http.StatusCreated, createResponse, w, cfg.sl)
 }
 
+// queries a page of users plants in the requested order
+func (cfg *apiConfig) getUsersPlantsPage(ctx context.Context, userID uuid.UUID, locationID uuid.NullUUID, page pageRequest) ([]database.GetAllUsersPlantsOrderedByCreatedRow, error) {
+	listParams := database.GetAllUsersPlantsOrderedByCreatedParams{
+		UserID:     userID,
+		LocationID: locationID,
+		CursorTime: page.cursorTime,
+		CursorID:   page.cursorID,
+		RowLimit:   page.rowLimit(),
+	}
+	if page.sort == pageSortCreated {
+		return cfg.db.GetAllUsersPlantsOrderedByCreated(ctx, listParams)
+	}
+
+	updatedRecords, err := cfg.db.GetAllUsersPlantsOrderedByUpdated(ctx, database.GetAllUsersPlantsOrderedByUpdatedParams(listParams))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]database.GetAllUsersPlantsOrderedByCreatedRow, 0, len(updatedRecords))
+	for _, record := range updatedRecords {
+		records = append(records, database.GetAllUsersPlantsOrderedByCreatedRow(record))
+	}
+	return records, nil
+}
+
 // requires access token in auth header
 // returns the users list of plants
+// paginated with 'limit', 'cursor', and 'sort' url query parameters
 func (cfg *apiConfig) usersPlantsListHandler(w http.ResponseWriter, r *http.Request) {
-	accessTokenProvided, err := auth.GetBearerToken(r.Header, cfg.sl)
-	if err != nil {
-		cfg.sl.Debug("Could not get token from headers", "error", err)
-		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
-		return
+	requestUserID := userIDFromContext(r.Context())
+
+	// optional filter by location
+	locationFilter := uuid.NullUUID{}
+	locationFilterStr := r.URL.Query().Get("location")
+	if locationFilterStr != "" {
+		locationID, err := uuid.Parse(locationFilterStr)
+		if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Invalid location was provided in url query", "location", locationFilterStr)
+			respondWithError(errors.New("invalid location provided"), http.StatusBadRequest, w, cfg.sl)
+			return
+		}
+		locationFilter = uuid.NullUUID{UUID: locationID, Valid: true}
 	}
 
-	requestUserID, err := auth.ValidateJWT(accessTokenProvided, cfg.JWTSecret, cfg.sl)
+	page, err := parsePageRequest(r.URL.Query(), pageSortUpdated)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Invalid pagination was provided in url query", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
 	// get list of plants in user_plants table
-	usersPlants, err := cfg.db.GetAllUsersPlantsOrderedByUpdated(r.Context(), requestUserID)
+	usersPlants, err := cfg.getUsersPlantsPage(r.Context(), requestUserID, locationFilter, page)
 	if err != nil {
-		cfg.sl.Debug("Could not get users' plant from database", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not get users' plant from database", "error", err)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
-
-	if len(usersPlants) <= 0 {
-		cfg.sl.Debug("User successfully requested their empty plants list", "user id", requestUserID)
-		respondWithJSON(http.StatusOK, usersPlants, w, cfg.sl)
-		return
-	}
+	usersPlants, nextCursor := nextPage(usersPlants, page, func(record database.GetAllUsersPlantsOrderedByCreatedRow) (time.Time, time.Time, uuid.UUID) {
+		return record.CreatedAt, record.UpdatedAt, record.UsersPlantID
+	})
 
 	// convert from database type to response type
 	viewResponse := make([]UserViewPlantResponse, 0)
@@ -175,39 +303,308 @@ func (cfg *apiConfig) usersPlantsListHandler(w http.ResponseWriter, r *http.Requ
 			plantName = &oldRecord.PlantName.String
 		}
 
+		careOverrides, care := usersPlantCareRecord{
+			WaterIntervalDays:      oldRecord.WaterIntervalDays,
+			LightNeedsID:           oldRecord.LightNeedsID,
+			FertilizerIntervalDays: oldRecord.FertilizerIntervalDays,
+			SpeciesDrySoilDays:     oldRecord.SpeciesDrySoilDays,
+			SpeciesDrySoilMm:       oldRecord.SpeciesDrySoilMm,
+			SpeciesLightNeedsID:    oldRecord.SpeciesLightNeedsID,
+			LightNeedName:          oldRecord.LightNeedName,
+		}.toResponse()
+
+		location, warnings := usersPlantLocation(oldRecord.LocationID, oldRecord.LocationName, environmentRange{
+			MinTemperatureCelsius: oldRecord.LocationMinTemperatureCelsius,
+			MaxTemperatureCelsius: oldRecord.LocationMaxTemperatureCelsius,
+			MinHumidityPercent:    oldRecord.LocationMinHumidityPercent,
+			MaxHumidityPercent:    oldRecord.LocationMaxHumidityPercent,
+		}, environmentRange{
+			MinTemperatureCelsius: oldRecord.PlantTypeMinTemperatureCelsius,
+			MaxTemperatureCelsius: oldRecord.PlantTypeMaxTemperatureCelsius,
+			MinHumidityPercent:    oldRecord.PlantTypeMinHumidityPercent,
+			MaxHumidityPercent:    oldRecord.PlantTypeMaxHumidityPercent,
+		})
+
 		newResponse := UserViewPlantResponse{
 			UsersPlantID:     oldRecord.UsersPlantID,
 			PlantSpeciesID:   oldRecord.PlantSpeciesID,
 			PlantSpeciesName: oldRecord.SpeciesName,
 			AdoptionDate:     adoptionDate,
 			Name:             plantName,
+			CareOverrides:    careOverrides,
+			Care:             care,
+			Location:         location,
+			Warnings:         warnings,
 		}
 		viewResponse = append(viewResponse, newResponse)
 	}
 
-	cfg.sl.Debug("User successfully listed their users plants", "user id", requestUserID)
+	pageResponse := PageResponse[UserViewPlantResponse]{
+		Items:      viewResponse,
+		NextCursor: nextCursor,
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully listed their users plants", "user id", requestUserID)
+	respondWithJSON(http.StatusOK, pageResponse, w, cfg.sl)
+}
+
+// converts the species details of a users plant into the response type
+func newUserViewPlantSpeciesResponse(record database.GetUsersPlantSpeciesDetailsByIDRow) UserViewPlantSpeciesResponse {
+	response := UserViewPlantSpeciesResponse{
+		UserViewAllPlantInfoResponse: UserViewAllPlantInfoResponse{
+			PlantSpeciesID:      record.PlantSpeciesID,
+			PlantSpeciesName:    record.PlantSpeciesName,
+			CommonNamesLangCode: &record.LangCode,
+		},
+	}
+
+	if record.CommonNames.Valid {
+		response.CommonNames = &record.CommonNames.String
+	}
+	if record.HumanPoisonToxic.Valid {
+		response.HumanPoisonToxic = &record.HumanPoisonToxic.Bool
+	}
+	if record.PetPoisonToxic.Valid {
+		response.PetPoisonToxic = &record.PetPoisonToxic.Bool
+	}
+	if record.HumanEdible.Valid {
+		response.HumanEdible = &record.HumanEdible.Bool
+	}
+	if record.PetEdible.Valid {
+		response.PetEdible = &record.PetEdible.Bool
+	}
+	if record.PlantTypeName.Valid {
+		response.PlantTypeName = &record.PlantTypeName.String
+	}
+	if record.PlantTypeDescription.Valid {
+		response.PlantTypeDescription = &record.PlantTypeDescription.String
+	}
+	if record.MaxTemperatureCelsius.Valid {
+		response.MaxTemperatureCelsius = &record.MaxTemperatureCelsius.Int32
+	}
+	if record.MinTemperatureCelsius.Valid {
+		response.MinTemperatureCelsius = &record.MinTemperatureCelsius.Int32
+	}
+	if record.MaxHumidityPercent.Valid {
+		response.MaxHumidityPercent = &record.MaxHumidityPercent.Int32
+	}
+	if record.MinHumidityPercent.Valid {
+		response.MinHumidityPercent = &record.MinHumidityPercent.Int32
+	}
+	if record.SoilOrganicMix.Valid {
+		response.SoilOrganicMix = &record.SoilOrganicMix.String
+	}
+	if record.SoilGritMix.Valid {
+		response.SoilGritMix = &record.SoilGritMix.String
+	}
+	if record.SoilDrainageMix.Valid {
+		response.SoilDrainageMix = &record.SoilDrainageMix.String
+	}
+	if record.LightNeedName.Valid {
+		response.LightNeedName = &record.LightNeedName.String
+	}
+	if record.LightNeedDescription.Valid {
+		response.LightNeedDescription = &record.LightNeedDescription.String
+	}
+	if record.WaterNeedType.Valid {
+		response.WaterNeedName = &record.WaterNeedType.String
+	}
+	if record.WaterNeedDescription.Valid {
+		response.WaterNeedDescription = &record.WaterNeedDescription.String
+	}
+	if record.WaterNeedDrySoilMm.Valid {
+		response.WaterNeedDrySoilMM = &record.WaterNeedDrySoilMm.Int32
+	}
+	if record.WaterNeedDrySoilDays.Valid {
+		response.WaterNeedDrySoilDays = &record.WaterNeedDrySoilDays.Int32
+	}
+
+	return response
+}
+
+// requires access token in auth header
+// returns a single users plant, with the full care info of its species
+// GET /api/v1/my/plants/{plantID}
+func (cfg *apiConfig) usersPlantsViewHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse users plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	usersPlant, err := cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Users plant does not exist", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get users plant from database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	var adoptionDate *time.Time
+	var plantName *string
+
+	if usersPlant.AdoptionDate.Valid {
+		adoptionDate = &usersPlant.AdoptionDate.Time
+	}
+	if usersPlant.PlantName.Valid {
+		plantName = &usersPlant.PlantName.String
+	}
+
+	careOverrides, care := usersPlantCareRecord{
+		WaterIntervalDays:      usersPlant.WaterIntervalDays,
+		LightNeedsID:           usersPlant.LightNeedsID,
+		FertilizerIntervalDays: usersPlant.FertilizerIntervalDays,
+		SpeciesDrySoilDays:     usersPlant.SpeciesDrySoilDays,
+		SpeciesDrySoilMm:       usersPlant.SpeciesDrySoilMm,
+		SpeciesLightNeedsID:    usersPlant.SpeciesLightNeedsID,
+		LightNeedName:          usersPlant.LightNeedName,
+	}.toResponse()
+
+	location, warnings := usersPlantLocation(usersPlant.LocationID, usersPlant.LocationName, environmentRange{
+		MinTemperatureCelsius: usersPlant.LocationMinTemperatureCelsius,
+		MaxTemperatureCelsius: usersPlant.LocationMaxTemperatureCelsius,
+		MinHumidityPercent:    usersPlant.LocationMinHumidityPercent,
+		MaxHumidityPercent:    usersPlant.LocationMaxHumidityPercent,
+	}, environmentRange{
+		MinTemperatureCelsius: usersPlant.PlantTypeMinTemperatureCelsius,
+		MaxTemperatureCelsius: usersPlant.PlantTypeMaxTemperatureCelsius,
+		MinHumidityPercent:    usersPlant.PlantTypeMinHumidityPercent,
+		MaxHumidityPercent:    usersPlant.PlantTypeMaxHumidityPercent,
+	})
+
+	detailsParams := database.GetUsersPlantSpeciesDetailsByIDParams{
+		UserID: requestUserID,
+		ID:     plantID,
+	}
+	speciesRecord, err := cfg.db.GetUsersPlantSpeciesDetailsByID(r.Context(), detailsParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get species details of users plant from database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	viewResponse := UserViewPlantDetailsResponse{
+		UserViewPlantResponse: UserViewPlantResponse{
+			UsersPlantID:     usersPlant.UsersPlantID,
+			PlantSpeciesID:   usersPlant.PlantSpeciesID,
+			PlantSpeciesName: usersPlant.SpeciesName,
+			AdoptionDate:     adoptionDate,
+			Name:             plantName,
+			CareOverrides:    careOverrides,
+			Care:             care,
+			Location:         location,
+			Warnings:         warnings,
+		},
+		Species: newUserViewPlantSpeciesResponse(speciesRecord),
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully viewed users plant", "user id", requestUserID, "users plant id", plantID)
 	respondWithJSON(http.StatusOK, viewResponse, w, cfg.sl)
 }
 
-func (cfg *apiConfig) userPlantsUpdateHandler(w http.ResponseWriter, r *http.Request) {
-	accessTokenProvided, err := auth.GetBearerToken(r.Header, cfg.sl)
+// requires access token in auth header
+// replaces the care overrides of a users plant
+// PUT /api/v1/my/plants/{plantID}/care
+func (cfg *apiConfig) usersPlantsCareUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not get token from headers", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse users plant id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	requestUserID, err := auth.ValidateJWT(accessTokenProvided, cfg.JWTSecret, cfg.sl)
+	var careRequest UserPlantCareOverrides
+	err = json.NewDecoder(r.Body).Decode(&careRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
+	defer r.Body.Close()
+
+	// check request body
+	if careRequest.WaterIntervalDays != nil && *careRequest.WaterIntervalDays <= 0 {
+		cfg.sl.DebugContext(r.Context(), "Request body has non positive watering interval")
+		respondWithError(errors.New("watering interval must be at least one day"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	if careRequest.FertilizerIntervalDays != nil && *careRequest.FertilizerIntervalDays <= 0 {
+		cfg.sl.DebugContext(r.Context(), "Request body has non positive fertilizer interval")
+		respondWithError(errors.New("fertilizer interval must be at least one day"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	newWaterInterval := sql.NullInt32{}
+	newLightNeedsID := uuid.NullUUID{}
+	newFertilizerInterval := sql.NullInt32{}
+
+	if careRequest.WaterIntervalDays != nil {
+		newWaterInterval.Valid = true
+		newWaterInterval.Int32 = *careRequest.WaterIntervalDays
+	}
+	if careRequest.LightNeedsID != nil {
+		_, err = cfg.db.GetLightNeedByID(r.Context(), *careRequest.LightNeedsID)
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.sl.DebugContext(r.Context(), "Request body has non existent light need", "light needs id", *careRequest.LightNeedsID)
+			respondWithError(errors.New("light need does not exist"), http.StatusBadRequest, w, cfg.sl)
+			return
+		} else if err != nil {
+			cfg.sl.DebugContext(r.Context(), "Unable to check for light need in database", "error", err)
+			respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+			return
+		}
+
+		newLightNeedsID.Valid = true
+		newLightNeedsID.UUID = *careRequest.LightNeedsID
+	}
+	if careRequest.FertilizerIntervalDays != nil {
+		newFertilizerInterval.Valid = true
+		newFertilizerInterval.Int32 = *careRequest.FertilizerIntervalDays
+	}
+
+	updateParams := database.UpdateUsersPlantCareByIDParams{
+		ID:                     plantID,
+		UpdatedBy:              requestUserID,
+		WaterIntervalDays:      newWaterInterval,
+		LightNeedsID:           newLightNeedsID,
+		FertilizerIntervalDays: newFertilizerInterval,
+	}
+	rowsUpdated, err := cfg.db.UpdateUsersPlantCareByID(r.Context(), updateParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not update users plant care overrides", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsUpdated == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot update care of non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully updated users plant care overrides", "user id", requestUserID, "users plant id", plantID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requires access token in auth header
+// updates a users plant, only when it belongs to the requesting user
+// PUT /api/v1/my/plants/{plantID}
+func (cfg *apiConfig) userPlantsUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
 
 	plantIDStr := r.PathValue("plantID")
 	plantID, err := uuid.Parse(plantIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant type id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant type id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -215,14 +612,14 @@ func (cfg *apiConfig) userPlantsUpdateHandler(w http.ResponseWriter, r *http.Req
 	var updateRequest UserUpdatePlantRequest
 	err = json.NewDecoder(r.Body).Decode(&updateRequest)
 	if err != nil {
-		cfg.sl.Debug("Could not decode body of request", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not decode body of request", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	defer r.Body.Close()
 
 	if updateRequest.AdoptionDate == nil && updateRequest.Name == nil {
-		cfg.sl.Debug("No updates provided in request")
+		cfg.sl.DebugContext(r.Context(), "No updates provided in request")
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
@@ -249,68 +646,53 @@ func (cfg *apiConfig) userPlantsUpdateHandler(w http.ResponseWriter, r *http.Req
 		AdoptionDate: newAdoptionDate,
 		Name:         newName,
 	}
-	err = cfg.db.UpdateUsersPlantByID(r.Context(), updateParams)
+	rowsUpdated, err := cfg.db.UpdateUsersPlantByID(r.Context(), updateParams)
 	if err != nil {
-		cfg.sl.Debug("Could not update users plant record", "error", err, "users plant id", plantID)
+		cfg.sl.DebugContext(r.Context(), "Could not update users plant record", "error", err, "users plant id", plantID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
+	if rowsUpdated == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot update non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
 
-	cfg.sl.Debug("User successfully updated users plant", "user id", requestUserID, "users plant id", plantID)
+	cfg.sl.DebugContext(r.Context(), "User successfully updated users plant", "user id", requestUserID, "users plant id", plantID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// requires access token in auth header
+// deletes a users plant, only when it belongs to the requesting user
+// DELETE /api/v1/my/plants/{plantID}
 func (cfg *apiConfig) userPlantsDeleteHandler(w http.ResponseWriter, r *http.Request) {
-	accessTokenProvided, err := auth.GetBearerToken(r.Header, cfg.sl)
-	if err != nil {
-		cfg.sl.Debug("Could not get token from headers", "error", err)
-		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
-		return
-	}
-
-	requestUserID, err := auth.ValidateJWT(accessTokenProvided, cfg.JWTSecret, cfg.sl)
-	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
-		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
-		return
-	}
+	requestUserID := userIDFromContext(r.Context())
 
 	plantIDStr := r.PathValue("plantID")
 	plantID, err := uuid.Parse(plantIDStr)
 	if err != nil {
-		cfg.sl.Debug("Could not parse plant type id from url path", "error", err)
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant type id from url path", "error", err)
 		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	// check for plant existing
-
-	getParams := database.GetUsersPlantByIDParams{
-		Column1: uuid.NullUUID{UUID: requestUserID, Valid: true},
-		Column2: uuid.NullUUID{UUID: plantID, Valid: true},
-	}
-	_, err = cfg.db.GetUsersPlantByID(r.Context(), getParams)
-	if errors.Is(err, sql.ErrNoRows) {
-		cfg.sl.Debug("Cannot delete non existent record", "users plant id", plantID)
-		respondWithError(errors.New("users plant does not exist"), http.StatusBadRequest, w, cfg.sl)
-		return
-	} else if err != nil {
-		cfg.sl.Debug("Unable to check for record in database", "error", err, "users plant id", plantID)
-		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
-		return
-	}
-
+	// only deletes the plant when it belongs to the requesting user
 	deleteParams := database.DeleteUsersPlantByIDParams{
 		ID:        plantID,
 		DeletedBy: uuid.NullUUID{UUID: requestUserID, Valid: true},
 	}
-	err = cfg.db.DeleteUsersPlantByID(r.Context(), deleteParams)
+	rowsDeleted, err := cfg.db.DeleteUsersPlantByID(r.Context(), deleteParams)
 	if err != nil {
-		cfg.sl.Debug("Could not delete users plant record", "error", err, "users plant id", plantID)
+		cfg.sl.DebugContext(r.Context(), "Could not delete users plant record", "error", err, "users plant id", plantID)
 		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
+	if rowsDeleted == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot delete non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
 
-	cfg.sl.Debug("User successfully deleted users plant", "user id", requestUserID, "users plant id", plantID)
+	cfg.sl.DebugContext(r.Context(), "User successfully deleted users plant", "user id", requestUserID, "users plant id", plantID)
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"time"
+
+	// registers the gif and png decoders with image.Decode
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/blob"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// Only these image formats can be uploaded, mapped to the extension of their blob key.
+var photoContentTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+const (
+	// multipart form field the photo is uploaded in
+	photoFormField = "photo"
+	// longest side of a thumbnail in pixels
+	photoThumbnailSize = 256
+	// refuse images that would take too much memory to decode
+	photoMaxPixels = 40_000_000
+)
+
+// === request response types ===
+
+// UserPlantPhotoResponse is for encoding photo metadata.
+type UserPlantPhotoResponse struct {
+	ID           uuid.UUID `json:"id"`
+	UsersPlantID uuid.UUID `json:"plantID"`
+	ContentType  string    `json:"contentType"`
+	SizeBytes    int64     `json:"sizeBytes"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	CreatedAt    time.Time `json:"createdAt"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnailURL"`
+}
+
+// converts a photo record into the response type
+func newUserPlantPhotoResponse(record database.GetPlantPhotoByIDRow) UserPlantPhotoResponse {
+	photoURL := fmt.Sprintf("/api/v1/my/plants/%s/photos/%s", record.UsersPlantID, record.ID)
+	return UserPlantPhotoResponse{
+		ID:           record.ID,
+		UsersPlantID: record.UsersPlantID,
+		ContentType:  record.ContentType,
+		SizeBytes:    record.SizeBytes,
+		Width:        record.Width,
+		Height:       record.Height,
+		CreatedAt:    record.CreatedAt,
+		URL:          photoURL,
+		ThumbnailURL: photoURL + "/thumbnail",
+	}
+}
+
+// scales the image down so its longest side is at most maxSide,
+// each thumbnail pixel is the average of the source pixels it covers
+func thumbnail(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := srcW, srcH
+	if srcW > maxSide || srcH > maxSide {
+		if srcW >= srcH {
+			dstW, dstH = maxSide, max(1, srcH*maxSide/srcW)
+		} else {
+			dstW, dstH = max(1, srcW*maxSide/srcH), maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := range dstH {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/dstH)
+		for x := range dstW {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/dstW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
+
+// writes a stored blob to the client
+func (cfg *apiConfig) serveBlob(w http.ResponseWriter, r *http.Request, key, contentType string) {
+	blobReader, err := cfg.blobs.Get(r.Context(), key)
+	if errors.Is(err, blob.ErrNotFound) {
+		cfg.sl.DebugContext(r.Context(), "Blob for photo is missing from store", "blob key", key)
+		respondWithError(errors.New("photo does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get blob from store", "error", err, "blob key", key)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	defer blobReader.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, blobReader)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not write blob to client", "error", err, "blob key", key)
+	}
+}
+
+// === handler functions ===
+
+// POST /api/v1/my/plants/{plantID}/photos
+// accepts a multipart form with the image in the 'photo' field
+func (cfg *apiConfig) usersPlantPhotosCreateHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// check for plant existing and belonging to user
+	_, err = cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot upload photo for non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// limit the whole body, leaving some room for the multipart framing
+	r.Body = http.MaxBytesReader(w, r.Body, cfg.photoMaxBytes+1<<20)
+	defer r.Body.Close()
+
+	photoFile, _, err := r.FormFile(photoFormField)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		cfg.sl.DebugContext(r.Context(), "Photo upload is too large", "error", err)
+		respondWithError(err, http.StatusRequestEntityTooLarge, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get photo from multipart form", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	defer photoFile.Close()
+	defer r.MultipartForm.RemoveAll()
+
+	photoData, err := io.ReadAll(io.LimitReader(photoFile, cfg.photoMaxBytes+1))
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not read photo from multipart form", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	if int64(len(photoData)) > cfg.photoMaxBytes {
+		cfg.sl.DebugContext(r.Context(), "Photo upload is too large", "max bytes", cfg.photoMaxBytes)
+		respondWithError(errors.New("photo is too large"), http.StatusRequestEntityTooLarge, w, cfg.sl)
+		return
+	}
+
+	// the content type is sniffed rather than trusting the client
+	contentType := http.DetectContentType(photoData)
+	extension, ok := photoContentTypes[contentType]
+	if !ok {
+		cfg.sl.DebugContext(r.Context(), "Photo upload has unsupported content type", "content type", contentType)
+		respondWithError(errors.New("unsupported photo content type"), http.StatusUnsupportedMediaType, w, cfg.sl)
+		return
+	}
+
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(photoData))
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode photo", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	if imageConfig.Width*imageConfig.Height > photoMaxPixels {
+		cfg.sl.DebugContext(r.Context(), "Photo upload has too many pixels", "width", imageConfig.Width, "height", imageConfig.Height)
+		respondWithError(errors.New("photo dimensions are too large"), http.StatusRequestEntityTooLarge, w, cfg.sl)
+		return
+	}
+
+	decodedImage, _, err := image.Decode(bytes.NewReader(photoData))
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not decode photo", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	var thumbnailData bytes.Buffer
+	err = jpeg.Encode(&thumbnailData, thumbnail(decodedImage, photoThumbnailSize), &jpeg.Options{Quality: 80})
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not encode photo thumbnail", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// store blobs before the record, so a record never points at a missing blob
+	photoID := uuid.New()
+	blobKey := fmt.Sprintf("photos/%s/%s/%s.%s", requestUserID, plantID, photoID, extension)
+	thumbnailKey := fmt.Sprintf("photos/%s/%s/%s_thumb.jpg", requestUserID, plantID, photoID)
+
+	err = cfg.blobs.Put(r.Context(), blobKey, photoData, contentType)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not store photo", "error", err, "blob key", blobKey)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	err = cfg.blobs.Put(r.Context(), thumbnailKey, thumbnailData.Bytes(), "image/jpeg")
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not store photo thumbnail", "error", err, "blob key", thumbnailKey)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	createParams := database.CreatePlantPhotoParams{
+		ID:           photoID,
+		CreatedBy:    requestUserID,
+		UsersPlantID: plantID,
+		ContentType:  contentType,
+		SizeBytes:    int64(len(photoData)),
+		Width:        int32(imageConfig.Width),
+		Height:       int32(imageConfig.Height),
+		BlobKey:      blobKey,
+		ThumbnailKey: thumbnailKey,
+	}
+	photoRecord, err := cfg.db.CreatePlantPhoto(r.Context(), createParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not create photo record", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	photoResponse := newUserPlantPhotoResponse(database.GetPlantPhotoByIDRow(photoRecord))
+
+	cfg.sl.DebugContext(r.Context(), "User successfully uploaded photo", "user id", requestUserID, "photo id", photoID)
+	respondWithJSON(http.StatusCreated, photoResponse, w, cfg.sl)
+}
+
+// GET /api/v1/my/plants/{plantID}/photos
+func (cfg *apiConfig) usersPlantPhotosListHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// check for plant existing and belonging to user
+	_, err = cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot list photos for non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	photoRecords, err := cfg.db.GetAllPlantPhotosByUsersPlantID(r.Context(), plantID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get photos from database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	photoResponses := make([]UserPlantPhotoResponse, 0)
+	for _, record := range photoRecords {
+		photoResponses = append(photoResponses, newUserPlantPhotoResponse(database.GetPlantPhotoByIDRow(record)))
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully listed photos", "user id", requestUserID, "users plant id", plantID)
+	respondWithJSON(http.StatusOK, photoResponses, w, cfg.sl)
+}
+
+// GET /api/v1/my/plants/{plantID}/photos/{photoID}
+// responds with the image itself rather than json
+func (cfg *apiConfig) usersPlantPhotosViewHandler(w http.ResponseWriter, r *http.Request) {
+	cfg.usersPlantPhotoServe(w, r, false)
+}
+
+// GET /api/v1/my/plants/{plantID}/photos/{photoID}/thumbnail
+// responds with the jpeg thumbnail rather than json
+func (cfg *apiConfig) usersPlantPhotosThumbnailHandler(w http.ResponseWriter, r *http.Request) {
+	cfg.usersPlantPhotoServe(w, r, true)
+}
+
+// shared by the photo and thumbnail handlers
+func (cfg *apiConfig) usersPlantPhotoServe(w http.ResponseWriter, r *http.Request, serveThumbnail bool) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	photoIDStr := r.PathValue("photoID")
+	photoID, err := uuid.Parse(photoIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse photo id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// check for plant existing and belonging to user
+	_, err = cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot get photo for non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	getParams := database.GetPlantPhotoByIDParams{
+		ID:           photoID,
+		UsersPlantID: plantID,
+	}
+	photoRecord, err := cfg.db.GetPlantPhotoByID(r.Context(), getParams)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot get non existent photo", "photo id", photoID)
+		respondWithError(errors.New("photo does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "photo id", photoID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	if serveThumbnail {
+		cfg.serveBlob(w, r, photoRecord.ThumbnailKey, "image/jpeg")
+		return
+	}
+	cfg.serveBlob(w, r, photoRecord.BlobKey, photoRecord.ContentType)
+}
+
+// DELETE /api/v1/my/plants/{plantID}/photos/{photoID}
+// the blobs are kept, as with other soft deleted records
+func (cfg *apiConfig) usersPlantPhotosDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	plantIDStr := r.PathValue("plantID")
+	plantID, err := uuid.Parse(plantIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse plant id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	photoIDStr := r.PathValue("photoID")
+	photoID, err := uuid.Parse(photoIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse photo id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// check for plant existing and belonging to user
+	_, err = cfg.getOwnedUsersPlant(r.Context(), requestUserID, plantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		cfg.sl.DebugContext(r.Context(), "Cannot delete photo for non existent users plant", "users plant id", plantID)
+		respondWithError(errors.New("users plant does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	} else if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Unable to check for record in database", "error", err, "users plant id", plantID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	deleteParams := database.DeletePlantPhotoByIDParams{
+		ID:           photoID,
+		UsersPlantID: plantID,
+		DeletedBy:    uuid.NullUUID{UUID: requestUserID, Valid: true},
+	}
+	rowsDeleted, err := cfg.db.DeletePlantPhotoByID(r.Context(), deleteParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not delete photo record", "error", err, "photo id", photoID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsDeleted == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot delete non existent photo", "photo id", photoID)
+		respondWithError(errors.New("photo does not exist"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully deleted photo", "user id", requestUserID, "photo id", photoID)
+	w.WriteHeader(http.StatusNoContent)
+}
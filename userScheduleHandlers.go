@@ -0,0 +1,269 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// Plants are watered in one of two ways (see adminWaterHandlers.go):
+//
+// x number of days between watering
+// - Semi-Arid
+// - Arid
+// these get a hard due date based on the last recorded watering.
+//
+// x millimeters of soil should be dry before watering
+// - Tropical
+// - Temperate
+// these cannot be predicted, so the user is reminded to check the soil
+// every checkSoilIntervalDays instead.
+
+// how often a user is reminded to check the soil of mm-of-dry-soil plants
+const checkSoilIntervalDays = 3
+
+// default and maximum number of days ahead that upcoming waterings are listed
+const (
+	defaultScheduleHorizonDays = 7
+	maxScheduleHorizonDays     = 90
+)
+
+// watering models
+const (
+	waterModelDays = "days"
+	waterModelMM   = "mm"
+)
+
+// watering schedule statuses
+const (
+	scheduleStatusOverdue     = "overdue"
+	scheduleStatusDueToday    = "due-today"
+	scheduleStatusUpcoming    = "upcoming"
+	scheduleStatusCheckSoil   = "check-soil"
+	scheduleStatusUnscheduled = "unscheduled"
+)
+
+// === schedule computation ===
+
+// wateringScheduleInput is everything needed to work out when a plant is next due.
+type wateringScheduleInput struct {
+	LastWateredAt *time.Time
+	// adoption date of the plant, or when it was added if there is none
+	StartedAt   time.Time
+	DrySoilDays *int32
+	DrySoilMM   *int32
+}
+
+// wateringSchedule is the computed watering schedule of a single plant.
+type wateringSchedule struct {
+	Status             string
+	WaterModel         string
+	NextDueAt          *time.Time
+	DaysUntilDue       *int
+	CheckSoilEveryDays *int32
+}
+
+// converts a schedule record from the database into the computation input
+func wateringScheduleInputFromRecord(record database.GetUsersPlantsWateringScheduleRow) wateringScheduleInput {
+	input := wateringScheduleInput{StartedAt: record.CreatedAt}
+
+	if record.AdoptionDate.Valid {
+		input.StartedAt = record.AdoptionDate.Time
+	}
+	if record.LastWateredAt.Valid {
+		input.LastWateredAt = &record.LastWateredAt.Time
+	}
+	if record.WaterNeedDrySoilDays.Valid {
+		input.DrySoilDays = &record.WaterNeedDrySoilDays.Int32
+	}
+	if record.WaterNeedDrySoilMm.Valid {
+		input.DrySoilMM = &record.WaterNeedDrySoilMm.Int32
+	}
+
+	// a users watering interval override replaces the species water need entirely
+	if record.WaterIntervalDays.Valid {
+		input.DrySoilDays = &record.WaterIntervalDays.Int32
+		input.DrySoilMM = nil
+	}
+
+	return input
+}
+
+// returns the number of calendar days (UTC) from a to b
+func daysBetween(a, b time.Time) int {
+	dayA := time.Date(a.UTC().Year(), a.UTC().Month(), a.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.UTC().Year(), b.UTC().Month(), b.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	return int(dayB.Sub(dayA).Hours() / 24)
+}
+
+// works out the next watering (or soil check) of a plant relative to now
+func computeWateringSchedule(in wateringScheduleInput, now time.Time) wateringSchedule {
+	lastWatered := in.StartedAt
+	if in.LastWateredAt != nil {
+		lastWatered = *in.LastWateredAt
+	}
+
+	// interval watering gets a hard due date
+	if in.DrySoilDays != nil {
+		nextDueAt := lastWatered.AddDate(0, 0, int(*in.DrySoilDays))
+		daysUntilDue := daysBetween(now, nextDueAt)
+
+		status := scheduleStatusUpcoming
+		if daysUntilDue < 0 {
+			status = scheduleStatusOverdue
+		} else if daysUntilDue == 0 {
+			status = scheduleStatusDueToday
+		}
+
+		return wateringSchedule{
+			Status:       status,
+			WaterModel:   waterModelDays,
+			NextDueAt:    &nextDueAt,
+			DaysUntilDue: &daysUntilDue,
+		}
+	}
+
+	// soil depth watering gets a check soil reminder cadence
+	if in.DrySoilMM != nil {
+		cadence := int32(checkSoilIntervalDays)
+		nextCheckAt := lastWatered.AddDate(0, 0, checkSoilIntervalDays)
+		if in.LastWateredAt == nil || nextCheckAt.Before(now) {
+			nextCheckAt = now
+		}
+		daysUntilCheck := daysBetween(now, nextCheckAt)
+
+		return wateringSchedule{
+			Status:             scheduleStatusCheckSoil,
+			WaterModel:         waterModelMM,
+			NextDueAt:          &nextCheckAt,
+			DaysUntilDue:       &daysUntilCheck,
+			CheckSoilEveryDays: &cadence,
+		}
+	}
+
+	// species has no water need linked
+	return wateringSchedule{Status: scheduleStatusUnscheduled}
+}
+
+// === request response types ===
+
+// UserScheduleEntryResponse is for encoding a single plant within the watering schedule.
+type UserScheduleEntryResponse struct {
+	UsersPlantID       uuid.UUID  `json:"id"`
+	Name               *string    `json:"plantName,omitempty"`
+	PlantSpeciesID     uuid.UUID  `json:"plantSpeciesID"`
+	PlantSpeciesName   string     `json:"plantSpeciesName"`
+	Status             string     `json:"status"`
+	WaterModel         string     `json:"waterModel,omitempty"`
+	LastWateredAt      *time.Time `json:"lastWateredAt,omitempty"`
+	NextDueAt          *time.Time `json:"nextDueAt,omitempty"`
+	DaysUntilDue       *int       `json:"daysUntilDue,omitempty"`
+	DrySoilDays        *int32     `json:"drySoilDays,omitempty"`
+	DrySoilMM          *int32     `json:"drySoilMM,omitempty"`
+	CheckSoilEveryDays *int32     `json:"checkSoilEveryDays,omitempty"`
+}
+
+// UserScheduleResponse is for encoding the watering schedule of all of a users plants.
+type UserScheduleResponse struct {
+	Overdue     []UserScheduleEntryResponse `json:"overdue"`
+	DueToday    []UserScheduleEntryResponse `json:"dueToday"`
+	Upcoming    []UserScheduleEntryResponse `json:"upcoming"`
+	CheckSoil   []UserScheduleEntryResponse `json:"checkSoil"`
+	Unscheduled []UserScheduleEntryResponse `json:"unscheduled"`
+}
+
+// orders schedule entries soonest due first
+func compareScheduleEntries(a, b UserScheduleEntryResponse) int {
+	if a.NextDueAt == nil || b.NextDueAt == nil {
+		return 0
+	}
+	return a.NextDueAt.Compare(*b.NextDueAt)
+}
+
+// === handler functions ===
+
+// GET /api/v1/my/schedule
+// optional query param 'days' limits how far ahead upcoming waterings are listed
+func (cfg *apiConfig) usersScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	horizonDays := defaultScheduleHorizonDays
+	horizonDaysStr := r.URL.Query().Get("days")
+	if horizonDaysStr != "" {
+		var err error
+		horizonDays, err = strconv.Atoi(horizonDaysStr)
+		if err != nil || horizonDays < 0 || horizonDays > maxScheduleHorizonDays {
+			cfg.sl.DebugContext(r.Context(), "Invalid days was provided in url query", "days", horizonDaysStr)
+			respondWithError(errors.New("invalid days provided"), http.StatusBadRequest, w, cfg.sl)
+			return
+		}
+	}
+
+	scheduleRecords, err := cfg.db.GetUsersPlantsWateringSchedule(r.Context(), requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get users plants watering schedule from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	scheduleResponse := UserScheduleResponse{
+		Overdue:     make([]UserScheduleEntryResponse, 0),
+		DueToday:    make([]UserScheduleEntryResponse, 0),
+		Upcoming:    make([]UserScheduleEntryResponse, 0),
+		CheckSoil:   make([]UserScheduleEntryResponse, 0),
+		Unscheduled: make([]UserScheduleEntryResponse, 0),
+	}
+
+	now := time.Now().UTC()
+	for _, record := range scheduleRecords {
+		var plantName *string
+		if record.PlantName.Valid {
+			plantName = &record.PlantName.String
+		}
+
+		input := wateringScheduleInputFromRecord(record)
+		schedule := computeWateringSchedule(input, now)
+
+		entry := UserScheduleEntryResponse{
+			UsersPlantID:       record.UsersPlantID,
+			Name:               plantName,
+			PlantSpeciesID:     record.PlantSpeciesID,
+			PlantSpeciesName:   record.SpeciesName,
+			Status:             schedule.Status,
+			WaterModel:         schedule.WaterModel,
+			LastWateredAt:      input.LastWateredAt,
+			NextDueAt:          schedule.NextDueAt,
+			DaysUntilDue:       schedule.DaysUntilDue,
+			DrySoilDays:        input.DrySoilDays,
+			DrySoilMM:          input.DrySoilMM,
+			CheckSoilEveryDays: schedule.CheckSoilEveryDays,
+		}
+
+		switch schedule.Status {
+		case scheduleStatusOverdue:
+			scheduleResponse.Overdue = append(scheduleResponse.Overdue, entry)
+		case scheduleStatusDueToday:
+			scheduleResponse.DueToday = append(scheduleResponse.DueToday, entry)
+		case scheduleStatusUpcoming:
+			if *schedule.DaysUntilDue <= horizonDays {
+				scheduleResponse.Upcoming = append(scheduleResponse.Upcoming, entry)
+			}
+		case scheduleStatusCheckSoil:
+			scheduleResponse.CheckSoil = append(scheduleResponse.CheckSoil, entry)
+		default:
+			scheduleResponse.Unscheduled = append(scheduleResponse.Unscheduled, entry)
+		}
+	}
+
+	slices.SortStableFunc(scheduleResponse.Overdue, compareScheduleEntries)
+	slices.SortStableFunc(scheduleResponse.Upcoming, compareScheduleEntries)
+	slices.SortStableFunc(scheduleResponse.CheckSoil, compareScheduleEntries)
+
+	cfg.sl.DebugContext(r.Context(), "User successfully viewed their watering schedule", "user id", requestUserID)
+	respondWithJSON(http.StatusOK, scheduleResponse, w, cfg.sl)
+}
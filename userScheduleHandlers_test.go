@@ -0,0 +1,144 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+func TestComputeWateringSchedule(t *testing.T) {
+	// just past midnight in UTC
+	now := time.Date(2025, 7, 2, 0, 30, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour, min int) *time.Time {
+		t := time.Date(2025, month, day, hour, min, 0, 0, time.UTC)
+		return &t
+	}
+	days := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name         string
+		in           wateringScheduleInput
+		status       string
+		model        string
+		nextDueAt    *time.Time
+		daysUntilDue *int
+	}{
+		{
+			name:         "overdue",
+			in:           wateringScheduleInput{LastWateredAt: at(6, 27, 10, 0), DrySoilDays: days(3)},
+			status:       scheduleStatusOverdue,
+			model:        waterModelDays,
+			nextDueAt:    at(6, 30, 10, 0),
+			daysUntilDue: intPtr(-2),
+		},
+		{
+			name:         "due minutes ago, before midnight, is overdue",
+			in:           wateringScheduleInput{LastWateredAt: at(6, 28, 23, 59), DrySoilDays: days(3)},
+			status:       scheduleStatusOverdue,
+			model:        waterModelDays,
+			nextDueAt:    at(7, 1, 23, 59),
+			daysUntilDue: intPtr(-1),
+		},
+		{
+			name:         "due late tonight is due today",
+			in:           wateringScheduleInput{LastWateredAt: at(6, 29, 23, 50), DrySoilDays: days(3)},
+			status:       scheduleStatusDueToday,
+			model:        waterModelDays,
+			nextDueAt:    at(7, 2, 23, 50),
+			daysUntilDue: intPtr(0),
+		},
+		{
+			name:         "upcoming",
+			in:           wateringScheduleInput{LastWateredAt: at(7, 1, 12, 0), DrySoilDays: days(7)},
+			status:       scheduleStatusUpcoming,
+			model:        waterModelDays,
+			nextDueAt:    at(7, 8, 12, 0),
+			daysUntilDue: intPtr(6),
+		},
+		{
+			name:         "never watered counts from when the plant was started",
+			in:           wateringScheduleInput{StartedAt: *at(6, 30, 9, 0), DrySoilDays: days(5)},
+			status:       scheduleStatusUpcoming,
+			model:        waterModelDays,
+			nextDueAt:    at(7, 5, 9, 0),
+			daysUntilDue: intPtr(3),
+		},
+		{
+			name:         "check soil after the cadence since watering",
+			in:           wateringScheduleInput{LastWateredAt: at(7, 1, 12, 0), DrySoilMM: days(25)},
+			status:       scheduleStatusCheckSoil,
+			model:        waterModelMM,
+			nextDueAt:    at(7, 4, 12, 0),
+			daysUntilDue: intPtr(2),
+		},
+		{
+			name:         "check soil that is past due is clamped to now",
+			in:           wateringScheduleInput{LastWateredAt: at(6, 20, 12, 0), DrySoilMM: days(25)},
+			status:       scheduleStatusCheckSoil,
+			model:        waterModelMM,
+			nextDueAt:    &now,
+			daysUntilDue: intPtr(0),
+		},
+		{
+			name:         "check soil of a plant never watered is now",
+			in:           wateringScheduleInput{StartedAt: *at(7, 1, 12, 0), DrySoilMM: days(25)},
+			status:       scheduleStatusCheckSoil,
+			model:        waterModelMM,
+			nextDueAt:    &now,
+			daysUntilDue: intPtr(0),
+		},
+		{
+			name:   "no water need",
+			in:     wateringScheduleInput{LastWateredAt: at(7, 1, 12, 0)},
+			status: scheduleStatusUnscheduled,
+		},
+	}
+
+	for _, test := range tests {
+		got := computeWateringSchedule(test.in, now)
+
+		if got.Status != test.status || got.WaterModel != test.model {
+			t.Errorf("%s: status %q and model %q, want %q and %q", test.name, got.Status, got.WaterModel, test.status, test.model)
+		}
+		if !equalPtr(got.NextDueAt, test.nextDueAt, func(a, b time.Time) bool { return a.Equal(b) }) {
+			t.Errorf("%s: next due at %v, want %v", test.name, got.NextDueAt, test.nextDueAt)
+		}
+		if !equalPtr(got.DaysUntilDue, test.daysUntilDue, func(a, b int) bool { return a == b }) {
+			t.Errorf("%s: days until due %v, want %v", test.name, got.DaysUntilDue, test.daysUntilDue)
+		}
+		if (got.WaterModel == waterModelMM) != (got.CheckSoilEveryDays != nil) {
+			t.Errorf("%s: check soil every %v days with model %q", test.name, got.CheckSoilEveryDays, got.WaterModel)
+		}
+	}
+}
+
+func TestWateringScheduleInputOverride(t *testing.T) {
+	record := database.GetUsersPlantsWateringScheduleRow{
+		CreatedAt:          time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		AdoptionDate:       sql.NullTime{Time: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		WaterNeedDrySoilMm: sql.NullInt32{Int32: 25, Valid: true},
+		WaterIntervalDays:  sql.NullInt32{Int32: 4, Valid: true},
+	}
+
+	in := wateringScheduleInputFromRecord(record)
+	if !in.StartedAt.Equal(record.AdoptionDate.Time) {
+		t.Errorf("started at %v, want the adoption date %v", in.StartedAt, record.AdoptionDate.Time)
+	}
+	if in.DrySoilMM != nil || in.DrySoilDays == nil || *in.DrySoilDays != 4 {
+		t.Errorf("dry soil mm %v and days %v, want the override of 4 days only", in.DrySoilMM, in.DrySoilDays)
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// compares two optional values, which are equal when both are nil
+func equalPtr[T any](a, b *T, equal func(T, T) bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return equal(*a, *b)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+// longest user agent stored for a session, longer ones are cut
+const sessionUserAgentMaxLength = 512
+
+// === request response types ===
+
+// UserSessionResponse is for encoding a single session, one per login.
+type UserSessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	UserAgent  *string   `json:"userAgent,omitempty"`
+	IPAddress  *string   `json:"ipAddress,omitempty"`
+	Current    bool      `json:"current"`
+}
+
+// returns the user agent and address of the device that is logging in or refreshing
+func sessionDevice(r *http.Request) (sql.NullString, sql.NullString) {
+	userAgent := r.UserAgent()
+	if len(userAgent) > sessionUserAgentMaxLength {
+		userAgent = userAgent[:sessionUserAgentMaxLength]
+	}
+	ipAddress := clientIP(r)
+
+	return sql.NullString{String: userAgent, Valid: userAgent != ""},
+		sql.NullString{String: ipAddress, Valid: ipAddress != ""}
+}
+
+// === session handlers ===
+
+// requires access token in auth header
+// lists the sessions that have not been revoked or expired, most recently used first
+// GET /api/v1/my/sessions
+func (cfg *apiConfig) usersSessionsListHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+	requestSessionID := sessionIDFromContext(r.Context())
+
+	sessionRecords, err := cfg.db.GetAllActiveSessionsByUserID(r.Context(), requestUserID)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not get sessions from database", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	sessionResponses := make([]UserSessionResponse, 0)
+	for _, record := range sessionRecords {
+		response := UserSessionResponse{
+			ID:         record.FamilyID,
+			CreatedAt:  record.StartedAt,
+			LastUsedAt: record.LastUsedAt,
+			ExpiresAt:  record.ExpiresAt,
+			UserAgent:  nullStringPtr(record.UserAgent),
+			IPAddress:  nullStringPtr(record.IpAddress),
+			Current:    record.FamilyID == requestSessionID,
+		}
+		sessionResponses = append(sessionResponses, response)
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully listed their sessions", "user id", requestUserID)
+	respondWithJSON(http.StatusOK, sessionResponses, w, cfg.sl)
+}
+
+// requires access token in auth header
+// revokes the refresh tokens of a session, logging out the device
+// DELETE /api/v1/my/sessions/{sessionID}
+func (cfg *apiConfig) usersSessionsDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	sessionIDStr := r.PathValue("sessionID")
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not parse session id from url path", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	revokeParams := database.RevokeRefreshTokenFamilyByUserIDParams{
+		FamilyID:  sessionID,
+		UpdatedBy: requestUserID,
+	}
+	rowsRevoked, err := cfg.db.RevokeRefreshTokenFamilyByUserID(r.Context(), revokeParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not revoke session", "error", err, "session id", sessionID)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+	if rowsRevoked == 0 {
+		cfg.sl.DebugContext(r.Context(), "Cannot revoke non existent session", "session id", sessionID)
+		respondWithError(errors.New("session does not exist"), http.StatusNotFound, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully revoked session", "user id", requestUserID, "session id", sessionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requires access token in auth header
+// revokes every session except the one of the access token, logging out everywhere else
+// POST /api/v1/my/sessions/revoke-others
+func (cfg *apiConfig) usersSessionsRevokeOthersHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+	requestSessionID := sessionIDFromContext(r.Context())
+
+	revokeParams := database.RevokeOtherRefreshTokenFamiliesParams{
+		UpdatedBy: requestUserID,
+		FamilyID:  requestSessionID,
+	}
+	rowsRevoked, err := cfg.db.RevokeOtherRefreshTokenFamilies(r.Context(), revokeParams)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not revoke other sessions", "error", err)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	cfg.sl.DebugContext(r.Context(), "User successfully revoked their other sessions", "user id", requestUserID, "session id", requestSessionID, "revoked tokens", rowsRevoked)
+	w.WriteHeader(http.StatusNoContent)
+}
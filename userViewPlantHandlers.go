@@ -1,12 +1,23 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/nicholasss/plantae/internal/auth"
+	"github.com/nicholasss/plantae/internal/database"
+)
+
+const (
+	searchDefaultLimit   = 20
+	searchMaxQueryLength = 100
 )
 
 type UserViewAllPlantInfoResponse struct {
@@ -28,135 +39,326 @@ type UserViewAllPlantInfoResponse struct {
 	WaterNeedDrySoilDays *int32    `json:"waterNeedDrySoilDays,omitempty"`
 }
 
-func (cfg *apiConfig) usersViewPlantsListHandler(w http.ResponseWriter, r *http.Request) {
-	accessTokenProvided, err := auth.GetBearerToken(r.Header, cfg.sl)
-	if err != nil {
-		cfg.sl.Debug("Could not get token from headers", "error", err)
-		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
-		return
+// converts a catalog plant record into its response, dropping fields that are not set
+func newUserViewAllPlantInfoResponse(record database.GetAllViewPlantsOrderedByCreatedRow) UserViewAllPlantInfoResponse {
+	var commonNamesLangCode *string
+	var commonNames *string
+	var humanPT *bool
+	var petPT *bool
+	var humanE *bool
+	var petE *bool
+	var plantTypeName *string
+	var plantTypeDesc *string
+	var lightNeedName *string
+	var lightNeedDesc *string
+	var waterNeedName *string
+	var waterNeedDesc *string
+	var waterNeedDryMM *int32
+	var waterNeedDryDays *int32
+
+	// NOTE: if this section is providing errors, set the internal/database package to use sql.NullString
+	if record.LangCode.Valid {
+		commonNamesLangCode = &record.LangCode.String
+	}
+	if record.CommonNames.Valid {
+		commonNames = &record.CommonNames.String
+	}
+
+	if record.HumanPoisonToxic.Valid {
+		humanPT = &record.HumanPoisonToxic.Bool
+	}
+	if record.PetPoisonToxic.Valid {
+		petPT = &record.PetPoisonToxic.Bool
+	}
+	if record.HumanEdible.Valid {
+		humanE = &record.HumanEdible.Bool
+	}
+	if record.PetEdible.Valid {
+		petE = &record.PetEdible.Bool
 	}
+	if record.PlantTypeName.Valid {
+		plantTypeName = &record.PlantTypeName.String
+	}
+	if record.PlantTypeDescription.Valid {
+		plantTypeDesc = &record.PlantTypeDescription.String
+	}
+	if record.LightNeedName.Valid {
+		lightNeedName = &record.LightNeedName.String
+	}
+	if record.LightNeedDescription.Valid {
+		lightNeedDesc = &record.LightNeedDescription.String
+	}
+	if record.WaterNeedType.Valid {
+		waterNeedName = &record.WaterNeedType.String
+	}
+	if record.WaterNeedDescription.Valid {
+		waterNeedDesc = &record.WaterNeedDescription.String
+	}
+	if record.WaterNeedDrySoilMm.Valid {
+		waterNeedDryMM = &record.WaterNeedDrySoilMm.Int32
+	}
+	if record.WaterNeedDrySoilDays.Valid {
+		waterNeedDryDays = &record.WaterNeedDrySoilDays.Int32
+	}
+
+	return UserViewAllPlantInfoResponse{
+		PlantSpeciesID:       record.PlantSpeciesID,
+		PlantSpeciesName:     record.PlantSpeciesName,
+		CommonNamesLangCode:  commonNamesLangCode,
+		CommonNames:          commonNames,
+		HumanPoisonToxic:     humanPT,
+		PetPoisonToxic:       petPT,
+		HumanEdible:          humanE,
+		PetEdible:            petE,
+		PlantTypeName:        plantTypeName,
+		PlantTypeDescription: plantTypeDesc,
+		LightNeedName:        lightNeedName,
+		LightNeedDescription: lightNeedDesc,
+		WaterNeedName:        waterNeedName,
+		WaterNeedDescription: waterNeedDesc,
+		WaterNeedDrySoilMM:   waterNeedDryMM,
+		WaterNeedDrySoilDays: waterNeedDryDays,
+	}
+}
+
+// viewPlantsFilter holds the optional catalog filters of a list request, unset filters match every plant
+type viewPlantsFilter struct {
+	petSafe               sql.NullBool
+	humanSafe             sql.NullBool
+	petEdible             sql.NullBool
+	humanEdible           sql.NullBool
+	plantType             sql.NullString
+	lightNeed             sql.NullString
+	waterModel            sql.NullString
+	minTemperatureCelsius sql.NullInt32
+	maxTemperatureCelsius sql.NullInt32
+	minHumidityPercent    sql.NullInt32
+	maxHumidityPercent    sql.NullInt32
+}
+
+// parses the catalog filter url query parameters
+func parseViewPlantsFilter(query url.Values) (viewPlantsFilter, error) {
+	var filter viewPlantsFilter
 
-	requestUserID, err := auth.ValidateJWT(accessTokenProvided, cfg.JWTSecret, cfg.sl)
+	boolFilters := []struct {
+		name  string
+		value *sql.NullBool
+	}{
+		{"petSafe", &filter.petSafe},
+		{"humanSafe", &filter.humanSafe},
+		{"petEdible", &filter.petEdible},
+		{"humanEdible", &filter.humanEdible},
+	}
+	for _, boolFilter := range boolFilters {
+		valueStr := query.Get(boolFilter.name)
+		if valueStr == "" {
+			continue
+		}
+		value, err := strconv.ParseBool(valueStr)
+		if err != nil {
+			return viewPlantsFilter{}, fmt.Errorf("%s must be either 'true' or 'false'", boolFilter.name)
+		}
+		*boolFilter.value = sql.NullBool{Bool: value, Valid: true}
+	}
+
+	if plantType := query.Get("plantType"); plantType != "" {
+		filter.plantType = sql.NullString{String: plantType, Valid: true}
+	}
+	if lightNeed := query.Get("lightNeed"); lightNeed != "" {
+		filter.lightNeed = sql.NullString{String: lightNeed, Valid: true}
+	}
+	if waterModel := query.Get("waterModel"); waterModel != "" {
+		if waterModel != "days" && waterModel != "mm" {
+			return viewPlantsFilter{}, errors.New("waterModel must be either 'days' or 'mm'")
+		}
+		filter.waterModel = sql.NullString{String: waterModel, Valid: true}
+	}
+
+	intFilters := []struct {
+		name  string
+		value *sql.NullInt32
+	}{
+		{"minTemperatureCelsius", &filter.minTemperatureCelsius},
+		{"maxTemperatureCelsius", &filter.maxTemperatureCelsius},
+		{"minHumidityPercent", &filter.minHumidityPercent},
+		{"maxHumidityPercent", &filter.maxHumidityPercent},
+	}
+	for _, intFilter := range intFilters {
+		valueStr := query.Get(intFilter.name)
+		if valueStr == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(valueStr, 10, 32)
+		if err != nil {
+			return viewPlantsFilter{}, fmt.Errorf("%s must be a whole number", intFilter.name)
+		}
+		*intFilter.value = sql.NullInt32{Int32: int32(value), Valid: true}
+	}
+
+	if filter.minTemperatureCelsius.Valid && filter.maxTemperatureCelsius.Valid &&
+		filter.minTemperatureCelsius.Int32 > filter.maxTemperatureCelsius.Int32 {
+		return viewPlantsFilter{}, errors.New("minTemperatureCelsius must not be more than maxTemperatureCelsius")
+	}
+	if filter.minHumidityPercent.Valid && filter.maxHumidityPercent.Valid &&
+		filter.minHumidityPercent.Int32 > filter.maxHumidityPercent.Int32 {
+		return viewPlantsFilter{}, errors.New("minHumidityPercent must not be more than maxHumidityPercent")
+	}
+
+	return filter, nil
+}
+
+// queries a page of catalog plants matching the filter in the requested order
+func (cfg *apiConfig) getViewPlantsPage(ctx context.Context, langCode sql.NullString, filter viewPlantsFilter, page pageRequest) ([]database.GetAllViewPlantsOrderedByCreatedRow, error) {
+	listParams := database.GetAllViewPlantsOrderedByCreatedParams{
+		LangCode:              langCode,
+		PetSafe:               filter.petSafe,
+		HumanSafe:             filter.humanSafe,
+		PetEdible:             filter.petEdible,
+		HumanEdible:           filter.humanEdible,
+		PlantType:             filter.plantType,
+		LightNeed:             filter.lightNeed,
+		WaterModel:            filter.waterModel,
+		MinTemperatureCelsius: filter.minTemperatureCelsius,
+		MaxTemperatureCelsius: filter.maxTemperatureCelsius,
+		MinHumidityPercent:    filter.minHumidityPercent,
+		MaxHumidityPercent:    filter.maxHumidityPercent,
+		CursorTime:            page.cursorTime,
+		CursorID:              page.cursorID,
+		RowLimit:              page.rowLimit(),
+	}
+	if page.sort == pageSortCreated {
+		return cfg.db.GetAllViewPlantsOrderedByCreated(ctx, listParams)
+	}
+
+	updatedRecords, err := cfg.db.GetAllViewPlantsOrderedByUpdated(ctx, database.GetAllViewPlantsOrderedByUpdatedParams(listParams))
 	if err != nil {
-		cfg.sl.Debug("Could not get user id from token", "error", err)
-		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
-		return
+		return nil, err
+	}
+	records := make([]database.GetAllViewPlantsOrderedByCreatedRow, 0, len(updatedRecords))
+	for _, record := range updatedRecords {
+		records = append(records, database.GetAllViewPlantsOrderedByCreatedRow(record))
 	}
+	return records, nil
+}
+
+// GET /api/v1/plants
+// paginated with 'limit', 'cursor', and 'sort' url query parameters,
+// and filtered with the optional url query parameters parsed by parseViewPlantsFilter
+func (cfg *apiConfig) usersViewPlantsListHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
 
 	requestedLangCode := r.URL.Query().Get("lang")
 	if requestedLangCode == "" {
-		cfg.sl.Debug("No lang code was provided in query params")
+		cfg.sl.DebugContext(r.Context(), "No lang code was provided in query params")
 		respondWithError(errors.New("no lang code was requested in query params"), http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 	langName, ok := LangCodes[requestedLangCode]
 	if !ok {
-		cfg.sl.Debug("Unknown language is being requested", "lang code", requestedLangCode)
+		cfg.sl.DebugContext(r.Context(), "Unknown language is being requested", "lang code", requestedLangCode)
 		respondWithError(errors.New("invalid lang code was requested"), http.StatusBadRequest, w, cfg.sl)
+		return
 	}
-	cfg.sl.Debug("Searching for all plants with common names in language", "lang code", requestedLangCode, "lang name", langName)
+	cfg.sl.DebugContext(r.Context(), "Searching for all plants with common names in language", "lang code", requestedLangCode, "lang name", langName)
 
-	// NOTE: if this section is providing errors, set the internal/database package to use sql.NullString
-	nullLangCode := sql.NullString{String: requestedLangCode, Valid: true}
-	plantRecords, err := cfg.db.GetAllViewPlantsOrderedByUpdated(r.Context(), nullLangCode)
+	page, err := parsePageRequest(r.URL.Query(), pageSortUpdated)
 	if err != nil {
-		cfg.sl.Debug("Could not view all plants in database with lang code", "error", err, "lang code", requestedLangCode)
-		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		cfg.sl.DebugContext(r.Context(), "Invalid pagination was provided in url query", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
 		return
 	}
 
-	if len(plantRecords) <= 0 {
-		cfg.sl.Debug("User successfully viewed empty plants view list", "user id", requestUserID)
-		respondWithJSON(http.StatusOK, plantRecords, w, cfg.sl)
+	filter, err := parseViewPlantsFilter(r.URL.Query())
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Invalid filter was provided in url query", "error", err)
+		respondWithError(err, http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	// NOTE: if this section is providing errors, set the internal/database package to use sql.NullString
+	nullLangCode := sql.NullString{String: requestedLangCode, Valid: true}
+	plantRecords, err := cfg.getViewPlantsPage(r.Context(), nullLangCode, filter, page)
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not view all plants in database with lang code", "error", err, "lang code", requestedLangCode)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
 		return
 	}
+	plantRecords, nextCursor := nextPage(plantRecords, page, func(record database.GetAllViewPlantsOrderedByCreatedRow) (time.Time, time.Time, uuid.UUID) {
+		return record.CreatedAt, record.UpdatedAt, record.PlantSpeciesID
+	})
 
 	// conversion to response records
-	var plantResponses []UserViewAllPlantInfoResponse
+	plantResponses := make([]UserViewAllPlantInfoResponse, 0)
 	for _, record := range plantRecords {
+		plantResponses = append(plantResponses, newUserViewAllPlantInfoResponse(record))
+	}
 
-		var commonNamesLangCode *string
-		var commonNames *string
-		var humanPT *bool
-		var petPT *bool
-		var humanE *bool
-		var petE *bool
-		var plantTypeName *string
-		var plantTypeDesc *string
-		var lightNeedName *string
-		var lightNeedDesc *string
-		var waterNeedName *string
-		var waterNeedDesc *string
-		var waterNeedDryMM *int32
-		var waterNeedDryDays *int32
-
-		// NOTE: if this section is providing errors, set the internal/database package to use sql.NullString
-		if record.LangCode.Valid {
-			commonNamesLangCode = &record.LangCode.String
-		}
-		if record.CommonNames.Valid {
-			commonNames = &record.CommonNames.String
-		}
+	pageResponse := PageResponse[UserViewAllPlantInfoResponse]{
+		Items:      plantResponses,
+		NextCursor: nextCursor,
+	}
 
-		if record.HumanPoisonToxic.Valid {
-			humanPT = &record.HumanPoisonToxic.Bool
-		}
-		if record.PetPoisonToxic.Valid {
-			petPT = &record.PetPoisonToxic.Bool
-		}
-		if record.HumanEdible.Valid {
-			humanE = &record.HumanEdible.Bool
-		}
-		if record.PetEdible.Valid {
-			petE = &record.PetEdible.Bool
-		}
-		if record.PlantTypeName.Valid {
-			plantTypeName = &record.PlantTypeName.String
-		}
-		if record.PlantTypeDescription.Valid {
-			plantTypeDesc = &record.PlantTypeDescription.String
-		}
-		if record.LightNeedName.Valid {
-			lightNeedName = &record.LightNeedName.String
-		}
-		if record.LightNeedDescription.Valid {
-			lightNeedDesc = &record.LightNeedDescription.String
-		}
-		if record.WaterNeedType.Valid {
-			waterNeedName = &record.WaterNeedType.String
-		}
-		if record.WaterNeedDescription.Valid {
-			waterNeedDesc = &record.WaterNeedDescription.String
-		}
-		if record.WaterNeedDrySoilMm.Valid {
-			waterNeedDryMM = &record.WaterNeedDrySoilMm.Int32
-		}
-		if record.WaterNeedDrySoilDays.Valid {
-			waterNeedDryDays = &record.WaterNeedDrySoilDays.Int32
-		}
+	respondWithJSON(http.StatusOK, pageResponse, w, cfg.sl)
+	cfg.sl.DebugContext(r.Context(), "User successfully listed all available plants", "user id", requestUserID)
+}
+
+// GET /api/v1/plants/search?q=&lang=
+// ranked by how well species or common names match, with an optional 'limit' url query parameter
+func (cfg *apiConfig) usersViewPlantsSearchHandler(w http.ResponseWriter, r *http.Request) {
+	requestUserID := userIDFromContext(r.Context())
+
+	searchQuery := strings.TrimSpace(r.URL.Query().Get("q"))
+	if searchQuery == "" {
+		cfg.sl.DebugContext(r.Context(), "No search query was provided in query params")
+		respondWithError(errors.New("no search query was provided in query params"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	if len(searchQuery) > searchMaxQueryLength {
+		cfg.sl.DebugContext(r.Context(), "Search query is too long", "length", len(searchQuery))
+		respondWithError(errors.New("search query must be at most 100 characters"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
 
-		response := UserViewAllPlantInfoResponse{
-			PlantSpeciesID:       record.PlantSpeciesID,
-			PlantSpeciesName:     record.PlantSpeciesName,
-			CommonNamesLangCode:  commonNamesLangCode,
-			CommonNames:          commonNames,
-			HumanPoisonToxic:     humanPT,
-			PetPoisonToxic:       petPT,
-			HumanEdible:          humanE,
-			PetEdible:            petE,
-			PlantTypeName:        plantTypeName,
-			PlantTypeDescription: plantTypeDesc,
-			LightNeedName:        lightNeedName,
-			LightNeedDescription: lightNeedDesc,
-			WaterNeedName:        waterNeedName,
-			WaterNeedDescription: waterNeedDesc,
-			WaterNeedDrySoilMM:   waterNeedDryMM,
-			WaterNeedDrySoilDays: waterNeedDryDays,
+	requestedLangCode := r.URL.Query().Get("lang")
+	if requestedLangCode == "" {
+		cfg.sl.DebugContext(r.Context(), "No lang code was provided in query params")
+		respondWithError(errors.New("no lang code was requested in query params"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+	if _, ok := LangCodes[requestedLangCode]; !ok {
+		cfg.sl.DebugContext(r.Context(), "Unknown language is being requested", "lang code", requestedLangCode)
+		respondWithError(errors.New("invalid lang code was requested"), http.StatusBadRequest, w, cfg.sl)
+		return
+	}
+
+	limit := int32(searchDefaultLimit)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit < 1 || parsedLimit > pageMaxLimit {
+			cfg.sl.DebugContext(r.Context(), "Invalid limit was provided in url query", "limit", limitStr)
+			respondWithError(errors.New("limit must be a number from 1 to 200"), http.StatusBadRequest, w, cfg.sl)
+			return
 		}
+		limit = int32(parsedLimit)
+	}
 
-		plantResponses = append(plantResponses, response)
+	plantRecords, err := cfg.db.SearchViewPlants(r.Context(), database.SearchViewPlantsParams{
+		LangCode: requestedLangCode,
+		Query:    searchQuery,
+		RowLimit: limit,
+	})
+	if err != nil {
+		cfg.sl.DebugContext(r.Context(), "Could not search plants in database", "error", err, "query", searchQuery)
+		respondWithError(err, http.StatusInternalServerError, w, cfg.sl)
+		return
+	}
+
+	// search rows have the same columns as the list rows
+	plantResponses := make([]UserViewAllPlantInfoResponse, 0)
+	for _, record := range plantRecords {
+		plantResponses = append(plantResponses, newUserViewAllPlantInfoResponse(database.GetAllViewPlantsOrderedByCreatedRow(record)))
 	}
 
 	respondWithJSON(http.StatusOK, plantResponses, w, cfg.sl)
-	cfg.sl.Debug("User successfully listed all available plants", "user id", requestUserID)
+	cfg.sl.DebugContext(r.Context(), "User successfully searched plants", "user id", requestUserID, "query", searchQuery, "results", len(plantResponses))
 }
@@ -1,25 +1,34 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/mail"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/lib/pq"
 	"github.com/nicholasss/plantae/internal/auth"
+	"github.com/nicholasss/plantae/internal/blob"
 	"github.com/nicholasss/plantae/internal/database"
+	"github.com/nicholasss/plantae/internal/migrate"
+	"github.com/nicholasss/plantae/internal/notify"
 )
 
-// Global logging level.
-var loggingLevel = slog.LevelDebug
+// Global logging level, info unless 'LOG_LEVEL' is set.
+var loggingLevel = new(slog.LevelVar)
 
 // === Global Variabes ===
 
@@ -220,15 +229,30 @@ var LangCodes = map[string]string{
 // === Global Types ===
 
 type apiConfig struct {
-	accessTokenDuration  time.Duration
-	refreshTokenDuration time.Duration
-	db                   *database.Queries
-	sl                   *slog.Logger
-	localAddr            string
-	platform             string
-	port                 string
-	JWTSecret            string
-	superAdminToken      string
+	accessTokenDuration        time.Duration
+	refreshTokenDuration       time.Duration
+	passwordResetTokenDuration time.Duration
+	passwordResetCooldown      time.Duration
+	emailVerifyTokenDuration   time.Duration
+	reminderInterval           time.Duration
+	refreshTokenPurgeInterval  time.Duration
+	photoMaxBytes              int64
+	db                         *database.Queries
+	sqlDB                      *sql.DB
+	sl                         *slog.Logger
+	notifiers                  map[string]notify.Notifier
+	mailer                     notify.Mailer
+	mails                      *mailQueue
+	blobs                      blob.BlobStore
+	migrator                   *migrate.Migrator
+	metrics                    *serverMetrics
+	startedAt                  time.Time
+	reminderHeartbeat          atomic.Int64 // unix nanoseconds of the last finished scan
+	localAddr                  string
+	platform                   string
+	port                       string
+	JWTSecret                  string
+	superAdminToken            string
 }
 
 // === Utilities Response Types ===
@@ -251,35 +275,99 @@ func platformNotProduction(cfg *apiConfig) bool {
 
 // check header for admin access token
 func (cfg *apiConfig) getUserIDFromToken(r *http.Request) (uuid.UUID, error) {
+	requestUserID, _, err := cfg.getSessionFromToken(r)
+	return requestUserID, err
+}
+
+// check header for access token, returning the user and the session it was issued for
+func (cfg *apiConfig) getSessionFromToken(r *http.Request) (uuid.UUID, uuid.UUID, error) {
 	requestAccessToken, err := auth.GetBearerToken(r.Header, cfg.sl)
 	if err != nil {
-		return uuid.UUID{}, err
+		return uuid.UUID{}, uuid.UUID{}, err
+	}
+
+	return auth.ValidateSessionJWT(requestAccessToken, cfg.JWTSecret, cfg.sl)
+}
+
+// errSessionRevoked is returned for access tokens whose session was revoked or has expired
+var errSessionRevoked = errors.New("session has been revoked, log in again")
+
+// returns errSessionRevoked unless the session still has a refresh token that can be used,
+// so revoking a session also cuts off the access tokens issued for it
+func (cfg *apiConfig) checkSessionActive(ctx context.Context, userID, sessionID uuid.UUID) error {
+	// access tokens issued before sessions were tracked cannot be checked
+	if sessionID == uuid.Nil {
+		return errSessionRevoked
 	}
 
-	requestUserID, err := auth.ValidateJWT(requestAccessToken, cfg.JWTSecret, cfg.sl)
+	activeParams := database.IsRefreshTokenFamilyActiveParams{
+		FamilyID: sessionID,
+		UserID:   userID,
+	}
+	active, err := cfg.db.IsRefreshTokenFamilyActive(ctx, activeParams)
 	if err != nil {
-		return uuid.UUID{}, err
+		return err
+	}
+	if !active {
+		return errSessionRevoked
+	}
+
+	return nil
+}
+
+// longest email address that can be delivered to
+const emailMaxLength = 254
+
+// returns the email trimmed and in lower case, as it is stored,
+// or an error when it is not a bare address such as 'craig@gmail.com'
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if len(email) > emailMaxLength {
+		return "", errors.New("email address is too long")
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", errors.New("invalid email address")
 	}
 
-	return requestUserID, nil
+	return email, nil
 }
 
-func loadAPIConfig() (*apiConfig, func() error, error) {
+// returns true when the error is from a unique constraint, such as an email that is already registered
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation"
+}
+
+// returns the address of the client, without the port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// checkSchema is false only for commands that change the schema themselves.
+// the returned function closes the database connection and the log file.
+func loadAPIConfig(checkSchema bool) (*apiConfig, func() error, error) {
 	// loading vars from .env
 	err := godotenv.Load(".env")
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// connect to log file and write out to it
-	logFile, err := os.OpenFile("log/server.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	// log to stdout and a rotating log file, unless 'LOG_OUTPUT' says otherwise
+	logWriter, closeLogOutput, err := openLogOutput()
 	if err != nil {
-		log.Fatalf("Unable to open log file: %q", err)
+		log.Fatalf("ERROR: %s, please check .env", err)
 	}
 
-	logWriter := io.MultiWriter(os.Stdout, logFile)
-	opts := slog.HandlerOptions{Level: loggingLevel}
-	sl := slog.New(slog.NewTextHandler(logWriter, &opts))
+	sl, err := newLogger(logWriter, os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		log.Fatalf("ERROR: %s, please check .env", err)
+	}
 
 	// connecting to database
 	dbURL := os.Getenv("GOOSE_DBSTRING")
@@ -304,15 +392,106 @@ func loadAPIConfig() (*apiConfig, func() error, error) {
 
 	// additional vars, configuration, and return
 	cfg := &apiConfig{
-		accessTokenDuration:  time.Hour * 2,
-		refreshTokenDuration: time.Hour * 24 * 30,
-		db:                   dbQueries,
-		sl:                   sl,
-		localAddr:            os.Getenv("LOCAL_ADDRESS"),
-		platform:             os.Getenv("PLATFORM"),
-		port:                 ":" + os.Getenv("PORT"),
-		JWTSecret:            os.Getenv("JWT_SECRET"),
-		superAdminToken:      os.Getenv("SUPER_ADMIN_TOKEN"),
+		accessTokenDuration:        time.Hour * 2,
+		refreshTokenDuration:       time.Hour * 24 * 30,
+		passwordResetTokenDuration: time.Hour,
+		passwordResetCooldown:      time.Minute * 5,
+		emailVerifyTokenDuration:   time.Hour * 24,
+		db:                         dbQueries,
+		sqlDB:                      db,
+		sl:                         sl,
+		localAddr:                  os.Getenv("LOCAL_ADDRESS"),
+		platform:                   os.Getenv("PLATFORM"),
+		port:                       ":" + os.Getenv("PORT"),
+		JWTSecret:                  os.Getenv("JWT_SECRET"),
+		superAdminToken:            os.Getenv("SUPER_ADMIN_TOKEN"),
+		startedAt:                  time.Now().UTC(),
+	}
+
+	// reminder scheduler, disabled with an interval of 0
+	reminderInterval := os.Getenv("REMINDER_INTERVAL")
+	if reminderInterval == "" {
+		cfg.reminderInterval = time.Minute * 15
+	} else {
+		cfg.reminderInterval, err = time.ParseDuration(reminderInterval)
+		if err != nil {
+			log.Fatal("ERROR: 'REMINDER_INTERVAL' is not a valid duration, please check .env")
+		}
+	}
+
+	// refresh token purger, disabled with an interval of 0
+	refreshTokenPurgeInterval := os.Getenv("REFRESH_TOKEN_PURGE_INTERVAL")
+	if refreshTokenPurgeInterval == "" {
+		cfg.refreshTokenPurgeInterval = time.Hour
+	} else {
+		cfg.refreshTokenPurgeInterval, err = time.ParseDuration(refreshTokenPurgeInterval)
+		if err != nil {
+			log.Fatal("ERROR: 'REFRESH_TOKEN_PURGE_INTERVAL' is not a valid duration, please check .env")
+		}
+	}
+
+	// notification channels
+	logNotifier := notify.NewLogNotifier(sl)
+	cfg.notifiers = map[string]notify.Notifier{
+		notifyChannelLog:     logNotifier,
+		notifyChannelWebhook: notify.NewWebhookNotifier(time.Second * 10),
+		notifyChannelEmail:   logNotifier,
+	}
+	cfg.mailer = logNotifier
+
+	smtpHost := os.Getenv("SMTP_HOST")
+	if smtpHost == "" {
+		sl.Warn("'SMTP_HOST' is empty, email notifications and account emails will only be logged")
+	} else {
+		smtpNotifier, err := notify.NewSMTPNotifier(
+			smtpHost,
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("SMTP_FROM"),
+		)
+		if err != nil {
+			log.Fatalf("ERROR: SMTP is misconfigured, please check .env: %q", err)
+		}
+		cfg.notifiers[notifyChannelEmail] = smtpNotifier
+		cfg.mailer = smtpNotifier
+	}
+	cfg.mails = newMailQueue(cfg)
+
+	// blob store for uploaded photos
+	photoMaxBytes := os.Getenv("PHOTO_MAX_BYTES")
+	if photoMaxBytes == "" {
+		cfg.photoMaxBytes = 10 << 20
+	} else {
+		cfg.photoMaxBytes, err = strconv.ParseInt(photoMaxBytes, 10, 64)
+		if err != nil || cfg.photoMaxBytes <= 0 {
+			log.Fatal("ERROR: 'PHOTO_MAX_BYTES' is not a positive number, please check .env")
+		}
+	}
+
+	switch blobStore := os.Getenv("BLOB_STORE"); blobStore {
+	case "", "local":
+		blobDir := os.Getenv("BLOB_LOCAL_DIR")
+		if blobDir == "" {
+			blobDir = "data/blobs"
+		}
+		cfg.blobs, err = blob.NewLocalStore(blobDir)
+		if err != nil {
+			log.Fatalf("ERROR: Unable to open local blob store: %q", err)
+		}
+	case "s3":
+		cfg.blobs, err = blob.NewS3Store(
+			os.Getenv("S3_ENDPOINT"),
+			os.Getenv("S3_REGION"),
+			os.Getenv("S3_BUCKET"),
+			os.Getenv("S3_ACCESS_KEY_ID"),
+			os.Getenv("S3_SECRET_ACCESS_KEY"),
+		)
+		if err != nil {
+			log.Fatalf("ERROR: S3 is misconfigured, please check .env: %q", err)
+		}
+	default:
+		log.Fatal("ERROR: 'BLOB_STORE' is unexpected value, please check .env")
 	}
 
 	// checking the config
@@ -334,9 +513,77 @@ func loadAPIConfig() (*apiConfig, func() error, error) {
 		log.Fatal("ERROR: 'SUPER_ADMIN_TOKEN' is empty, please check .env")
 	}
 
+	cfg.metrics = newServerMetrics(db, dbQueries, sl)
+
+	// database schema, checked against the migrations bundled into the binary
+	cfg.migrator, err = migrate.New(db, schemaFS, schemaDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if checkSchema {
+		autoMigrate := false
+		if autoMigrateStr := os.Getenv("AUTO_MIGRATE"); autoMigrateStr != "" {
+			autoMigrate, err = strconv.ParseBool(autoMigrateStr)
+			if err != nil {
+				log.Fatal("ERROR: 'AUTO_MIGRATE' is not 'true' or 'false', please check .env")
+			}
+		}
+
+		err = cfg.checkSchema(context.Background(), autoMigrate)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	cfg.sl.Info("Config is loaded")
 
-	return cfg, logFile.Close, nil
+	closeConfig := func() error {
+		return errors.Join(db.Close(), closeLogOutput())
+	}
+
+	return cfg, closeConfig, nil
+}
+
+// compares the applied migrations to the ones bundled into the binary, so the server does not
+// run queries against tables or columns that do not exist yet.
+// pending migrations are applied first when autoMigrate is set.
+func (cfg *apiConfig) checkSchema(ctx context.Context, autoMigrate bool) error {
+	if autoMigrate {
+		ran, err := cfg.migrator.Up(ctx)
+		for _, migration := range ran {
+			cfg.sl.InfoContext(ctx, "Applied migration", "migration", migration.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("auto migrating the database: %w", err)
+		}
+	}
+
+	statuses, err := cfg.migrator.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("reading the database schema version: %w", err)
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Name)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database schema is missing %d migrations (%s), run 'plantae migrate up' or set 'AUTO_MIGRATE=true'", len(pending), strings.Join(pending, ", "))
+	}
+
+	version, err := cfg.migrator.Version(ctx)
+	if err != nil {
+		return fmt.Errorf("reading the database schema version: %w", err)
+	}
+	if version > cfg.migrator.Latest() {
+		cfg.sl.WarnContext(ctx, "Database schema is newer than this server", "version", version, "expected version", cfg.migrator.Latest())
+	} else {
+		cfg.sl.InfoContext(ctx, "Database schema is up to date", "version", version)
+	}
+
+	return nil
 }
 
 // === Utility Response Handlers ===